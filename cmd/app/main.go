@@ -2,18 +2,33 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/auth"
 	"github.com/CAATHARSIS/courier-bot/internal/bot"
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
 	"github.com/CAATHARSIS/courier-bot/internal/config"
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
 	delivery "github.com/CAATHARSIS/courier-bot/internal/delivery/http"
+	"github.com/CAATHARSIS/courier-bot/internal/health"
+	"github.com/CAATHARSIS/courier-bot/internal/leader"
+	"github.com/CAATHARSIS/courier-bot/internal/lifecycle"
 	"github.com/CAATHARSIS/courier-bot/internal/logger"
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
 	"github.com/CAATHARSIS/courier-bot/internal/repository"
+	"github.com/CAATHARSIS/courier-bot/internal/service/admin"
 	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+	"github.com/CAATHARSIS/courier-bot/internal/service/inbox"
+	"github.com/CAATHARSIS/courier-bot/internal/service/presence"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
 	"github.com/CAATHARSIS/courier-bot/pkg/database"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
@@ -28,6 +43,21 @@ func main() {
 		log.Info("Debug messages are enable")
 	}
 
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Error("Failed to load timezone", "timezone", cfg.Timezone, "error", err)
+		os.Exit(1)
+	}
+
+	manager := lifecycle.NewManager(log)
+
+	shutdownTracing, err := tracing.Setup(cfg.TracingExporter, cfg.TracingFile, "courier-bot")
+	if err != nil {
+		log.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	manager.OnClose("tracing", shutdownTracing)
+
 	migrationDB, err := database.NewPostgresDB(cfg)
 	if err != nil {
 		log.Error("Failed to connect to database", "error", err)
@@ -42,18 +72,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := migrationDB.Close(); err != nil {
+		log.Error("Failed to close migration db", "error", err)
+	}
+
+	schemaVersion, err := database.LatestMigrationVersion()
+	if err != nil {
+		log.Error("Failed to determine schema version", "error", err)
+		os.Exit(1)
+	}
+
 	appDB, err := database.NewPostgresDB(cfg)
 	if err != nil {
 		log.Error("Failed to connect to database", "error", err)
-		if err := appDB.Close(); err != nil {
-			log.Error("Failed to close app db", "error", err)
-		}
 		os.Exit(1)
 	}
+	manager.OnClose("database", func(context.Context) error {
+		return appDB.Close()
+	})
 
 	repo := repository.NewRepository(appDB)
 
-	telegramBot, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
+	telegramBot, err := tgbotapi.NewBotAPIWithClient(cfg.TelegramBotToken, tgbotapi.APIEndpoint, metrics.NewTelegramClient())
 	if err != nil {
 		log.Error("Failed to create Telegram bot", "error", err)
 		os.Exit(1)
@@ -62,35 +102,135 @@ func main() {
 	telegramBot.Debug = cfg.Env == "dev"
 	log.Info("Authorized on account", "username", telegramBot.Self.UserName)
 
-	assignmentService := assignment.NewService(*repo, telegramBot, log)
+	callbackSecret := cfg.CallbackSecret
+	if callbackSecret == "" {
+		log.Warn("CALLBACK_SECRET is not set, signing callback data with the bot token")
+		callbackSecret = cfg.TelegramBotToken
+	}
+	callbackCodec := callback.NewCodec(callbackSecret)
+
+	assignmentService := assignment.NewService(*repo, telegramBot, manager, callbackCodec, customer.NewLogChannel(log), log)
+	assignmentService.UpdateIdleThreshold(cfg.CourierIdleThreshold)
+	assignmentService.UpdateLocation(location)
+	assignmentService.UpdateHoldLead(cfg.AssignmentHoldLead)
+	assignmentService.UpdateDailySummaryTime(cfg.DailySummaryAt)
+
+	notifications, err := customerNotifications(cfg, log)
+	if err != nil {
+		log.Error("Failed to set up customer notifications", "error", err)
+		os.Exit(1)
+	}
+	assignmentService.UpdateCustomerNotifications(notifications)
+
+	var reviewLinks *customer.ReviewLinks
+	switch {
+	case cfg.PublicURL != "" && cfg.ReviewSecret != "":
+		reviewLinks = customer.NewReviewLinks(cfg.PublicURL, cfg.ReviewSecret)
+	case cfg.PublicURL != "" || cfg.ReviewSecret != "":
+		log.Error("PUBLIC_URL and REVIEW_SECRET must be set together to enable reviews")
+		os.Exit(1)
+	}
+	assignmentService.UpdateReviews(assignment.Reviews{
+		Links:    reviewLinks,
+		Window:   cfg.ReviewWindow,
+		HalfLife: cfg.RatingHalfLife,
+	})
 
 	// assignmentManager := assignment.NewAssignmentManager(assignmentService, log)
 	// assignmentManager.StartCleanupWorker()
 
-	webhookHandler := delivery.NewWebhookHandler(assignmentService, cfg.WebhookSecret, log)
+	metrics.RegisterActiveCouriers(func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
 
-	keyboardManager := bot.NewkeyboardManager(log)
-	handlers := bot.NewHandlers(assignmentService, keyboardManager, log)
+		couriers, err := repo.Courier.GetActiveCouriers(ctx, time.Now().Add(-cfg.CourierIdleThreshold))
+		if err != nil {
+			log.Warn("Failed to count active couriers for metrics", "error", err)
+			return 0
+		}
 
-	botInstance := bot.NewTelegramBot(telegramBot, handlers, log)
+		return float64(len(couriers))
+	})
 
-	go botInstance.Start(context.Background())
+	inboxService := inbox.NewService(*repo, assignmentService, manager, cfg.InboxMaxAttempts, log)
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/webhook/order", func(w http.ResponseWriter, r *http.Request) {
-		webhookHandler.HandleNewOrderWebhook(context.Background(), w, r)
+	signatureVerifier := delivery.NewSignatureVerifier(cfg.WebhookSecrets, cfg.WebhookTimestampTolerance, repo.WebhookNonce, log)
+	webhookHandler := delivery.NewWebhookHandler(inboxService, signatureVerifier, log)
+
+	authService := auth.NewService(repo.APIToken, log)
+
+	adminService := admin.NewService(*repo, cfg.CourierIdleThreshold, location, log)
+	adminHandler := delivery.NewAdminHandler(adminService, assignmentService, log)
+
+	keyboardManager := bot.NewkeyboardManager(callbackCodec, log)
+	presenceTracker := presence.NewTracker(repo.Courier, cfg.LastSeenDebounce, log)
+	handlers := bot.NewHandlers(assignmentService, presenceTracker, keyboardManager, callbackCodec, log)
+
+	botInstance := bot.NewTelegramBot(telegramBot, handlers, manager, log)
+
+	elector := leader.NewElector(appDB, cfg.LeaderLockID, cfg.LeaderElectionInterval, log)
+	elector.Register("telegram-polling", botInstance.Start)
+	elector.Register("assignment-expiry", func(ctx context.Context) {
+		assignmentService.RunExpiryScheduler(ctx, cfg.AssignmentCheckInterval)
+	})
+	elector.Register("shift-auto-offline", func(ctx context.Context) {
+		assignmentService.RunAutoOffline(ctx, cfg.ShiftCheckInterval, cfg.ShiftIdleTimeout)
+	})
+	elector.Register("availability-reminders", func(ctx context.Context) {
+		assignmentService.RunAvailabilityReminders(ctx, cfg.AvailabilityReminderInterval)
+	})
+	elector.Register("held-assignments", func(ctx context.Context) {
+		assignmentService.RunHeldAssignments(ctx, cfg.HeldAssignmentInterval)
+	})
+	elector.Register("daily-summaries", func(ctx context.Context) {
+		assignmentService.RunDailySummaries(ctx, cfg.DailySummaryInterval)
 	})
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+	elector.Register("webhook-nonce-cleanup", func(ctx context.Context) {
+		signatureVerifier.RunNonceCleanup(ctx, cfg.WebhookNonceCleanup)
 	})
 
+	checker := health.NewChecker(cfg.HealthCheckTimeout, log)
+	checker.Add("database", health.DBPing(appDB))
+	checker.Add("migrations", health.MigrationVersion(appDB, schemaVersion))
+	checker.Add("telegram", health.Cached(time.Minute, func(ctx context.Context) error {
+		_, err := telegramBot.GetMe()
+		return err
+	}))
+	checker.Add("telegram-polling", health.Heartbeat(botInstance.LastPoll, 90*time.Second, elector.IsLeader))
+	checker.Add("assignment-scheduler", health.Heartbeat(assignmentService.LastExpiryRun, cfg.SchedulerMaxLag, elector.IsLeader))
+
+	manager.Run("leader-election", elector.Run)
+	manager.Run("inbox-worker", func(ctx context.Context) {
+		inboxService.RunWorker(ctx, cfg.InboxPollInterval)
+	})
+
+	mux := http.NewServeMux()
+	var orderWebhook http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		webhookHandler.HandleOrderWebhook(r.Context(), w, r)
+	}
+	if cfg.WebhookRequireToken {
+		orderWebhook = authService.Require(models.ScopeWebhookIngest, orderWebhook)
+	}
+	mux.HandleFunc("/webhook/order", tracing.Middleware("POST /webhook/order", orderWebhook))
+	adminHandler.Register(mux, authService)
+	if reviewLinks != nil {
+		delivery.NewReviewHandler(assignmentService, reviewLinks, log).Register(mux)
+	}
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /livez", checker.HandleLive)
+	mux.HandleFunc("GET /readyz", checker.HandleReady)
+	mux.HandleFunc("GET /health", checker.HandleReady)
+
 	server := &http.Server{
 		Addr:         cfg.HTTPAddr,
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return manager.Context()
+		},
 	}
+	manager.OnStopIntake("http-server", server.Shutdown)
 
 	go func() {
 		log.Info("Starting HTTP server", "addr", cfg.HTTPAddr)
@@ -106,12 +246,70 @@ func main() {
 
 	log.Info("Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	checker.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Error("Server forced to shutdown", "error", err)
+	if err := manager.Shutdown(ctx); err != nil {
+		log.Error("Shutdown finished with errors", "error", err)
 	}
 
 	log.Info("Server exited")
 }
+
+// customerNotifications собирает каналы уведомлений клиента из
+// CUSTOMER_NOTIFIERS. Канал без нужных настроек — ошибка запуска, а не
+// молча выключенные уведомления.
+func customerNotifications(cfg *config.Config, log *slog.Logger) (assignment.CustomerNotifications, error) {
+	notifications := assignment.CustomerNotifications{
+		Events: make(map[models.CustomerEvent]bool),
+	}
+
+	templates, err := customer.LoadTemplates(cfg.CustomerTemplatesFile)
+	if err != nil {
+		return notifications, err
+	}
+	notifications.Templates = templates
+
+	for _, value := range cfg.CustomerNotifyEvents {
+		event := models.CustomerEvent(value)
+		if !event.IsValid() {
+			return notifications, fmt.Errorf("unknown customer event %q in CUSTOMER_NOTIFY_EVENTS", value)
+		}
+		notifications.Events[event] = true
+	}
+
+	for _, channel := range cfg.CustomerNotifiers {
+		switch channel {
+		case customer.ChannelSMS:
+			if cfg.SMSGatewayURL == "" {
+				return notifications, fmt.Errorf("SMS_GATEWAY_URL is required for sms notifications")
+			}
+			notifications.Notifiers = append(notifications.Notifiers, customer.NewSMSNotifier(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.SMSSender))
+		case customer.ChannelEmail:
+			if cfg.SMTPAddr == "" || cfg.EmailFrom == "" {
+				return notifications, fmt.Errorf("SMTP_ADDR and EMAIL_FROM are required for email notifications")
+			}
+			notifications.Notifiers = append(notifications.Notifiers, customer.NewEmailNotifier(cfg.SMTPAddr, cfg.SMTPUser, cfg.SMTPPassword, cfg.EmailFrom))
+		case customer.ChannelTelegram:
+			if cfg.CustomerBotToken == "" {
+				return notifications, fmt.Errorf("CUSTOMER_BOT_TOKEN is required for telegram notifications")
+			}
+			api, err := tgbotapi.NewBotAPIWithClient(cfg.CustomerBotToken, tgbotapi.APIEndpoint, metrics.NewTelegramClient())
+			if err != nil {
+				return notifications, fmt.Errorf("failed to create customer bot: %v", err)
+			}
+			notifications.Notifiers = append(notifications.Notifiers, customer.NewTelegramNotifier(api))
+		case customer.ChannelFile:
+			notifications.Notifiers = append(notifications.Notifiers, customer.NewFileNotifier(cfg.CustomerNotifyFile, log))
+		default:
+			return notifications, fmt.Errorf("unknown customer notifier %q in CUSTOMER_NOTIFIERS", channel)
+		}
+	}
+
+	log.Info("Customer notifications configured", "channels", cfg.CustomerNotifiers, "events", cfg.CustomerNotifyEvents)
+
+	return notifications, nil
+}
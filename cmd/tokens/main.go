@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/auth"
+	"github.com/CAATHARSIS/courier-bot/internal/config"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/postgres"
+	"github.com/CAATHARSIS/courier-bot/pkg/database"
+
+	_ "github.com/lib/pq"
+)
+
+const usage = `Usage:
+  tokens create -name <name> -scopes <scope,...>
+  tokens list
+  tokens revoke -id <id>
+
+Scopes: read, dispatcher, admin, webhook-ingest
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	authService := auth.NewService(postgres.NewAPITokenRepository(db), log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	switch os.Args[1] {
+	case "create":
+		err = createToken(ctx, authService, os.Args[2:])
+	case "list":
+		err = listTokens(ctx, authService)
+	case "revoke":
+		err = revokeToken(ctx, authService, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func createToken(ctx context.Context, authService *auth.Service, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	name := flags.String("name", "", "human readable token owner")
+	scopesValue := flags.String("scopes", "", "comma separated list of scopes")
+	flags.Parse(args)
+
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	var scopes []models.Scope
+	for _, value := range strings.Split(*scopesValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			scopes = append(scopes, models.Scope(value))
+		}
+	}
+
+	plaintext, token, err := authService.CreateToken(ctx, *name, scopes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token %d (%s) created with scopes %v.\n", token.ID, token.Name, token.Scopes)
+	fmt.Println("Store it now, it will not be shown again:")
+	fmt.Println(plaintext)
+
+	return nil
+}
+
+func listTokens(ctx context.Context, authService *auth.Service) error {
+	tokens, err := authService.ListTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED\tLAST USED\tSTATUS")
+
+	for _, token := range tokens {
+		lastUsed := "never"
+		if token.LastUsedAt != nil {
+			lastUsed = token.LastUsedAt.Format(time.DateTime)
+		}
+
+		status := "active"
+		if token.IsRevoked() {
+			status = "revoked " + token.RevokedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%v\t%s\t%s\t%s\n",
+			token.ID, token.Name, token.Scopes, token.CreatedAt.Format(time.DateTime), lastUsed, status)
+	}
+
+	return w.Flush()
+}
+
+func revokeToken(ctx context.Context, authService *auth.Service, args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := flags.Int("id", 0, "token ID")
+	flags.Parse(args)
+
+	if *id <= 0 {
+		return fmt.Errorf("-id is required")
+	}
+
+	if err := authService.RevokeToken(ctx, *id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Token %d revoked.\n", *id)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type contextKey struct{}
+
+type errorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func TokenFromContext(ctx context.Context) (*models.APIToken, bool) {
+	token, ok := ctx.Value(contextKey{}).(*models.APIToken)
+	return token, ok
+}
+
+// Require пропускает запрос только с Bearer-токеном, у которого есть нужный
+// scope, и пишет каждый вызов с валидным токеном в api_audit_log.
+func (s *Service) Require(scope models.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		plaintext, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found {
+			s.log.Warn("API request without token", "method", r.Method, "path", r.URL.Path)
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		token, err := s.Authenticate(r.Context(), plaintext)
+		if err != nil {
+			if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrRevokedToken) {
+				s.log.Warn("API request with rejected token", "method", r.Method, "path", r.URL.Path, "error", err)
+				writeError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			s.log.Error("Failed to authenticate API request", "error", err)
+			writeError(w, http.StatusInternalServerError, "Failed to authenticate")
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		if token.HasScope(scope) {
+			next(recorder, r.WithContext(context.WithValue(r.Context(), contextKey{}, token)))
+		} else {
+			s.log.Warn("API token lacks scope", "tokenID", token.ID, "required", scope, "path", r.URL.Path)
+			writeError(recorder, http.StatusForbidden, "Forbidden")
+		}
+
+		s.audit(r, token, recorder.status, time.Since(started))
+	}
+}
+
+func (s *Service) audit(r *http.Request, token *models.APIToken, status int, duration time.Duration) {
+	ctx := context.WithoutCancel(r.Context())
+
+	entry := &models.APIAuditEntry{
+		TokenID:    &token.ID,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Status:     status,
+		RemoteAddr: r.RemoteAddr,
+		Duration:   duration,
+	}
+
+	if err := s.tokens.RecordAudit(ctx, entry); err != nil {
+		s.log.Error("Failed to record API audit entry", "tokenID", token.ID, "error", err)
+	}
+
+	if err := s.tokens.TouchLastUsed(ctx, token.ID); err != nil {
+		s.log.Error("Failed to update API token last use", "tokenID", token.ID, "error", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResponse{Success: false, Error: message})
+}
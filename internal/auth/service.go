@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+const tokenPrefix = "cbt_"
+
+var (
+	ErrInvalidToken = errors.New("invalid api token")
+	ErrRevokedToken = errors.New("api token revoked")
+	ErrNoScopes     = errors.New("at least one scope is required")
+)
+
+type Service struct {
+	tokens interfaces.APIToken
+	log    *slog.Logger
+}
+
+func NewService(tokens interfaces.APIToken, log *slog.Logger) *Service {
+	return &Service{
+		tokens: tokens,
+		log:    log,
+	}
+}
+
+// CreateToken выпускает новый токен. Открытое значение возвращается только
+// здесь, в базе хранится лишь его SHA-256.
+func (s *Service) CreateToken(ctx context.Context, name string, scopes []models.Scope) (string, *models.APIToken, error) {
+	if len(scopes) == 0 {
+		return "", nil, ErrNoScopes
+	}
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return "", nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	plaintext := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &models.APIToken{
+		Name:      name,
+		TokenHash: hashToken(plaintext),
+		Scopes:    scopes,
+	}
+
+	if err := s.tokens.Create(ctx, token); err != nil {
+		return "", nil, err
+	}
+
+	s.log.Info("API token created", "tokenID", token.ID, "name", name, "scopes", scopes)
+
+	return plaintext, token, nil
+}
+
+func (s *Service) ListTokens(ctx context.Context) ([]*models.APIToken, error) {
+	return s.tokens.List(ctx)
+}
+
+func (s *Service) RevokeToken(ctx context.Context, id int) error {
+	if err := s.tokens.Revoke(ctx, id); err != nil {
+		return err
+	}
+
+	s.log.Info("API token revoked", "tokenID", id)
+
+	return nil
+}
+
+func (s *Service) Authenticate(ctx context.Context, plaintext string) (*models.APIToken, error) {
+	if !strings.HasPrefix(plaintext, tokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := s.tokens.GetByHash(ctx, hashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if token.IsRevoked() {
+		return nil, ErrRevokedToken
+	}
+
+	return token, nil
+}
+
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,10 +2,18 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/lifecycle"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const ParseMode = "Markdown"
@@ -13,13 +21,21 @@ const ParseMode = "Markdown"
 type TelegramBot struct {
 	api      *tgbotapi.BotAPI
 	handlers *Handlers
+	spawner  lifecycle.Spawner
 	log      *slog.Logger
+	lastPoll atomic.Int64
 }
 
-func NewTelegramBot(api *tgbotapi.BotAPI, handlers *Handlers, log *slog.Logger) *TelegramBot {
+type pollResult struct {
+	updates []tgbotapi.Update
+	err     error
+}
+
+func NewTelegramBot(api *tgbotapi.BotAPI, handlers *Handlers, spawner lifecycle.Spawner, log *slog.Logger) *TelegramBot {
 	return &TelegramBot{
 		api:      api,
 		handlers: handlers,
+		spawner:  spawner,
 		log:      log,
 	}
 }
@@ -28,32 +44,107 @@ func (b *TelegramBot) Start(ctx context.Context) {
 	b.log.Info("Starting Telegram bot")
 
 	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	u.Timeout = 30
 
-	updates := b.api.GetUpdatesChan(u)
+	b.lastPoll.Store(time.Now().UnixNano())
 
 	for {
 		select {
 		case <-ctx.Done():
 			b.log.Info("Stopping Telegram bot")
 			return
-		case update := <-updates:
-			go b.handleUpdate(ctx, update)
+		default:
+		}
+
+		updates, err := b.getUpdates(ctx, u)
+		if ctx.Err() != nil {
+			// Необработанные апдейты не подтверждены offset'ом, Telegram
+			// отдаст их следующему лидеру.
+			b.log.Info("Stopping Telegram bot")
+			return
+		}
+
+		if err == nil {
+			b.lastPoll.Store(time.Now().UnixNano())
+		} else {
+			b.log.Error("Failed to get updates", "error", err)
+
+			select {
+			case <-ctx.Done():
+				b.log.Info("Stopping Telegram bot")
+				return
+			case <-time.After(3 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			if update.UpdateID >= u.Offset {
+				u.Offset = update.UpdateID + 1
+			}
+
+			b.spawner.Go(ctx, "telegram-update", func(ctx context.Context) {
+				b.handleUpdate(ctx, update)
+			})
 		}
 	}
 }
 
+// getUpdates не умеет отменяться по контексту, поэтому запрос идёт
+// в отдельной горутине, а при остановке его результат просто отбрасывается.
+func (b *TelegramBot) getUpdates(ctx context.Context, config tgbotapi.UpdateConfig) ([]tgbotapi.Update, error) {
+	result := make(chan pollResult, 1)
+
+	go func() {
+		updates, err := b.api.GetUpdates(config)
+		result <- pollResult{updates: updates, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		return r.updates, r.err
+	}
+}
+
+// LastPoll — время последнего успешного long polling запроса, нужно
+// для проверки готовности.
+func (b *TelegramBot) LastPoll() time.Time {
+	if nanos := b.lastPoll.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+
+	return time.Time{}
+}
+
 func (b *TelegramBot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
+	ctx, span := tracing.Start(ctx, "telegram.update", attribute.Int("telegram.update_id", update.UpdateID))
+	defer span.End()
+
+	if chat := update.FromChat(); chat != nil {
+		span.SetAttributes(tracing.ChatID(chat.ID))
+	}
+
+	if update.CallbackQuery != nil {
+		span.SetAttributes(attribute.String("telegram.callback_data", update.CallbackQuery.Data))
+	}
+
 	if update.Message != nil {
 		b.handlers.HandleMessage(ctx, b, update)
 	} else if update.CallbackQuery != nil {
 		b.handlers.HandleCallback(ctx, b, update)
+	} else if update.EditedMessage != nil && update.EditedMessage.Location != nil {
+		b.handlers.HandleLocation(ctx, b, update.EditedMessage)
 	}
 }
 
+// Новые сообщения в тихом режиме курьера уходят без звука, правки
+// сообщений звука не дают и так.
 func (b *TelegramBot) SendMessage(chatID int64, text string) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = ParseMode
+	msg.DisableNotification = b.handlers.quiet(chatID)
 	_, err := b.api.Send(msg)
 	return err
 }
@@ -61,24 +152,39 @@ func (b *TelegramBot) SendMessage(chatID int64, text string) error {
 func (b *TelegramBot) SendMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.ReplyKeyboardMarkup) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = ParseMode
+	msg.DisableNotification = b.handlers.quiet(chatID)
 	msg.ReplyMarkup = keyboard
 	_, err := b.api.Send(msg)
 	return err
 }
 
-func (b *TelegramBot) SendMessageWithInlineKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+func (b *TelegramBot) SendMessageWithInlineKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = ParseMode
+	msg.DisableNotification = b.handlers.quiet(chatID)
 	msg.ReplyMarkup = keyboard
-	_, err := b.api.Send(msg)
-	return err
+	sent, err := b.api.Send(msg)
+	return sent.MessageID, err
 }
 
 func (b *TelegramBot) EditMessageText(chatID int64, messageID int, text string) error {
 	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
 	editMsg.ParseMode = ParseMode
 	_, err := b.api.Send(editMsg)
-	return err
+	return ignoreNotModified(err)
+}
+
+// EditMessageWithInlineKeyboard заменяет текст и клавиатуру одним запросом.
+// Пустая клавиатура убирает кнопки из сообщения.
+func (b *TelegramBot) EditMessageWithInlineKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	if keyboard.InlineKeyboard == nil {
+		keyboard.InlineKeyboard = [][]tgbotapi.InlineKeyboardButton{}
+	}
+
+	editMsg := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	editMsg.ParseMode = ParseMode
+	_, err := b.api.Send(editMsg)
+	return ignoreNotModified(err)
 }
 
 func (b *TelegramBot) EditMessageReplyMarkup(chatID int64, messageID int, replyMarkup interface{}) error {
@@ -96,7 +202,7 @@ func (b *TelegramBot) EditMessageReplyMarkup(chatID int64, messageID int, replyM
 
 	editMsg := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, keyboard)
 	_, err := b.api.Send(editMsg)
-	return err
+	return ignoreNotModified(err)
 }
 
 func (b *TelegramBot) DeleteMessage(chatID int64, messageID int) {
@@ -126,16 +232,42 @@ func (b *TelegramBot) TestConnection() error {
 	return err
 }
 
+// SetDefaultCommands регистрирует меню команд: общий список на языке по
+// умолчанию и отдельные списки для каждого языка каталога.
 func (b *TelegramBot) SetDefaultCommands() error {
-	commands := []tgbotapi.BotCommand{
-		{Command: "start", Description: "Запустить бота"},
-		{Command: "help", Description: "🆘 Помощь"},
-		{Command: "orders", Description: "📋 Мои заказы"},
-		{Command: "status", Description: "ℹ️ Статус"},
-		{Command: "settings", Description: "⚙️ Настройки"},
+	config := tgbotapi.NewSetMyCommands(botCommands(i18n.For(i18n.Default))...)
+	if _, err := b.api.Request(config); err != nil {
+		return fmt.Errorf("failed to set default commands: %v", err)
+	}
+
+	for _, lang := range i18n.Languages {
+		config := tgbotapi.NewSetMyCommands(botCommands(i18n.For(lang))...)
+		config.LanguageCode = string(lang)
+		if _, err := b.api.Request(config); err != nil {
+			return fmt.Errorf("failed to set %s commands: %v", lang, err)
+		}
+	}
+
+	return nil
+}
+
+func botCommands(tr i18n.Localizer) []tgbotapi.BotCommand {
+	return []tgbotapi.BotCommand{
+		{Command: "start", Description: tr.T("command.start")},
+		{Command: "help", Description: tr.T("menu.help")},
+		{Command: "orders", Description: tr.T("menu.orders")},
+		{Command: "status", Description: tr.T("menu.status")},
+		{Command: "settings", Description: tr.T("menu.settings")},
+	}
+}
+
+// ignoreNotModified: Telegram отвечает ошибкой, если сообщение уже выглядит
+// так же, например при повторном нажатии кнопки. Для бота это успех.
+func ignoreNotModified(err error) error {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "message is not modified") {
+		return nil
 	}
 
-	config := tgbotapi.NewSetMyCommands(commands...)
-	_, err := b.api.Request(config)
 	return err
 }
@@ -0,0 +1,283 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository"
+	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+	"github.com/CAATHARSIS/courier-bot/internal/service/presence"
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Курьер принимает заказ и отмечает шаги кнопками карточки доставки,
+// которую прислал сервис: клиент узнаёт о принятии, о том, что заказ
+// забрали, и что курьер подъезжает.
+func TestDeliveryCardNotifiesCustomer(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := repository.NewRepository(db)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	courierID, chatID := dbtest.InsertCourier(t, db)
+	orderID := dbtest.InsertOrder(t, db)
+
+	ctx := context.Background()
+
+	offer := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now(),
+		ExpiredAt:             time.Now().Add(time.Hour),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+	if err := repo.OrderAssignment.Create(ctx, offer); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := customer.LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	telegram := newFakeTelegram(t)
+	codec := callback.NewCodec("secret")
+	notifier := &recordingNotifier{}
+
+	service := assignment.NewService(*repo, telegram.api, inlineSpawner{}, codec, customer.NewLogChannel(log), log)
+	service.UpdateCustomerNotifications(assignment.CustomerNotifications{
+		Notifiers: []assignment.CustomerNotifier{notifier},
+		Templates: templates,
+		Events: map[models.CustomerEvent]bool{
+			models.CustomerEventAccepted:    true,
+			models.CustomerEventPickedUp:    true,
+			models.CustomerEventApproaching: true,
+		},
+	})
+
+	km := NewkeyboardManager(codec, log)
+	h := NewHandlers(service, presence.NewTracker(repo.Courier, time.Minute, log), km, codec, log)
+	bot := &recordingBot{}
+
+	tap := func(keyboard tgbotapi.InlineKeyboardMarkup, action string, messageID int) {
+		t.Helper()
+
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				if button.CallbackData == nil {
+					continue
+				}
+
+				data, err := codec.Decode(*button.CallbackData)
+				if err != nil {
+					t.Fatalf("button %q: %v", button.Text, err)
+				}
+				if data.Action != action {
+					continue
+				}
+
+				h.HandleCallback(ctx, bot, tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+					ID:      "1",
+					From:    &tgbotapi.User{ID: chatID},
+					Message: &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: chatID}},
+					Data:    *button.CallbackData,
+				}})
+				return
+			}
+		}
+
+		t.Fatalf("no %q button on the keyboard", action)
+	}
+
+	tap(km.CreateAssignmentKeyboard(orderID), ActionAccept, 1)
+
+	cardID, card := telegram.lastKeyboard(t)
+	tap(card, StatusPicked, cardID)
+	tap(bot.lastKeyboard(t), StatusDelivering, cardID)
+
+	want := []models.CustomerEvent{
+		models.CustomerEventAccepted,
+		models.CustomerEventPickedUp,
+		models.CustomerEventApproaching,
+	}
+
+	notifications, err := repo.CustomerNotification.ListByOrderID(ctx, orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []models.CustomerEvent
+	for _, notification := range notifications {
+		if notification.Status != models.CustomerNotificationSent {
+			t.Errorf("%s notification status = %q, want %q", notification.Event, notification.Status, models.CustomerNotificationSent)
+		}
+		got = append(got, notification.Event)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("customer notifications = %v, want %v", got, want)
+	}
+
+	if len(notifier.messages) != len(want) {
+		t.Fatalf("notifier got %d messages, want %d", len(notifier.messages), len(want))
+	}
+}
+
+// inlineSpawner выполняет фоновую работу сразу, чтобы тест видел её
+// результат без ожидания.
+type inlineSpawner struct{}
+
+func (inlineSpawner) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	fn(ctx)
+}
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []customer.Message
+}
+
+func (n *recordingNotifier) Channel() string {
+	return "test"
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, recipient customer.Recipient, message customer.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+// fakeTelegram отвечает на запросы Bot API сервиса и запоминает
+// клавиатуры отправленных сообщений.
+type fakeTelegram struct {
+	api *tgbotapi.BotAPI
+
+	mu        sync.Mutex
+	messageID int
+	keyboards map[int]tgbotapi.InlineKeyboardMarkup
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	t.Helper()
+
+	f := &fakeTelegram{keyboards: make(map[int]tgbotapi.InlineKeyboardMarkup)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.messageID++
+
+		if markup := r.FormValue("reply_markup"); markup != "" {
+			var keyboard tgbotapi.InlineKeyboardMarkup
+			if err := json.Unmarshal([]byte(markup), &keyboard); err == nil && len(keyboard.InlineKeyboard) > 0 {
+				f.keyboards[f.messageID] = keyboard
+			}
+		}
+
+		fmt.Fprintf(w, `{"ok":true,"result":{"id":1,"is_bot":true,"message_id":%d,"chat":{"id":1}}}`, f.messageID)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := tgbotapi.NewBotAPIWithClient("token", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatalf("failed to create bot API: %v", err)
+	}
+	f.api = api
+
+	return f
+}
+
+func (f *fakeTelegram) lastKeyboard(t *testing.T) (int, tgbotapi.InlineKeyboardMarkup) {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last := 0
+	for id := range f.keyboards {
+		last = max(last, id)
+	}
+	if last == 0 {
+		t.Fatal("service sent no inline keyboard")
+	}
+
+	return last, f.keyboards[last]
+}
+
+// recordingBot — BotInterface обработчиков, запоминающий последнюю
+// inline-клавиатуру.
+type recordingBot struct {
+	keyboard *tgbotapi.InlineKeyboardMarkup
+}
+
+func (b *recordingBot) lastKeyboard(t *testing.T) tgbotapi.InlineKeyboardMarkup {
+	t.Helper()
+
+	if b.keyboard == nil {
+		t.Fatal("handlers sent no inline keyboard")
+	}
+
+	return *b.keyboard
+}
+
+func (b *recordingBot) SendMessage(chatID int64, text string) error {
+	return nil
+}
+
+func (b *recordingBot) SendMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.ReplyKeyboardMarkup) error {
+	return nil
+}
+
+func (b *recordingBot) SendMessageWithInlineKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error) {
+	b.keyboard = &keyboard
+	return 1, nil
+}
+
+func (b *recordingBot) EditMessageText(chatID int64, messageID int, text string) error {
+	return nil
+}
+
+func (b *recordingBot) EditMessageReplyMarkup(chatID int64, messageID int, replyMarkup interface{}) error {
+	return nil
+}
+
+func (b *recordingBot) EditMessageWithInlineKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	b.keyboard = &keyboard
+	return nil
+}
+
+func (b *recordingBot) DeleteMessage(chatID int64, messageID int) {}
+
+func (b *recordingBot) AnswerCallbackQuery(callbackQueryID string) error {
+	return nil
+}
+
+func (b *recordingBot) AnswerCallbackQueryWithText(callbackQueryID, text string) error {
+	return nil
+}
+
+func (b *recordingBot) GetMe() (*tgbotapi.User, error) {
+	return &tgbotapi.User{}, nil
+}
+
+func (b *recordingBot) TestConnection() error {
+	return nil
+}
+
+func (b *recordingBot) SetDefaultCommands() error {
+	return nil
+}
@@ -2,29 +2,49 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
 	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/navigation"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
 	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+	"github.com/CAATHARSIS/courier-bot/internal/service/presence"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type Handlers struct {
 	assignmentService *assignment.Service
+	presence          *presence.Tracker
 	keyboardManager   KeyboardManagerInterface
+	codec             *callback.Codec
+	router            *Router
+	langs             sync.Map // chatID -> i18n.Lang
+	quietChats        sync.Map // chatID -> bool
+	composing         sync.Map // chatID -> composeState
 	log               *slog.Logger
 }
 
-func NewHandlers(assignmentService *assignment.Service, keyboardManager KeyboardManagerInterface, log *slog.Logger) *Handlers {
-	return &Handlers{
+func NewHandlers(assignmentService *assignment.Service, presenceTracker *presence.Tracker, keyboardManager KeyboardManagerInterface, codec *callback.Codec, log *slog.Logger) *Handlers {
+	h := &Handlers{
 		assignmentService: assignmentService,
+		presence:          presenceTracker,
 		keyboardManager:   keyboardManager,
+		codec:             codec,
 		log:               log,
 	}
+
+	h.router = NewRouter(h.HandleUnknownCallback, h.recoverPanics, h.logCallbacks)
+	h.router.Handle(h.routes()...)
+
+	return h
 }
 
 func (h *Handlers) HandleMessage(ctx context.Context, bot BotInterface, update tgbotapi.Update) {
@@ -37,714 +57,1049 @@ func (h *Handlers) HandleMessage(ctx context.Context, bot BotInterface, update t
 
 	h.log.Info("Received message", "From", chatID, "Message", text)
 
-	switch text {
+	h.presence.Touch(ctx, chatID)
+	h.resolveLang(ctx, chatID, update.Message.From)
+	h.resolveQuiet(ctx, chatID)
+
+	if update.Message.Location != nil {
+		h.HandleLocation(ctx, bot, update.Message)
+		return
+	}
+
+	if h.relayComposed(ctx, bot, chatID, text) {
+		return
+	}
+
+	// Кнопки главного меню приходят текстом на языке курьера, поэтому
+	// сначала находим ключ кнопки, а маршрутизируем уже по нему.
+	command := text
+	if key, ok := i18n.Match(text, "menu.help", "menu.orders", "menu.status", "menu.settings"); ok {
+		command = key
+	}
+
+	switch command {
 	case "/start":
-		h.HandleStartCommand(bot, chatID, update.Message.From)
-	case "/help", "🆘 Помощь":
+		h.HandleStartCommand(ctx, bot, chatID, update.Message.From)
+	case "/help", "menu.help":
 		h.HandleHelpCommand(bot, chatID)
-	case "/orders", "📋 Мои заказы":
+	case "/orders", "menu.orders":
 		h.HandleMyOrdersCommand(ctx, bot, chatID)
-	case "/status", "ℹ️ Статус":
-		h.HandleStatusCommand(bot, chatID)
-	case "/settings", "⚙️ Настройки":
+	case "/status", "menu.status":
+		h.HandleStatusCommand(ctx, bot, chatID)
+	case "/settings", "menu.settings":
 		h.HandleSettingsCommand(bot, chatID)
 	default:
 		h.HandleUnknownCommand(bot, chatID)
 	}
 }
 
+// HandleLocation принимает геопозицию и обновления live location. Сами
+// координаты пока не сохраняются, они только отмечают курьера активным.
+func (h *Handlers) HandleLocation(ctx context.Context, bot BotInterface, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	h.presence.Touch(ctx, chatID)
+
+	if message.Location.LivePeriod > 0 && message.EditDate == 0 {
+		bot.SendMessage(chatID, h.tr(chatID).T("location.live_started"))
+	}
+}
+
 func (h *Handlers) HandleCallback(ctx context.Context, bot BotInterface, update tgbotapi.Update) {
 	if update.CallbackQuery == nil {
 		return
 	}
 
-	callback := update.CallbackQuery
+	query := update.CallbackQuery
 
 	var chatID int64
-	if callback.Message != nil {
-		chatID = callback.Message.Chat.ID
+	var messageID int
+	if query.Message != nil {
+		chatID = query.Message.Chat.ID
+		messageID = query.Message.MessageID
 	} else {
-		chatID = callback.From.ID
-		h.log.Warn("Callback without message, usting user ID as chatID", "userID", callback.From.ID, "callbackData", callback.Data)
+		chatID = query.From.ID
+		h.log.Warn("Callback without message, usting user ID as chatID", "userID", query.From.ID, "callbackData", query.Data)
 	}
 
-	callbackData := callback.Data
-
-	h.log.Info("Received callback", "chatID", chatID, "callbackData", callbackData, "messageID", callback.Message.MessageID)
+	h.log.Info("Received callback", "chatID", chatID, "callbackData", query.Data, "messageID", messageID)
 
 	if bot == nil {
 		h.log.Error("Bot interface is nil in callback handler")
 		return
 	}
 
-	if err := bot.AnswerCallbackQuery(callback.ID); err != nil {
+	h.presence.Touch(ctx, chatID)
+	h.resolveLang(ctx, chatID, query.From)
+	h.resolveQuiet(ctx, chatID)
+
+	data, err := h.codec.Decode(query.Data)
+	if err != nil {
+		h.rejectCallback(bot, chatID, query, err)
+		return
+	}
+
+	if err := bot.AnswerCallbackQuery(query.ID); err != nil {
 		h.log.Error("Failed to answer callback query", "error", err)
 	}
 
-	action := h.keyboardManager.GetActionFromCallback(callbackData)
+	h.router.Dispatch(ctx, &CallbackRequest{
+		Bot:       bot,
+		Query:     query,
+		ChatID:    chatID,
+		MessageID: messageID,
+		Data:      data,
+	})
+}
 
-	switch action {
-	case ActionAccept:
-		h.HandleAcceptOrder(ctx, bot, chatID, callbackData, callback.Message.MessageID)
-	case ActionReject:
-		h.HandleRejectOrder(ctx, bot, chatID, callbackData, callback.Message.MessageID)
-	case ActionComplete:
-		h.HandleCompleteOrder(ctx, bot, chatID, callbackData)
-	case ActionProblem:
-		h.HandleProblemOrder(bot, chatID, callbackData)
-	case ActionNavigate:
-		h.HandleNavigation(bot, chatID, callbackData)
-	case ActionCall:
-		h.HandleCallCustomer(bot, chatID, callbackData)
-	case ActionStatus:
-		h.HandleStatusUpdate(ctx, bot, chatID, callbackData)
-	case ActionSettings:
-		h.HandleSettings(ctx, bot, chatID, callbackData)
-	case ActionConfirm:
-		h.HandleConfirmation(bot, chatID, callbackData)
-	case ActionRefresh:
-		h.HandleRefresh(ctx, bot, chatID, callbackData)
-	case ActionMenu:
-		h.HandleMenu(bot, chatID, callbackData)
-	case ActionOrderDetails:
-		h.HandleOrderDetails(ctx, bot, chatID, callbackData)
-	case ActionBackToOrder:
-		h.HandleBackToOrder(ctx, bot, chatID, callbackData)
-	case ActionConfirmDelivery:
-		h.HandleDeliveryConfirmation(ctx, bot, chatID, callbackData)
-	case ActionCancelDelivery:
-		h.HandleDeliveryCancel(ctx, bot, chatID, callbackData)
-	case ActionChangeWorkmode:
-		h.HandleChangeWorkmode(ctx, bot, chatID, callbackData)
-	default:
-		h.HandleUnknownCommand(bot, chatID)
-	}
+func (h *Handlers) HandleUnknownCallback(ctx context.Context, req *CallbackRequest) {
+	h.log.Warn("No route for callback", "chatID", req.ChatID, "action", req.Data.Action, "ids", req.Data.IDs, "arg", req.Data.Arg)
+	h.HandleUnknownCommand(req.Bot, req.ChatID)
 }
 
 // COMMAND HANDLERS
 
-func (h *Handlers) HandleStartCommand(bot BotInterface, chatID int64, user *tgbotapi.User) {
+func (h *Handlers) HandleStartCommand(ctx context.Context, bot BotInterface, chatID int64, user *tgbotapi.User) {
+	tr := h.tr(chatID)
+
 	var message string
 
-	if !h.assignmentService.CheckCourierByChatID(context.Background(), chatID) {
+	if !h.assignmentService.CheckCourierByChatID(ctx, chatID) {
 		newCourier := &models.Courier{
 			TelegramID: user.ID,
 			ChatID:     chatID,
 			Name:       user.FirstName + " " + user.LastName,
 			Phone:      "",
-			IsActive:   true,
+			IsActive:   false,
+			Language:   string(tr.Lang()),
 		}
 
-		h.assignmentService.CreateCourier(context.Background(), newCourier)
-
-		message = fmt.Sprintf(
-			"Добро пожаловать, %s!\n\n"+
-				"Вы успешно зарегестрированы как курьер.\n"+
-				"Я - бот для курьеров доставки. Буду сопровождать вас в вашей работе.\n\n"+
-				"*Основные команды:*\n"+
-				"• 📋 Мои заказы - посмотреть активные заказы\n"+
-				"• ℹ️ Статус - информация о вашем статусе\n"+
-				"• ⚙️ Настройки - настройки уведомлений\n"+
-				"• 🆘 Помощь - справка по использованию\n\n"+
-				"Ожидайте новые заказы!",
-			user.FirstName,
-		)
+		h.assignmentService.CreateCourier(ctx, newCourier)
+
+		message = tr.T("start.new", user.FirstName) + tr.T("start.intro") + tr.T("start.new_hint")
 	} else {
-		message = fmt.Sprintf(
-			"С возвращением, %s!\n\n"+
-				"Я - бот для курьеров доставки. Буду сопровождать вас в вашей работе.\n\n"+
-				"*Основные команды:*\n"+
-				"• 📋 Мои заказы - посмотреть активные заказы\n"+
-				"• ℹ️ Статус - информация о вашем статусе\n"+
-				"• ⚙️ Настройки - настройки уведомлений\n"+
-				"• 🆘 Помощь - справка по использованию\n\n"+
-				"Ожидайте новые заказы!",
-			user.FirstName,
-		)
-	}
-
-	keyboard := h.keyboardManager.CreateMainMenuKeyboard()
+		message = tr.T("start.back", user.FirstName) + tr.T("start.intro") + tr.T("start.back_hint")
+	}
+
+	keyboard := h.keyboards(chatID).CreateMainMenuKeyboard()
 	bot.SendMessageWithKeyboard(chatID, message, keyboard)
 }
 
 func (h *Handlers) HandleHelpCommand(bot BotInterface, chatID int64) {
-	message := "🆘 *Помощь по боту*\n\n" +
-		"*Как работает бот:*\n" +
-		"• 📦 Вы получаете уведомления о новых заказах\n" +
-		"• ✅ Можете принять или отклонить заказ\n" +
-		"• 🗺️ Использовать навигацию к адресу доставки\n" +
-		"• 📞 Связаться с клиентом\n" +
-		"• 🏁 Отмечать статусы доставки\n\n" +
-		"*Основные кнопки:*\n" +
-		"• ✅ Принять - взять заказ в работу\n" +
-		"• ❌ Отклонить - отказаться от заказа\n" +
-		"• 🗺️ Построить маршрут - открыть навигацию\n" +
-		"• 📞 Позвонить - связаться с клиентом\n" +
-		"• 🏁 Доставка завершена - отметить выполнение\n\n" +
-		"Если возникли проблемы, обратитесь к администратору."
-
-	bot.SendMessage(chatID, message)
+	bot.SendMessage(chatID, h.tr(chatID).T("help.text"))
 }
 
 func (h *Handlers) HandleMyOrdersCommand(ctx context.Context, bot BotInterface, chatID int64) {
-	h.log.Info("Fetching active orders for courier", "ChatID", chatID)
+	h.showOrderList(ctx, bot, chatID, models.CourierOrdersActive, 0, 0)
+}
 
-	orders, err := h.assignmentService.GetActiveOrdersByCourier(ctx, chatID)
+func (h *Handlers) HandleStatusCommand(ctx context.Context, bot BotInterface, chatID int64) {
+	tr := h.tr(chatID)
+
+	courier, err := h.assignmentService.GetCourierByChatID(ctx, chatID)
 	if err != nil {
-		h.log.Error("Failed to get active orders for courier", "chatID", chatID, "Error", err)
-		bot.SendMessage(chatID, "❌ Не удалось загрузить список заказов. Попробуйте позже.")
+		bot.SendMessage(chatID, tr.T("status.load_failed"))
 		return
 	}
 
-	if len(orders) == 0 {
-		message := "📋 *Ваши активные заказы*\n\n" +
-			"На данный момент у вас нет активных заказов.\n\n" +
-			"💡 *Совет:* Убедитесь, что ваш статус 'Активен' в настройках.\n" +
-			"Новые заказы будут приходить автоматически!"
-		bot.SendMessage(chatID, message)
+	stats, err := h.assignmentService.GetCourierStats(ctx, chatID, models.StatsPeriodToday)
+	if err != nil {
+		h.log.Error("Failed to get courier stats", "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, tr.T("status.load_failed"))
 		return
 	}
 
-	orderItems := h.convertOrdersToOrderListItem(ctx, orders)
-	message := h.formatOrdersSummary(orderItems)
-	keyboard := h.keyboardManager.CreateOrderListKeyboard(orderItems)
+	orders, err := h.assignmentService.GetActiveOrdersByCourier(ctx, chatID)
+	if err != nil {
+		h.log.Error("Failed to get active orders for courier", "chatID", chatID, "error", err)
+	}
 
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
-}
+	state, err := h.assignmentService.GetShiftState(ctx, courier.ChatID)
+	if err != nil {
+		h.log.Error("Failed to get shift state", "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, tr.T("status.load_failed"))
+		return
+	}
+
+	readyKey := "status.ready"
+	switch {
+	case state.Shift == nil:
+		readyKey = "status.no_shift"
+	case state.Break != nil:
+		readyKey = "status.on_break"
+	}
 
-// ЭТО ЗАГЛУШКА, ЙОУ
-func (h *Handlers) HandleStatusCommand(bot BotInterface, chatID int64) {
-	message := "ℹ️ *Ваш статус*\n\n" +
-		"• 📱 Статус: *Активен*\n" +
-		"• 🚗 Доступен для заказов: *Да*\n" +
-		"• 📊 Заказов сегодня: *0*\n" +
-		"• ⭐ Рейтинг: *Ты заглушечка*\n\n" +
-		"Вы готовы принимать новые заказы! 🚀"
+	message := tr.T(
+		"status.text",
+		h.formatShiftStatus(tr, state),
+		len(orders),
+		stats.Deliveries,
+		stats.Earnings,
+		h.formatRating(tr, stats.Rating),
+		tr.T(readyKey),
+	)
 
-	bot.SendMessage(chatID, message)
+	bot.SendMessageWithInlineKeyboard(chatID, message, h.keyboards(chatID).CreateStatsShortcutKeyboard())
 }
 
 func (h *Handlers) HandleSettingsCommand(bot BotInterface, chatID int64) {
-	message := "⚙️ *Настройки*\n\n" +
-		"Выберите настройку для изменения:"
-
-	keyboard := h.keyboardManager.CreateSettingsKeyboard()
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	keyboard := h.keyboards(chatID).CreateSettingsKeyboard()
+	bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("settings.title"), keyboard)
 }
 
 func (h *Handlers) HandleUnknownCommand(bot BotInterface, chatID int64) {
-	message := "❓ Неизвестная команда\n\n" +
-		"Используйте кнопки меню или введите /help для справки."
-
-	bot.SendMessage(chatID, message)
+	bot.SendMessage(chatID, h.tr(chatID).T("unknown.command"))
 }
 
 // CALLBACK HANDLERS
 
-func (h *Handlers) HandleAcceptOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string, messageID int) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from callback", "CallbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка обработки заказа")
-		return
-	}
-
+func (h *Handlers) HandleAcceptOrder(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int) {
 	h.log.Info("Courier accepting order", "chatID", chatID, "orderID", orderID)
 
-	bot.AnswerCallbackQueryWithText("", "✅ Принимаем заказ...")
+	bot.AnswerCallbackQueryWithText("", h.tr(chatID).T("order.accepting"))
 
-	err = h.assignmentService.HandleCourierResponse(ctx, chatID, orderID, true)
+	err := h.assignmentService.HandleCourierResponse(ctx, chatID, orderID, true)
 	if err != nil {
 		h.log.Error("Failed to accept order by courier", "orderID", orderID, "chatID", chatID, "error", err)
-		bot.SendMessage(chatID, "❌ Не удалось принять заказ. Попробуйте позже.")
+		bot.SendMessage(chatID, h.tr(chatID).T("order.accept_failed"))
 		return
 	}
 
 	bot.DeleteMessage(chatID, messageID)
 }
 
-func (h *Handlers) HandleRejectOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string, messageID int) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from callback", "CallbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка обработки заказа")
-		return
-	}
-
+func (h *Handlers) HandleRejectOrder(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int) {
 	h.log.Info("Courier rejecting order", "chatID", chatID, "orderID", orderID)
 
 	bot.EditMessageReplyMarkup(chatID, messageID, nil)
 
-	err = h.assignmentService.HandleCourierResponse(ctx, chatID, orderID, false)
+	err := h.assignmentService.HandleCourierResponse(ctx, chatID, orderID, false)
 	if err != nil {
 		h.log.Error("Failed to reject order by courier", "orderID", orderID, "chatID", chatID, "error", err)
-		bot.SendMessage(chatID, "❌ Не удалось отклонить заказ. Попробуйте позже.")
+		bot.SendMessage(chatID, h.tr(chatID).T("order.reject_failed"))
 		return
 	}
 
 	bot.DeleteMessage(chatID, messageID)
 }
 
-func (h *Handlers) HandleCompleteOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from callback", "CallbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка обработки заказа")
+func (h *Handlers) HandleCompleteOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
+
+	if err := h.assignmentService.UpdateOrderStatusReceived(ctx, orderID, true); err != nil {
+		h.log.Error("Failed to mark order as delivered", "orderID", orderID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("delivery.update_failed"))
 		return
 	}
 
-	message := fmt.Sprintf(
-		"✅ *Заказ #%d завершен!*\n\n"+
-			"Поздравляем с успешной доставкой!",
-		orderID,
-	)
+	h.closeOrderCard(bot, chatID, messageID, h.tr(chatID).T("order.completed", orderID))
+	h.log.Info("Order marked as completed by courier", "orderID", orderID, "chatID", chatID)
+}
 
-	bot.SendMessage(chatID, message)
+func (h *Handlers) HandleProblemOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
 
-	h.assignmentService.UpdateOrderStatusReceived(ctx, orderID, true)
-	h.log.Info("Order marked as completed by courier", "orderID", orderID, "chatID", chatID)
+	keyboard := h.keyboards(chatID).CreateProblemKeyboard(orderID)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, h.tr(chatID).T("order.problem", orderID), keyboard)
 }
 
-func (h *Handlers) HandleProblemOrder(bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from callback", "Callback", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка обработки заказа")
+// HandleNavigation присылает ссылки на маршрут до адреса из заказа: в
+// выбранном курьером приложении, а пока он не выбрал — во всех.
+func (h *Handlers) HandleNavigation(bot BotInterface, chatID int64, courier *models.Courier, order *models.Order) {
+	tr := h.tr(chatID)
+
+	address := order.FullAddress()
+	if address == "" {
+		bot.SendMessage(chatID, tr.T("navigation.no_address"))
 		return
 	}
 
-	message := fmt.Sprintf(
-		"🚨 *Проблема с заказом #%d*\n\n"+
-			"Выберите тип проблемы:",
-		orderID,
-	)
+	message := tr.T("navigation.text", order.ID, tgbotapi.EscapeText(ParseMode, address))
+
+	apps := navigation.Apps
+	if app, ok := navigation.Parse(courier.MapApp); ok {
+		apps = []navigation.App{app}
+	} else {
+		message += tr.T("navigation.choose_hint")
+	}
 
-	keyboard := h.keyboardManager.CreateProblemKeyboard(orderID)
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	bot.SendMessageWithInlineKeyboard(chatID, message, h.keyboards(chatID).CreateNavigationKeyboard(apps, address))
 }
 
-func (h *Handlers) HandleNavigation(bot BotInterface, chatID int64, callbackData string) {
-	parts := strings.Split(callbackData, "_")
-	if len(parts) < 3 {
-		bot.SendMessage(chatID, "❌ Не удалось получить адрес для навигации")
+// HandleCallCustomer показывает номер клиента, пока заказ в работе.
+// Сообщение с номером регистрируется в заказе и удаляется при его закрытии.
+func (h *Handlers) HandleCallCustomer(ctx context.Context, bot BotInterface, chatID int64, order *models.Order) {
+	phone, err := h.assignmentService.RevealCustomerPhone(ctx, chatID, order.ID)
+	if err != nil {
+		h.contactFailed(bot, chatID, order.ID, err)
 		return
 	}
 
-	orderID := parts[1]
-	address := strings.Join(parts[2:], " ")
+	messageID, err := bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("call.text", order.ID, phone), h.keyboards(chatID).CreatePhoneKeyboard(order.ID))
+	if err != nil {
+		h.log.Error("Failed to send customer phone", "orderID", order.ID, "chatID", chatID, "error", err)
+		return
+	}
 
-	message := fmt.Sprintf(
-		"🗺️ *Навигация для заказа #%s*\n\n"+
-			"*Адрес:* %s\n\n"+
-			"Откройте приложение навигации для построения маршурута.",
-		orderID,
-		address,
-	)
+	h.assignmentService.TrackOrderMessage(ctx, order.ID, chatID, messageID, models.OrderMessageContact)
+}
+
+func (h *Handlers) HandleContact(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
 
-	bot.SendMessage(chatID, message)
+	keyboard := h.keyboards(chatID).CreateContactKeyboard(orderID)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, h.tr(chatID).T("contact.menu", orderID), keyboard)
 }
 
-func (h *Handlers) HandleCallCustomer(bot BotInterface, chatID int64, callbackData string) {
-	parts := strings.Split(callbackData, "_")
-	if len(parts) < 3 {
-		bot.SendMessage(chatID, "❌ Не удалось получить номер телефона")
+func (h *Handlers) HandleContactTemplate(ctx context.Context, bot BotInterface, chatID int64, orderID int, template string) {
+	if !slices.Contains(contactTemplates, template) {
+		h.log.Warn("Unknown contact template", "template", template)
+		bot.SendMessage(chatID, h.tr(chatID).T("order.processing_error"))
 		return
 	}
 
-	orderID := parts[1]
-	phone := parts[2]
+	text := i18n.For(i18n.Default).T("contact.message." + template)
+	h.sendToCustomer(ctx, bot, chatID, orderID, template, text)
+}
 
-	message := fmt.Sprintf(
-		"📞 *Звонок клиенту заказа #%s*\n\n"+
-			"*Телефон:* `%s`\n\n"+
-			"Нажмите на номер для звонка.",
-		orderID,
-		phone,
-	)
+// HandleContactCompose ждёт от курьера текст для клиента: следующее
+// сообщение в чате уйдёт клиенту, если курьер не передумает.
+func (h *Handlers) HandleContactCompose(bot BotInterface, chatID int64, orderID int) {
+	h.composing.Store(chatID, composeState{OrderID: orderID, Until: time.Now().Add(contactComposeTTL)})
 
-	bot.SendMessage(chatID, message)
+	bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("contact.compose_prompt", orderID), h.keyboards(chatID).CreateComposeKeyboard(orderID))
 }
 
-func (h *Handlers) HandleStatusUpdate(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	h.log.Info("Processing status update from courier", "chatID", chatID, "callbackData", callbackData)
+func (h *Handlers) HandleContactCancel(bot BotInterface, chatID int64) {
+	h.composing.Delete(chatID)
+	bot.SendMessage(chatID, h.tr(chatID).T("contact.compose_cancelled"))
+}
 
-	action, orderID, err := h.parseStatusCallback(callbackData)
-	if err != nil {
-		h.log.Error("Failed to parse status callback", "chatID", chatID, "callbackData", callbackData, "error", err)
-		bot.SendMessage(chatID, "❌ Ошибка обработки команды, попробуйте еще раз.")
-		return
-	}
+func (h *Handlers) HandleStatusUpdate(ctx context.Context, bot BotInterface, chatID int64, action string, order *models.Order, messageID int) {
+	h.log.Info("Processing status update from courier", "chatID", chatID, "orderID", order.ID, "action", action)
 
-	order, err := h.assignmentService.GetOrderByID(ctx, orderID)
-	if err != nil {
-		h.log.Error("Failed to get order", "orderID", orderID, "error", err)
-		bot.SendMessage(chatID, "❌ Не удалось найти заказ.")
-		return
-	}
-
-	courier, err := h.assignmentService.GetCourierByChatID(ctx, chatID)
-	if err != nil {
-		h.log.Error("Failed to get courier", "chatID", chatID, "error", err)
-		bot.SendMessage(chatID, "❌ Ошибка проверки доступа")
+	switch action {
+	case StatusPicked:
+		h.handleOrderPicked(ctx, bot, chatID, order, messageID)
+	case StatusDelivering:
+		h.handleOrderDelivering(ctx, bot, chatID, order, messageID)
+	case StatusArrived:
+		h.handleOrderArrived(ctx, bot, chatID, order, messageID)
+	case StatusDelivered:
+		h.handleOrderDelivered(ctx, bot, chatID, order.ID, messageID)
+	default:
+		bot.SendMessage(chatID, h.tr(chatID).T("error.unknown_action"))
 		return
 	}
+}
 
-	if order.CourierID == nil || *order.CourierID != courier.ID {
-		bot.SendMessage(chatID, "❌ Этот заказ не назначен вам.")
+func (h *Handlers) HandleSettings(ctx context.Context, bot BotInterface, chatID int64, data callback.Data) {
+	if data.Action == SettingsLanguage && data.Arg != "" {
+		h.changeLanguage(ctx, bot, chatID, data.Arg)
 		return
 	}
 
-	switch action {
-	case "status_picked":
-		h.handleOrderPicked(bot, chatID, orderID, order)
-	case "status_delivering":
-		h.handleOrderDelivering(bot, chatID, orderID, order)
-	case "status_arrived":
-		h.handleOrderArrived(bot, chatID, orderID, order)
-	case "status_delivered":
-		h.handleOrderDelivered(ctx, bot, chatID, orderID)
-	default:
-		bot.SendMessage(chatID, "❌ Неизвестное действие.")
+	if data.Action == SettingsMaps && data.Arg != "" {
+		h.changeMapApp(ctx, bot, chatID, data.Arg)
 		return
 	}
-}
 
-func (h *Handlers) HandleSettings(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	switch callbackData {
-	case SettingsNotifications:
-		bot.SendMessage(chatID, "🔔 Настройки уведомлений...\nУбрать может э")
+	switch data.Action {
 	case SettingsWorkmode:
-		courier, err := h.assignmentService.GetCourierByChatID(ctx, chatID)
+		state, err := h.assignmentService.GetShiftState(ctx, chatID)
 		if err != nil {
-			bot.SendMessage(chatID, "❌ Ошибка доступа")
+			h.log.Error("Failed to get shift state", "chatID", chatID, "error", err)
+			bot.SendMessage(chatID, h.tr(chatID).T("error.access"))
+			return
 		}
 
-		isActiveText := "Активен"
-		if !courier.IsActive {
-			isActiveText = "Не активен"
+		tr := h.tr(chatID)
+		msg := tr.T("workmode.current", h.formatShiftStatus(tr, state))
+		if state.Shift != nil {
+			msg += tr.T("workmode.started_at", state.Shift.StartedAt.In(h.assignmentService.Now().Location()).Format("15:04"))
 		}
 
-		msg := fmt.Sprintf("⚙️ *Текущий статус: %s*", isActiveText)
-
-		keyboard := h.keyboardManager.CreateChangeWorkmodeKeyboard(courier.IsActive)
+		keyboard := h.keyboards(chatID).CreateChangeWorkmodeKeyboard(state.Shift != nil, state.Break != nil)
 		bot.SendMessageWithInlineKeyboard(chatID, msg, keyboard)
+	case SettingsSchedule:
+		h.showSchedule(ctx, bot, chatID)
+	case SettingsLanguage:
+		bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("language.title"), h.keyboards(chatID).CreateLanguageKeyboard())
+	case SettingsMaps:
+		courier, err := h.assignmentService.GetCourierByChatID(ctx, chatID)
+		if err != nil {
+			bot.SendMessage(chatID, h.tr(chatID).T("error.access"))
+			return
+		}
+
+		current, _ := navigation.Parse(courier.MapApp)
+		bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("maps.title"), h.keyboards(chatID).CreateMapAppKeyboard(current))
 	case SettingsContacts:
-		bot.SendMessage(chatID, "Контактная информация...\nУбрать может э")
+		bot.SendMessage(chatID, h.tr(chatID).T("settings.contacts"))
 	default:
 		h.HandleSettingsCommand(bot, chatID)
 	}
 }
 
-func (h *Handlers) HandleConfirmation(bot BotInterface, chatID int64, callbackData string) {
-	bot.SendMessage(chatID, "✅ Действие подтверждено")
-}
+// HandleNotificationSettings показывает настройки уведомлений, а с
+// аргументом переключает одну из них в том же сообщении.
+func (h *Handlers) HandleNotificationSettings(ctx context.Context, bot BotInterface, chatID int64, setting string, messageID int) {
+	tr := h.tr(chatID)
 
-func (h *Handlers) HandleRefresh(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	h.HandleMyOrdersCommand(ctx, bot, chatID)
-}
+	var (
+		settings *models.NotificationSettings
+		err      error
+	)
 
-func (h *Handlers) HandleMenu(bot BotInterface, chatID int64, callbackData string) {
-	h.HandleStartCommand(bot, chatID, &tgbotapi.User{FirstName: "Курьер"})
-}
+	if setting == "" {
+		settings, err = h.assignmentService.GetNotificationSettings(ctx, chatID)
+		messageID = 0
+	} else {
+		settings, err = h.assignmentService.ToggleNotificationSetting(ctx, chatID, models.NotificationSetting(setting))
+	}
 
-func (h *Handlers) HandleOrderDetails(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from delivery confirmation", "callbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Не удалось получить информацию о заказе.")
+	if errors.Is(err, assignment.ErrUnknownNotificationSetting) {
+		h.log.Warn("Unknown notification setting", "chatID", chatID, "setting", setting)
+		h.HandleUnknownCommand(bot, chatID)
 		return
 	}
 
-	order, err := h.assignmentService.GetOrderByID(ctx, orderID)
 	if err != nil {
-		bot.SendMessage(chatID, "❌ Не удалось получить информацию о заказе.")
+		h.log.Error("Failed to update notification settings", "chatID", chatID, "setting", setting, "error", err)
+		bot.SendMessage(chatID, tr.T("notifications.load_failed"))
 		return
 	}
 
-	message := fmt.Sprintf(
-		"📋 *Детали заказа #%d*\n\n"+
-			"*Статус:* %s\n"+
-			"*Адрес:* %s %s\n"+
-			"*Клиент:* %s\n"+
-			"*Телефон:* %s\n"+
-			"*Дата доставки:* %s\n\n"+
-			"Используйте кнопки ниже для управления доставкой:",
-		orderID,
-		h.determineOrderStatus(ctx, *order),
-		order.City, order.Address,
-		order.Name,
-		order.PhoneNumber,
-		order.DeliveryDate,
-	)
+	h.quietChats.Store(chatID, settings.QuietMode)
 
-	keyboard := h.keyboardManager.CreateDeliveryKeyboard(orderID, order.City+order.Address, order.PhoneNumber)
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	keyboard := h.keyboards(chatID).CreateNotificationSettingsKeyboard(settings)
+	if _, err := h.editOrSend(bot, chatID, messageID, tr.T("notifications.title"), keyboard); err != nil {
+		h.log.Error("Failed to show notification settings", "chatID", chatID, "error", err)
+	}
 }
 
-func (h *Handlers) HandleBackToOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
+// HandleStatistics показывает экран статистики. Переход с другого экрана
+// отправляет новое сообщение, переключение периода редактирует текущее.
+func (h *Handlers) HandleStatistics(ctx context.Context, bot BotInterface, chatID int64, action string, messageID int) {
+	period := models.StatsPeriod(strings.TrimPrefix(action, ActionStats+"_"))
+	switchPeriod := period.IsValid()
+	if !switchPeriod {
+		period = models.StatsPeriodToday
+	}
+
+	stats, err := h.assignmentService.GetCourierStats(ctx, chatID, period)
 	if err != nil {
-		bot.SendMessage(chatID, "❌ Не удалось вернуться к заказу.")
+		h.log.Error("Failed to get courier stats", "chatID", chatID, "period", period, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("stats.load_failed"))
 		return
 	}
 
-	h.HandleOrderDetails(ctx, bot, chatID, fmt.Sprintf("%s_%d", ActionOrderDetails, orderID))
-}
+	if !switchPeriod {
+		messageID = 0
+	}
 
-func (h *Handlers) HandleDeliveryConfirmation(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from delivery confirmation", "callbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка подтверждения заказа.")
-		return
+	message := h.formatStats(h.tr(chatID), stats)
+	keyboard := h.keyboards(chatID).CreateStatsKeyboard(period)
+
+	if _, err := h.editOrSend(bot, chatID, messageID, message, keyboard); err != nil {
+		h.log.Error("Failed to show stats", "chatID", chatID, "error", err)
 	}
+}
+
+// feedbackLimit — сколько последних отзывов видит курьер.
+const feedbackLimit = 5
 
-	err = h.assignmentService.UpdateOrderStatusReceived(ctx, orderID, true)
+// HandleFeedback показывает рейтинг курьера и последние отзывы клиентов
+// вместо статистики, в том же сообщении.
+func (h *Handlers) HandleFeedback(ctx context.Context, bot BotInterface, chatID int64, messageID int) {
+	tr := h.tr(chatID)
+
+	feedback, err := h.assignmentService.GetCourierFeedback(ctx, chatID, feedbackLimit)
 	if err != nil {
-		h.log.Error("Failed to mark order as delivered", "orderID", orderID, "error", err)
-		bot.SendMessage(chatID, "❌ Не удалось обновить статус заказа.")
+		h.log.Error("Failed to get courier feedback", "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, tr.T("feedback.load_failed"))
 		return
 	}
 
-	message := fmt.Sprintf(
-		"🎉 *Заказ #%d доставлен!*\n\n"+
-			"✅ Доставка успешно завершена и подтверждена!\n\n"+
-			"Спасибо за вашу работу!",
-		orderID,
-	)
+	message := h.formatFeedback(tr, feedback)
+	keyboard := h.keyboards(chatID).CreateFeedbackKeyboard()
 
-	bot.SendMessage(chatID, message)
-	h.log.Info("Order confirmed as delivered by courier", "orderID", orderID, "chatID", chatID)
+	if _, err := h.editOrSend(bot, chatID, messageID, message, keyboard); err != nil {
+		h.log.Error("Failed to show feedback", "chatID", chatID, "error", err)
+	}
+}
 
-	h.showNextActions(bot, chatID)
+// HandleRefresh показывает активные заказы в том же сообщении.
+func (h *Handlers) HandleRefresh(ctx context.Context, bot BotInterface, chatID int64, messageID int) {
+	h.showOrderList(ctx, bot, chatID, models.CourierOrdersActive, 0, messageID)
 }
 
-func (h *Handlers) HandleDeliveryCancel(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	orderID, err := h.ExtractOrderID(callbackData)
-	if err != nil {
-		h.log.Error("Failed to extract order ID from delivery confirmation", "callbackData", callbackData)
-		bot.SendMessage(chatID, "❌ Ошибка подтверждения заказа.")
+// HandleOrderList листает список заказов и переключает вкладки в том же
+// сообщении.
+func (h *Handlers) HandleOrderList(ctx context.Context, bot BotInterface, chatID int64, tab string, page int, messageID int) {
+	orderTab := models.CourierOrderTab(tab)
+	if !orderTab.IsValid() {
+		h.log.Warn("Unknown order list tab", "chatID", chatID, "tab", tab)
+		h.HandleUnknownCommand(bot, chatID)
 		return
 	}
 
-	message := fmt.Sprintf(
-		"ℹ️ *Подтверждение доставки отменено*\n\n"+
-			"Заказ #%d остается активным.\n\n"+
-			"Вы можете завершить доставку позже или сообщить о проблеме.",
+	h.showOrderList(ctx, bot, chatID, orderTab, page, messageID)
+}
+
+func (h *Handlers) HandleMenu(ctx context.Context, bot BotInterface, chatID int64) {
+	h.HandleStartCommand(ctx, bot, chatID, &tgbotapi.User{FirstName: h.tr(chatID).T("menu.courier")})
+}
+
+func (h *Handlers) HandleOrderDetails(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
+
+	tr := h.tr(chatID)
+	message := tr.T(
+		"order.details",
 		orderID,
+		tr.T(h.determineOrderStatus(ctx, *order)),
+		order.City, order.Address,
+		order.Name,
+		order.DeliveryDate,
 	)
 
-	order, err := h.assignmentService.GetOrderByID(ctx, orderID)
-	if err == nil && order != nil {
-		keyboard := h.keyboardManager.CreateDeliveryKeyboard(orderID, order.City+order.Address, order.PhoneNumber)
-		bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
-	} else {
+	// Доставленный заказ открывают из истории: действий по нему нет, и
+	// карточкой он не становится.
+	if order.IsReceived {
 		bot.SendMessage(chatID, message)
+		return
 	}
 
+	keyboard := h.keyboards(chatID).CreateDeliveryKeyboard(order)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, message, keyboard)
+}
+
+func (h *Handlers) HandleDeliveryConfirmation(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
+
+	if err := h.assignmentService.UpdateOrderStatusReceived(ctx, orderID, true); err != nil {
+		h.log.Error("Failed to mark order as delivered", "orderID", orderID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("delivery.update_failed"))
+		return
+	}
+
+	h.closeOrderCard(bot, chatID, messageID, h.tr(chatID).T("delivery.confirmed", orderID))
+	h.log.Info("Order confirmed as delivered by courier", "orderID", orderID, "chatID", chatID)
+
+	h.showNextActions(bot, chatID)
+}
+
+func (h *Handlers) HandleDeliveryCancel(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
+
+	message := h.tr(chatID).T("delivery.confirm_cancelled", orderID)
+
+	keyboard := h.keyboards(chatID).CreateDeliveryKeyboard(order)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, message, keyboard)
+
 	h.log.Info("Delivery confirmation cancelled for order by courier", "orderID", orderID, "chatID", chatID)
 }
 
-func (h *Handlers) HandleChangeWorkmode(ctx context.Context, bot BotInterface, chatID int64, callbackData string) {
-	parts := strings.Split(callbackData, "_")
-	isActiveStatus, err := strconv.ParseBool(parts[2])
-	if err != nil {
-		h.log.Warn("Invalid callback data", "Error", err, "CallbakckData", callbackData)
-		bot.SendMessage(chatID, "Ошибка на стороне сервера, попробуйте позже ⌛")
+func (h *Handlers) HandleChangeWorkmode(ctx context.Context, bot BotInterface, chatID int64, action string) {
+	var (
+		msg string
+		err error
+	)
+
+	switch action {
+	case WorkmodeShiftStart:
+		_, err = h.assignmentService.StartShift(ctx, chatID)
+		msg = "workmode.shift_started"
+	case WorkmodeShiftEnd:
+		// Итоги смены присылает сервис.
+		err = h.assignmentService.EndShift(ctx, chatID)
+	case WorkmodeBreakStart:
+		err = h.assignmentService.StartBreak(ctx, chatID)
+		msg = "workmode.break_started"
+	case WorkmodeBreakEnd:
+		err = h.assignmentService.EndBreak(ctx, chatID)
+		msg = "workmode.break_ended"
+	default:
+		h.log.Warn("Invalid workmode action", "action", action)
+		bot.SendMessage(chatID, h.tr(chatID).T("error.server"))
 		return
 	}
 
-	err = h.assignmentService.UpdateCourierStatusIsActive(ctx, chatID, isActiveStatus)
-	if err != nil {
-		bot.SendMessage(chatID, "Ошибка на стороне сервера, попробуйте позже ⌛")
+	switch {
+	case errors.Is(err, assignment.ErrShiftHasUndelivered):
+		bot.SendMessage(chatID, h.tr(chatID).T("workmode.has_undelivered"))
+		return
+	case errors.Is(err, assignment.ErrShiftAlreadyOpen):
+		bot.SendMessage(chatID, h.tr(chatID).T("workmode.already_open"))
+		return
+	case errors.Is(err, assignment.ErrNoOpenShift):
+		bot.SendMessage(chatID, h.tr(chatID).T("workmode.not_open"))
+		return
+	case errors.Is(err, assignment.ErrAlreadyOnBreak):
+		bot.SendMessage(chatID, h.tr(chatID).T("workmode.already_on_break"))
+		return
+	case errors.Is(err, assignment.ErrNotOnBreak):
+		bot.SendMessage(chatID, h.tr(chatID).T("workmode.not_on_break"))
+		return
+	case err != nil:
+		h.log.Error("Failed to change workmode", "chatID", chatID, "action", action, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("error.server"))
 		return
 	}
 
-	var secondPartMsg string
-	if isActiveStatus {
-		secondPartMsg = "Поменяйте статус в настройках на *\"активен\"*, когда захотите вернуться к работе"
-	} else {
-		secondPartMsg = "Теперь ваш статус *\"активен\"*, ждите уведомлений о новых заказах"
+	if msg != "" {
+		bot.SendMessage(chatID, h.tr(chatID).T(msg))
 	}
-	msg := "📝 Ваш статус успешно измен\n\n" + secondPartMsg
+}
+
+func (h *Handlers) HandleCancelOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
 
-	bot.SendMessage(chatID, msg)
+	keyboard := h.keyboards(chatID).CreateCancelReasonKeyboard(orderID)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, h.tr(chatID).T("order.cancel", orderID), keyboard)
+}
+
+func (h *Handlers) HandleCancelReason(ctx context.Context, bot BotInterface, chatID int64, orderID int, reasonCode string) {
+	if !slices.Contains(cancelReasons, reasonCode) {
+		h.log.Warn("Unknown cancel reason", "reason", reasonCode)
+		bot.SendMessage(chatID, h.tr(chatID).T("order.processing_error"))
+		return
+	}
+
+	reason := i18n.For(i18n.Default).T("cancel_reason.text." + reasonCode)
+	err := h.assignmentService.CancelOrderByCourier(ctx, chatID, orderID, reason)
+	switch {
+	case errors.Is(err, assignment.ErrOrderNotAssignedToYou):
+		bot.SendMessage(chatID, h.tr(chatID).T("order.not_yours"))
+	case errors.Is(err, assignment.ErrOrderAlreadyCancelled):
+		bot.SendMessage(chatID, h.tr(chatID).T("order.already_cancelled", orderID))
+	case errors.Is(err, assignment.ErrOrderAlreadyDelivered):
+		bot.SendMessage(chatID, h.tr(chatID).T("order.already_delivered", orderID))
+	case err != nil:
+		h.log.Error("Failed to cancel order by courier", "orderID", orderID, "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("order.cancel_failed"))
+	default:
+		h.log.Info("Order cancelled by courier", "orderID", orderID, "chatID", chatID, "reason", reason)
+	}
 }
 
 // STATUS UPDATE HANDLERS
 
-func (h *Handlers) handleOrderPicked(bot BotInterface, chatID int64, orderID int, order *models.Order) {
-	message := fmt.Sprintf(
-		"📦 *Заказ #%d забран!*\n\n"+
-			"✅ Вы успешно забрали заказ у ресторана.\n\n"+
-			"*Информация о заказе:*\n"+
-			"• Адрес доставки: %s, %s\n"+
-			"• Клиент: %s\n"+
-			"• Телефон: `%s`\n\n"+
-			"🚗 Теперь можете начать доставку к клиенту.",
+func (h *Handlers) handleOrderPicked(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
+	message := h.tr(chatID).T(
+		"order.picked",
 		orderID,
 		order.Address, order.City,
 		order.Name,
-		order.PhoneNumber,
 	)
 
-	keyboard := h.keyboardManager.CreateDeliveryKeyboard(orderID, order.Address, order.PhoneNumber)
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	keyboard := h.keyboards(chatID).CreateDeliveryKeyboard(order)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, message, keyboard)
+	h.assignmentService.ReportOrderProgress(ctx, orderID, models.CustomerEventPickedUp)
 
 	h.log.Info("Courier picked up order", "chatID", chatID, "orderID", orderID)
 }
 
-func (h *Handlers) handleOrderDelivering(bot BotInterface, chatID int64, orderID int, order *models.Order) {
-	message := fmt.Sprintf(
-		"🚗 *Заказ #%d в пути!*\n\n"+
-			"📍 Вы направляетесь к клиенту.\n\n"+
-			"*Рекомендации:*\n"+
-			"• 🗺️ Используйте навигацию для оптимального маршрута\n"+
-			"• 📞 Свяжитесь с клиентом за 10-15 минут до прибытия\n"+
-			"• ⏱️ Учитывайте текущую дорожную ситуацию\n\n"+
-			"Ориентировочное время прибытия: *15-20 минут*",
-		orderID,
-	)
-
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗺️ Построить маршрут", fmt.Sprintf("nav_%d_%s", orderID, h.keyboardManager.EscapeCallbackData(order.Address))),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить клиенту", fmt.Sprintf("call_%d_%s", orderID, order.PhoneNumber)),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📍 Я на месте", fmt.Sprintf("status_arrived_%d", orderID)),
-		),
-	)
+func (h *Handlers) handleOrderDelivering(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
 
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	keyboard := h.keyboards(chatID).CreateDeliveringKeyboard(order)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, h.tr(chatID).T("order.delivering", orderID), keyboard)
+	h.assignmentService.ReportOrderProgress(ctx, orderID, models.CustomerEventApproaching)
 	h.log.Info("Courier started delivering order", "chatID", chatID, "orderID", orderID)
 }
 
-func (h *Handlers) handleOrderArrived(bot BotInterface, chatID int64, orderID int, order *models.Order) {
-	message := fmt.Sprintf(
-		"📍 *Вы на месте!*\n\n"+
-			"Заказ #%d готов к передаче клиенту.\n\n"+
-			"*Действия:*\n"+
-			"1. 📞 Позвоните клиенту для встречи\n"+
-			"2. ✅ Передайте заказ\n"+
-			"3. 💰 Примите оплату (если необходимо)\n"+
-			"4. 🏁 Подтвердите доставку\n\n"+
-			"Клиент: %s\n"+
-			"Телефон: `%s`",
-		orderID,
-		order.Name,
-		order.PhoneNumber,
-	)
+func (h *Handlers) handleOrderArrived(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+	orderID := order.ID
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить клиенту", fmt.Sprintf("call_%d_%s", orderID, order.PhoneNumber)),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Доставка завершена", fmt.Sprintf("status_delivered_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("Возникли проблемы", fmt.Sprintf("problem_%d", orderID)),
-		),
-	)
-
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+	message := h.tr(chatID).T("order.arrived", orderID, order.Name)
+	keyboard := h.keyboards(chatID).CreateArrivedKeyboard(order)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, message, keyboard)
 	h.log.Info("Courier arrived with order", "chatID", chatID, "orderID", orderID)
 }
 
-func (h *Handlers) handleOrderDelivered(ctx context.Context, bot BotInterface, chatID int64, orderID int) {
-	h.assignmentService.UpdateOrderStatusReceived(ctx, orderID, true)
+// handleOrderDelivered только спрашивает подтверждение, заказ отмечается
+// доставленным в HandleDeliveryConfirmation.
+func (h *Handlers) handleOrderDelivered(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int) {
+	keyboard := h.keyboards(chatID).CreateConfirmationKeyboard(ActionConfirmDelivery, ActionCancelDelivery, orderID)
+	h.showOrderCard(ctx, bot, chatID, orderID, messageID, h.tr(chatID).T("order.confirm_delivery", orderID), keyboard)
+}
+
+// UTILITY METHODS
 
-	message := fmt.Sprintf(
-		"🏁 *Подтверждение доставки*\n\n"+
-			"Заказ #%d готов к отметке как доставленный.\n\n"+
-			"*Пожалуйста, подтвердите:*\n"+
-			"✅ Заказ передан клиенту\n"+
-			"✅ Оплата получена (если требуется)\n"+
-			"После подтверждения заказ будет завершен.",
-		orderID,
-	)
+// Причина отмены сохраняется в заказе и показывается диспетчеру, поэтому
+// её текст берётся на языке по умолчанию, а не на языке курьера.
+var cancelReasons = []string{CancelReasonRefused, CancelReasonUnreachable, CancelReasonAddress, CancelReasonOther}
+
+// Фразы уходят клиенту и сохраняются в заказе, поэтому, как и причины
+// отмены, не зависят от языка курьера.
+var contactTemplates = []string{ContactDownstairs, ContactLate, ContactAtDoor, ContactNoAnswer}
+
+// contactComposeTTL — сколько бот ждёт текст для клиента после нажатия
+// «Написать своё».
+const contactComposeTTL = 10 * time.Minute
 
-	keyboard := h.keyboardManager.CreateConfirmationKeyboard("delivery", orderID)
-	bot.SendMessageWithInlineKeyboard(chatID, message, keyboard)
+type composeState struct {
+	OrderID int
+	Until   time.Time
 }
 
-// UTILITY METHODS
+// relayComposed передаёт клиенту сообщение курьера, если бот его ждёт.
+// Команда или кнопка меню отменяют ожидание и обрабатываются как обычно.
+func (h *Handlers) relayComposed(ctx context.Context, bot BotInterface, chatID int64, text string) bool {
+	value, ok := h.composing.Load(chatID)
+	if !ok {
+		return false
+	}
+
+	state := value.(composeState)
+	if time.Now().After(state.Until) {
+		h.composing.Delete(chatID)
+		return false
+	}
+
+	if strings.HasPrefix(text, "/") {
+		h.composing.Delete(chatID)
+		return false
+	}
+
+	if _, ok := i18n.Match(text, "menu.help", "menu.orders", "menu.status", "menu.settings"); ok {
+		h.composing.Delete(chatID)
+		return false
+	}
+
+	if strings.TrimSpace(text) == "" {
+		bot.SendMessage(chatID, h.tr(chatID).T("contact.text_only"))
+		return true
+	}
+
+	h.composing.Delete(chatID)
+	h.sendToCustomer(ctx, bot, chatID, state.OrderID, "", strings.TrimSpace(text))
+
+	return true
+}
+
+func (h *Handlers) sendToCustomer(ctx context.Context, bot BotInterface, chatID int64, orderID int, template, text string) {
+	if err := h.assignmentService.SendCustomerMessage(ctx, chatID, orderID, template, text); err != nil {
+		h.contactFailed(bot, chatID, orderID, err)
+		return
+	}
+
+	bot.SendMessage(chatID, h.tr(chatID).T("contact.sent", tgbotapi.EscapeText(ParseMode, text)))
+}
+
+func (h *Handlers) contactFailed(bot BotInterface, chatID int64, orderID int, err error) {
+	switch {
+	case errors.Is(err, assignment.ErrOrderAlreadyDelivered), errors.Is(err, assignment.ErrOrderAlreadyCancelled):
+		bot.SendMessage(chatID, h.tr(chatID).T("contact.closed", orderID))
+	case errors.Is(err, assignment.ErrOrderNotAssignedToYou):
+		bot.SendMessage(chatID, h.tr(chatID).T("order.not_yours"))
+	case errors.Is(err, assignment.ErrNoCustomerPhone):
+		bot.SendMessage(chatID, h.tr(chatID).T("call.no_phone"))
+	default:
+		h.log.Error("Failed to contact customer", "orderID", orderID, "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("contact.send_failed"))
+	}
+}
+
+func (h *Handlers) getActiveOrder(ctx context.Context, bot BotInterface, chatID int64, orderID int) (*models.Order, bool) {
+	order, err := h.assignmentService.GetOrderByID(ctx, orderID)
+	if err != nil {
+		h.log.Error("Failed to get order", "orderID", orderID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("order.not_found"))
+		return nil, false
+	}
+
+	if order.IsCancelled() {
+		bot.SendMessage(chatID, h.tr(chatID).T("order.cancelled_locked", orderID))
+		return nil, false
+	}
+
+	return order, true
+}
+
+// showOrderCard выводит экран заказа в его карточке. Кнопка, нажатая на
+// самой карточке, редактирует её. Переход из другого сообщения, например из
+// списка заказов, присылает карточку заново, а со старой снимает кнопки.
+func (h *Handlers) showOrderCard(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	card, err := h.assignmentService.GetOrderCard(ctx, orderID, chatID)
+	if err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+		h.log.Error("Failed to get order card", "orderID", orderID, "chatID", chatID, "error", err)
+	}
+
+	target := 0
+	if card != nil && card.MessageID == messageID {
+		target = messageID
+	}
+
+	sentID, err := h.editOrSend(bot, chatID, target, text, keyboard)
+	if err != nil {
+		h.log.Error("Failed to show order card", "orderID", orderID, "chatID", chatID, "error", err)
+		return
+	}
 
-func (h *Handlers) ExtractOrderID(callbackData string) (int, error) {
-	parts := strings.Split(callbackData, "_")
-	if len(parts) < 2 {
-		return 0, fmt.Errorf("invalid callback data format: %s", callbackData)
+	if sentID != target {
+		h.assignmentService.TrackOrderMessage(ctx, orderID, chatID, sentID, models.OrderMessageDelivery)
 	}
+}
 
-	for i := len(parts) - 1; i >= 0; i-- {
-		if id, err := strconv.Atoi(parts[i]); err == nil {
-			return id, nil
+// closeOrderCard заменяет карточку итогом без кнопок: действий по заказу
+// больше не будет.
+func (h *Handlers) closeOrderCard(bot BotInterface, chatID int64, messageID int, text string) {
+	if _, err := h.editOrSend(bot, chatID, messageID, text, tgbotapi.InlineKeyboardMarkup{}); err != nil {
+		h.log.Error("Failed to close order card", "chatID", chatID, "messageID", messageID, "error", err)
+	}
+}
+
+// editOrSend редактирует сообщение messageID, а если его нет или правка не
+// удалась, отправляет новое. Возвращает ID сообщения, где оказался текст.
+func (h *Handlers) editOrSend(bot BotInterface, chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error) {
+	if messageID != 0 {
+		err := bot.EditMessageWithInlineKeyboard(chatID, messageID, text, keyboard)
+		if err == nil {
+			return messageID, nil
 		}
+
+		h.log.Warn("Failed to edit message, sending a new one", "chatID", chatID, "messageID", messageID, "error", err)
+	}
+
+	if len(keyboard.InlineKeyboard) == 0 {
+		return 0, bot.SendMessage(chatID, text)
 	}
 
-	return 0, fmt.Errorf("order ID not found in callback data: %s", callbackData)
+	return bot.SendMessageWithInlineKeyboard(chatID, text, keyboard)
+}
+
+func (h *Handlers) showOrderList(ctx context.Context, bot BotInterface, chatID int64, tab models.CourierOrderTab, page int, messageID int) {
+	tr := h.tr(chatID)
+
+	page = max(page, 0)
+	orders, total, err := h.assignmentService.ListCourierOrders(ctx, chatID, tab, models.Page{
+		Limit:  orderListPageSize,
+		Offset: page * orderListPageSize,
+	})
+	if err != nil {
+		h.log.Error("Failed to list courier orders", "chatID", chatID, "tab", tab, "page", page, "error", err)
+		bot.SendMessage(chatID, tr.T("orders.load_failed"))
+		return
+	}
+
+	// Пока курьер листал, заказы могли завершиться и страница опустела.
+	if len(orders) == 0 && page > 0 {
+		h.showOrderList(ctx, bot, chatID, tab, 0, messageID)
+		return
+	}
+
+	message := tr.T("orders.title." + string(tab))
+	if total == 0 {
+		message += tr.T("orders.empty." + string(tab))
+	} else {
+		message += tr.T("orders.total", total)
+	}
+
+	keyboard := h.keyboards(chatID).CreateOrderListKeyboard(OrderList{
+		Tab:   tab,
+		Page:  page,
+		Pages: (total + orderListPageSize - 1) / orderListPageSize,
+		Items: h.convertOrdersToOrderListItem(ctx, chatID, tab, orders),
+	})
+
+	if _, err := h.editOrSend(bot, chatID, messageID, message, keyboard); err != nil {
+		h.log.Error("Failed to show order list", "chatID", chatID, "error", err)
+	}
 }
 
 func (h *Handlers) showNextActions(bot BotInterface, chatID int64) {
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 Мои заказы", "my_orders"),
-			tgbotapi.NewInlineKeyboardButtonData("📊 Статистика", "statistics"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Новый заказ", "refresh_orders"),
-			tgbotapi.NewInlineKeyboardButtonData("⚙️ Настройки", "settings"),
-		),
-	)
+	bot.SendMessageWithInlineKeyboard(chatID, h.tr(chatID).T("next.title"), h.keyboards(chatID).CreateNextActionsKeyboard())
+}
+
+// rejectCallback отвечает на кнопку, которую не удалось разобрать. Кнопки
+// старого формата просят открыть экран заново, подделанные ещё и пишутся
+// в лог с отправителем.
+func (h *Handlers) rejectCallback(bot BotInterface, chatID int64, query *tgbotapi.CallbackQuery, err error) {
+	key := "callback.stale"
+
+	switch {
+	case errors.Is(err, callback.ErrStale):
+		h.log.Info("Stale callback data", "chatID", chatID, "callbackData", query.Data)
+	case errors.Is(err, callback.ErrInvalidSignature), errors.Is(err, callback.ErrMalformed):
+		h.log.Warn("Rejected callback data", "chatID", chatID, "userID", query.From.ID, "callbackData", query.Data, "error", err)
+		key = "callback.invalid"
+	default:
+		h.log.Error("Failed to decode callback data", "chatID", chatID, "error", err)
+		key = "error.server"
+	}
+
+	if err := bot.AnswerCallbackQueryWithText(query.ID, h.tr(chatID).T(key)); err != nil {
+		h.log.Error("Failed to answer callback query", "error", err)
+	}
+}
+
+// tr возвращает локализатор чата. Язык определяется в resolveLang при
+// получении апдейта, до этого используется язык по умолчанию.
+func (h *Handlers) tr(chatID int64) i18n.Localizer {
+	if lang, ok := h.langs.Load(chatID); ok {
+		return i18n.For(lang.(i18n.Lang))
+	}
+
+	return i18n.For(i18n.Default)
+}
+
+func (h *Handlers) keyboards(chatID int64) KeyboardManagerInterface {
+	return h.keyboardManager.WithLang(h.tr(chatID).Lang())
+}
+
+// resolveLang запоминает язык чата: у зарегистрированного курьера — из его
+// профиля, у нового пользователя — по LanguageCode из Telegram.
+func (h *Handlers) resolveLang(ctx context.Context, chatID int64, user *tgbotapi.User) {
+	if _, ok := h.langs.Load(chatID); ok {
+		return
+	}
+
+	lang := i18n.Default
+	if user != nil {
+		lang = i18n.FromTelegram(user.LanguageCode)
+	}
+
+	if h.assignmentService.CheckCourierByChatID(ctx, chatID) {
+		if courier, err := h.assignmentService.GetCourierByChatID(ctx, chatID); err == nil {
+			lang = i18n.Parse(courier.Language)
+		}
+	}
+
+	h.langs.Store(chatID, lang)
+}
+
+// resolveQuiet запоминает, включён ли у курьера тихий режим: по нему
+// TelegramBot отправляет ответы без звука.
+func (h *Handlers) resolveQuiet(ctx context.Context, chatID int64) {
+	if _, ok := h.quietChats.Load(chatID); ok {
+		return
+	}
 
-	bot.SendMessageWithInlineKeyboard(chatID, "Что дальше?", keyboard)
+	if !h.assignmentService.CheckCourierByChatID(ctx, chatID) {
+		return
+	}
+
+	settings, err := h.assignmentService.GetNotificationSettings(ctx, chatID)
+	if err != nil {
+		h.log.Warn("Failed to get notification settings", "chatID", chatID, "error", err)
+		return
+	}
+
+	h.quietChats.Store(chatID, settings.QuietMode)
+}
+
+func (h *Handlers) quiet(chatID int64) bool {
+	quiet, ok := h.quietChats.Load(chatID)
+	return ok && quiet.(bool)
 }
 
-func (h *Handlers) convertOrdersToOrderListItem(ctx context.Context, orders []models.Order) []OrderListItem {
+func (h *Handlers) changeLanguage(ctx context.Context, bot BotInterface, chatID int64, value string) {
+	lang := i18n.Parse(value)
+	if string(lang) != value {
+		h.log.Warn("Unsupported language", "chatID", chatID, "language", value)
+		h.HandleUnknownCommand(bot, chatID)
+		return
+	}
+
+	if err := h.assignmentService.SetCourierLanguage(ctx, chatID, lang); err != nil {
+		h.log.Error("Failed to change courier language", "chatID", chatID, "language", lang, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("error.server"))
+		return
+	}
+
+	h.langs.Store(chatID, lang)
+
+	// Reply-клавиатуру нужно прислать заново, иначе кнопки меню останутся
+	// на прежнем языке.
+	bot.SendMessageWithKeyboard(chatID, h.tr(chatID).T("language.changed"), h.keyboards(chatID).CreateMainMenuKeyboard())
+}
+
+func (h *Handlers) changeMapApp(ctx context.Context, bot BotInterface, chatID int64, value string) {
+	app, ok := navigation.Parse(value)
+	if !ok {
+		h.log.Warn("Unsupported map app", "chatID", chatID, "app", value)
+		h.HandleUnknownCommand(bot, chatID)
+		return
+	}
+
+	if err := h.assignmentService.SetCourierMapApp(ctx, chatID, app); err != nil {
+		h.log.Error("Failed to change courier map app", "chatID", chatID, "app", app, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("error.server"))
+		return
+	}
+
+	tr := h.tr(chatID)
+	bot.SendMessage(chatID, tr.T("maps.changed", tr.T("maps.app."+string(app))))
+}
+
+func (h *Handlers) convertOrdersToOrderListItem(ctx context.Context, chatID int64, tab models.CourierOrderTab, orders []models.Order) []OrderListItem {
 	var items []OrderListItem
 
+	tr := h.tr(chatID)
+
 	for _, order := range orders {
-		status := h.determineOrderStatus(ctx, order)
 		item := OrderListItem{
 			ID:      order.ID,
-			Status:  status,
 			Address: fmt.Sprintf("%s, %s", order.Address, order.City),
-			Time:    h.formatDeliveryTime(order.DeliveryDate),
 			Price:   order.FinalPrice,
 		}
 
+		if tab == models.CourierOrdersActive {
+			item.Status = h.determineOrderStatus(ctx, order)
+			item.Time = h.formatDeliveryTime(tr, order.DeliveryDate)
+		} else {
+			item.Status = orderStatusDelivered
+			item.Time = h.formatDeliveredAt(tr, tab, order.RecievedAt)
+		}
+
 		items = append(items, item)
 	}
 
 	return items
 }
 
+const orderListPageSize = 5
+
+// Статусы заказа в списке — ключи каталога сообщений.
+const (
+	orderStatusPending    = "order_status.pending"
+	orderStatusWaiting    = "order_status.waiting"
+	orderStatusAccepted   = "order_status.accepted"
+	orderStatusInDelivery = "order_status.in_delivery"
+	orderStatusDelivered  = "order_status.delivered"
+	orderStatusRejected   = "order_status.rejected"
+	orderStatusExpired    = "order_status.expired"
+	orderStatusProcessing = "order_status.processing"
+)
+
 func (h *Handlers) determineOrderStatus(ctx context.Context, order models.Order) string {
 	assignment, err := h.assignmentService.GetAssignmentByOrderID(ctx, order.ID)
 
 	if err != nil || assignment == nil {
-		return "⏳ Ожидает подтверждения"
+		return orderStatusPending
 	}
 
 	switch assignment.CourierResponseStatus {
 	case "waiting":
-		return "⏳ Ожидает ответа"
+		return orderStatusWaiting
 	case "accepted":
 		switch {
 		case order.IsReceived:
-			return "✅ Доставлен"
+			return orderStatusDelivered
 		case h.isDeliveryInProgerss(order):
-			return "🚗 В доставке"
+			return orderStatusInDelivery
 		default:
-			return "✅ Принят в работу"
+			return orderStatusAccepted
 		}
 	case "rejected":
-		return "❌ Отклонен"
+		return orderStatusRejected
 	case "expired":
-		return "⏰ Время истекло"
+		return orderStatusExpired
 	default:
-		return "📋 В обработке"
+		return orderStatusProcessing
 	}
 }
 
@@ -760,9 +1115,9 @@ func (h *Handlers) isDeliveryInProgerss(order models.Order) bool {
 	return timeUntilDelivery <= 2*time.Hour || deliveryTime.Before(now)
 }
 
-func (h *Handlers) formatDeliveryTime(deliveryTime *time.Time) string {
+func (h *Handlers) formatDeliveryTime(tr i18n.Localizer, deliveryTime *time.Time) string {
 	if deliveryTime == nil {
-		return "⏰ Время не указано"
+		return tr.T("time.not_set")
 	}
 
 	now := time.Now()
@@ -771,96 +1126,118 @@ func (h *Handlers) formatDeliveryTime(deliveryTime *time.Time) string {
 	diff := delivery.Sub(now)
 
 	if diff <= 0 {
-		return "🚨 СРОЧНО! Просрочен"
+		return tr.T("time.overdue")
 	}
 
 	if diff <= time.Hour {
 		minutes := int(diff.Minutes())
 		if minutes <= 0 {
-			return "🚨 СРОЧНО! Просрочен"
+			return tr.T("time.overdue")
 		}
-		return fmt.Sprintf("🚨 через %d мин", minutes)
+		return tr.T("time.in_minutes", minutes)
 	}
 
 	if delivery.Year() == now.Year() && delivery.Month() == now.Month() && delivery.Day() == now.Day() {
-		return fmt.Sprintf("🕐 Сегодня в %s", delivery.Format("15:04"))
+		return tr.T("time.today", delivery.Format("15:04"))
 	}
 
 	tomorrow := now.Add(24 * time.Hour)
 	if delivery.Year() == tomorrow.Year() && delivery.Month() == tomorrow.Month() && delivery.Day() == tomorrow.Day() {
-		return fmt.Sprintf("📅 Завтра в %s", delivery.Format("15:04"))
+		return tr.T("time.tomorrow", delivery.Format("15:04"))
 	}
 
 	weekLater := now.Add(7 * 24 * time.Hour)
 	if delivery.Before(weekLater) {
-		weekday := h.getRussianWeekday(delivery.Weekday())
-		return fmt.Sprintf("📅 %s в %s", weekday, delivery.Format("15:04"))
+		return tr.T("time.weekday", tr.Weekday(delivery.Weekday()), delivery.Format("15:04"))
 	}
 
-	return fmt.Sprintf("📅 %s", delivery.Format("02.01 в 15:04"))
+	return tr.T("time.date", delivery.Format("02.01"), delivery.Format("15:04"))
 }
 
-func (h *Handlers) getRussianWeekday(weekday time.Weekday) string {
-	days := map[time.Weekday]string{
-		time.Monday:    "Пн",
-		time.Tuesday:   "Вт",
-		time.Wednesday: "Ср",
-		time.Thursday:  "Чт",
-		time.Friday:    "Пт",
-		time.Saturday:  "Сб",
-		time.Sunday:    "Вс",
+// formatDeliveredAt: на вкладке today достаточно времени, в истории нужна
+// ещё и дата.
+func (h *Handlers) formatDeliveredAt(tr i18n.Localizer, tab models.CourierOrderTab, receivedAt *time.Time) string {
+	if receivedAt == nil {
+		return tr.T("time.not_set")
 	}
 
-	return days[weekday]
+	delivered := receivedAt.In(h.assignmentService.Now().Location())
+	if tab == models.CourierOrdersToday {
+		return delivered.Format("15:04")
+	}
+
+	return delivered.Format("02.01 15:04")
 }
 
-func (h *Handlers) formatOrdersSummary(orderItems []OrderListItem) string {
-	var waitingCount, acceptCount, deliveryCount int
+func (h *Handlers) formatShiftStatus(tr i18n.Localizer, state *assignment.ShiftState) string {
+	switch {
+	case state.Shift == nil:
+		return tr.T("shift_status.off")
+	case state.Break != nil:
+		return tr.T("shift_status.break")
+	default:
+		return tr.T("shift_status.on")
+	}
+}
 
-	for _, item := range orderItems {
-		switch item.Status {
-		case "⏳ Ожидает ответа", "⏳ Ожидает подтверждения":
-			waitingCount++
-		case "✅ Принят в работу":
-			acceptCount++
-		case "🚗 В доставке":
-			deliveryCount++
-		}
+func (h *Handlers) formatStats(tr i18n.Localizer, stats *models.CourierStats) string {
+	var builder strings.Builder
+
+	builder.WriteString(tr.T("stats.title", tr.T("stats.period."+string(stats.Period))))
+	builder.WriteString(tr.T("stats.since", stats.Since.Format("02.01.2006")))
+	builder.WriteString(tr.N("stats.deliveries", stats.Deliveries))
+	builder.WriteString(tr.T("stats.earnings", stats.Earnings))
+
+	if stats.Offers > 0 {
+		builder.WriteString(tr.T("stats.acceptance", stats.Accepted, stats.Offers, stats.AcceptanceRate()))
+	} else {
+		builder.WriteString(tr.T("stats.acceptance_none"))
 	}
 
-	total := len(orderItems)
+	if stats.Deliveries > 0 && stats.AvgDeliveryTime > 0 {
+		builder.WriteString(tr.T("stats.avg_time", tr.Duration(stats.AvgDeliveryTime)))
+	} else {
+		builder.WriteString(tr.T("stats.avg_time_none"))
+	}
 
-	summary := fmt.Sprintf(
-		"📋 *Ваши активные заказы*\n\n"+
-			"📊 *Статистика:*\n"+
-			"• ⏳ Ожидают подтверждения: %d\n"+
-			"• ✅ Приняты в работу: %d\n"+
-			"• 🚗 В доставке: %d\n"+
-			"• 📈 Всего активных: %d\n\n",
-		waitingCount,
-		acceptCount,
-		deliveryCount,
-		total,
-	)
+	if stats.WithDeadline > 0 {
+		builder.WriteString(tr.T("stats.on_time", stats.OnTimeRate()))
+	} else {
+		builder.WriteString(tr.T("stats.on_time_none"))
+	}
 
-	summary += "Выберите заказ для просмотра деталей:"
+	builder.WriteString(tr.T("stats.rating", h.formatRating(tr, stats.Rating)))
 
-	return summary
+	return builder.String()
 }
 
-func (h *Handlers) parseStatusCallback(callbackData string) (action string, orderID int, err error) {
-	parts := strings.Split(callbackData, "_")
+func (h *Handlers) formatFeedback(tr i18n.Localizer, feedback *assignment.CourierFeedback) string {
+	if len(feedback.Reviews) == 0 {
+		return tr.T("feedback.none")
+	}
+
+	var builder strings.Builder
 
-	if len(parts) < 3 {
-		return "", 0, fmt.Errorf("invalid callback format: %s", callbackData)
+	builder.WriteString(tr.T("feedback.title", h.formatRating(tr, feedback.Rating), feedback.Total))
+
+	location := h.assignmentService.Now().Location()
+	for _, review := range feedback.Reviews {
+		stars := strings.Repeat("★", review.Rating) + strings.Repeat("☆", models.MaxReviewRating-review.Rating)
+		builder.WriteString(tr.T("feedback.item", stars, review.OrderID, review.CreatedAt.In(location).Format("02.01")))
+
+		if review.Comment != "" {
+			builder.WriteString(tr.T("feedback.comment", tgbotapi.EscapeText(ParseMode, review.Comment)))
+		}
+		builder.WriteString("\n")
 	}
 
-	action = strings.Join(parts[:2], "_")
+	return builder.String()
+}
 
-	orderID, err = strconv.Atoi(parts[2])
-	if err != nil {
-		return "", 0, fmt.Errorf("invalid order id in callback: %s", parts[2])
+func (h *Handlers) formatRating(tr i18n.Localizer, rating float64) string {
+	if rating == 0 {
+		return tr.T("rating.none")
 	}
 
-	return action, orderID, nil
+	return fmt.Sprintf("%.2f", rating)
 }
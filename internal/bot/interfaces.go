@@ -2,17 +2,23 @@ package bot
 
 import (
 	"context"
+	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/navigation"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type BotInterface interface {
 	SendMessage(chatID int64, text string) error
 	SendMessageWithKeyboard(chatID int64, text string, keyboard tgbotapi.ReplyKeyboardMarkup) error
-	SendMessageWithInlineKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) error
+	SendMessageWithInlineKeyboard(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error)
 
 	EditMessageText(chatID int64, messageID int, text string) error
 	EditMessageReplyMarkup(chatID int64, messageID int, replyMarkup interface{}) error
+	EditMessageWithInlineKeyboard(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error
 
 	DeleteMessage(chatID int64, messageID int)
 
@@ -26,55 +32,75 @@ type BotInterface interface {
 }
 
 type KeyboardManagerInterface interface {
+	WithLang(lang i18n.Lang) KeyboardManagerInterface
+
 	CreateAssignmentKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
-	CreateDeliveryKeyboard(orderID int, address, phone string) tgbotapi.InlineKeyboardMarkup
-	CreateStatusKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
+	CreateDeliveryKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup
+	CreateDeliveringKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup
+	CreateArrivedKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup
+	CreateContactKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
+	CreateComposeKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
+	CreatePhoneKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
 	CreateMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup
 	CreateSettingsKeyboard() tgbotapi.InlineKeyboardMarkup
-	CreateConfirmationKeyboard(action string, data interface{}) tgbotapi.InlineKeyboardMarkup
-	CreateOrderListKeyboard(orders []OrderListItem) tgbotapi.InlineKeyboardMarkup
+	CreateLanguageKeyboard() tgbotapi.InlineKeyboardMarkup
+	CreateNotificationSettingsKeyboard(settings *models.NotificationSettings) tgbotapi.InlineKeyboardMarkup
+	CreateMapAppKeyboard(current navigation.App) tgbotapi.InlineKeyboardMarkup
+	CreateNavigationKeyboard(apps []navigation.App, address string) tgbotapi.InlineKeyboardMarkup
+	CreateConfirmationKeyboard(confirm, cancel string, id int) tgbotapi.InlineKeyboardMarkup
+	CreateOrderListKeyboard(list OrderList) tgbotapi.InlineKeyboardMarkup
 	CreateProblemKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
-	CreateYesNoKeyboard(action string, id int) tgbotapi.InlineKeyboardMarkup
-	CreateChangeWorkmodeKeyboard(isActive bool) tgbotapi.InlineKeyboardMarkup
+	CreateNextActionsKeyboard() tgbotapi.InlineKeyboardMarkup
+	CreateStatsShortcutKeyboard() tgbotapi.InlineKeyboardMarkup
+	CreateChangeWorkmodeKeyboard(onShift, onBreak bool) tgbotapi.InlineKeyboardMarkup
+	CreateCancelReasonKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup
+	CreateStatsKeyboard(period models.StatsPeriod) tgbotapi.InlineKeyboardMarkup
+	CreateFeedbackKeyboard() tgbotapi.InlineKeyboardMarkup
+	CreateScheduleKeyboard(availability *models.CourierAvailability) tgbotapi.InlineKeyboardMarkup
+	CreateWeekdayKeyboard() tgbotapi.InlineKeyboardMarkup
+	CreateHourKeyboard(next callback.Data, fromHour, toHour int) tgbotapi.InlineKeyboardMarkup
+	CreateExceptionDateKeyboard(today time.Time, days int) tgbotapi.InlineKeyboardMarkup
+	CreateExceptionTypeKeyboard(day int) tgbotapi.InlineKeyboardMarkup
 	RemoveKeyboard() tgbotapi.ReplyKeyboardRemove
-
-	GetActionFromCallback(callbackData string) string
-	ParseCallbackData(callbackData string) (action string, id int, err error)
-
-	EscapeCallbackData(data string) string
 }
 
 type HandlersInterface interface {
 	HandleMessage(ctx context.Context, bot BotInterface, update tgbotapi.Update)
 	HandleCallback(ctx context.Context, bot BotInterface, update tgbotapi.Update)
+	HandleLocation(ctx context.Context, bot BotInterface, message *tgbotapi.Message)
 
-	HandleStartCommand(bot BotInterface, chatID int64, user *tgbotapi.User)
+	HandleStartCommand(ctx context.Context, bot BotInterface, chatID int64, user *tgbotapi.User)
 	HandleHelpCommand(bot BotInterface, chatID int64)
 	HandleMyOrdersCommand(ctx context.Context, bot BotInterface, chatID int64)
-	HandleStatusCommand(bot BotInterface, chatID int64)
+	HandleStatusCommand(ctx context.Context, bot BotInterface, chatID int64)
 	HandleSettingsCommand(bot BotInterface, chatID int64)
 	HandleUnknownCommand(bot BotInterface, chatID int64)
 
-	HandleAcceptOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string, messageID int64)
-	HandleRejectOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string, messageID int64)
-	HandleCompleteOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleProblemOrder(bot BotInterface, chatID int64, callbackData string)
-	HandleNavigation(bot BotInterface, chatID int64, callbackData string)
-	HanldeCallCustomeer(bot BotInterface, chatID int64, callbackData string)
-	HandleChangeWorkmode(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-
-	HandleStatusUpdate(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HanldeSettings(ctx context.Context, ot BotInterface, chatID int64, callbackData string)
-	HandleConfirmation(bot BotInterface, chatID int64, callbackData string)
-	HanldeRefresh(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleMenu(bot BotInterface, chatID int64, callbackData string)
-	HandleOrderDetails(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleBackToOrder(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleDeliveryConfirmation(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleDeliveryCancel(ctx context.Context, bot BotInterface, chatID int64, callbackData string)
-	HandleUnknownCallback(bot BotInterface, chatID int64, callbackData string)
-
-	ExtractOrderID(callbackData string) (int, error)
+	HandleAcceptOrder(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int)
+	HandleRejectOrder(ctx context.Context, bot BotInterface, chatID int64, orderID int, messageID int)
+	HandleCompleteOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleProblemOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleCancelOrder(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleCancelReason(ctx context.Context, bot BotInterface, chatID int64, orderID int, reasonCode string)
+	HandleNavigation(bot BotInterface, chatID int64, courier *models.Courier, order *models.Order)
+	HandleCallCustomer(ctx context.Context, bot BotInterface, chatID int64, order *models.Order)
+	HandleContact(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleContactTemplate(ctx context.Context, bot BotInterface, chatID int64, orderID int, template string)
+	HandleContactCompose(bot BotInterface, chatID int64, orderID int)
+	HandleContactCancel(bot BotInterface, chatID int64)
+	HandleChangeWorkmode(ctx context.Context, bot BotInterface, chatID int64, action string)
+
+	HandleStatusUpdate(ctx context.Context, bot BotInterface, chatID int64, action string, order *models.Order, messageID int)
+	HandleSettings(ctx context.Context, bot BotInterface, chatID int64, data callback.Data)
+	HandleRefresh(ctx context.Context, bot BotInterface, chatID int64, messageID int)
+	HandleOrderList(ctx context.Context, bot BotInterface, chatID int64, tab string, page int, messageID int)
+	HandleMenu(ctx context.Context, bot BotInterface, chatID int64)
+	HandleOrderDetails(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleDeliveryConfirmation(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleDeliveryCancel(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)
+	HandleStatistics(ctx context.Context, bot BotInterface, chatID int64, action string, messageID int)
+	HandleSchedule(ctx context.Context, bot BotInterface, chatID int64, data callback.Data)
+	HandleUnknownCallback(ctx context.Context, req *CallbackRequest)
 }
 
 const (
@@ -87,15 +113,17 @@ const (
 	// Utility Actions
 	ActionNavigate        = "nav"
 	ActionCall            = "call"
-	ActionStatus          = "status"
+	ActionContact         = "contact"
 	ActionSettings        = "settings"
-	ActionConfirm         = "confirm"
-	ActionCancel          = "cancel"
-	ActionRefresh         = "refresh"
-	ActionMenu            = "menu"
+	ActionRefreshOrders   = "refresh_orders"
+	ActionOrderList       = "order_list"
 	ActionConfirmDelivery = "confirm_delivery"
 	ActionCancelDelivery  = "cancel_delivery"
-	ActionChangeWorkmode  = "change_workmode"
+	ActionCancelOrder     = "cancel_order"
+	ActionCancelReason    = "cancel_reason"
+	ActionStats           = "stats"
+	ActionFeedback        = "feedback"
+	ActionSchedule        = "schedule"
 
 	// Sub-actions
 	ActionOrderDetails = "order_details"
@@ -118,19 +146,71 @@ const (
 	SettingsNotifications = "settings_notifications"
 	SettingsWorkmode      = "settings_workmode"
 	SettingsContacts      = "settings_contacts"
+	SettingsSchedule      = "settings_schedule"
+	SettingsLanguage      = "settings_language"
+	SettingsMaps          = "settings_maps"
+
+	// Stats Sub-types
+	StatsToday = "stats_today"
+	StatsWeek  = "stats_week"
+	StatsMonth = "stats_month"
+
+	// Workmode Sub-types
+	WorkmodeShiftStart = "change_workmode_start"
+	WorkmodeShiftEnd   = "change_workmode_end"
+	WorkmodeBreakStart = "change_workmode_break"
+	WorkmodeBreakEnd   = "change_workmode_resume"
+
+	// Schedule Sub-types
+	ScheduleAddWindow       = "schedule_add"
+	ScheduleWindowDay       = "schedule_day"
+	ScheduleWindowFrom      = "schedule_from"
+	ScheduleWindowTo        = "schedule_to"
+	ScheduleDeleteWindow    = "schedule_del"
+	ScheduleAddException    = "schedule_exc"
+	ScheduleExceptionDate   = "schedule_date"
+	ScheduleExceptionOff    = "schedule_off"
+	ScheduleExceptionFrom   = "schedule_efrom"
+	ScheduleExceptionTo     = "schedule_eto"
+	ScheduleDeleteException = "schedule_edel"
+
+	// Contact Sub-types
+	ContactTemplate = "contact_tpl"
+	ContactCompose  = "contact_write"
+	ContactCancel   = "contact_cancel"
 
 	// Menu Sub-types
 	MenuMain = "menu_main"
+
+	// Cancel Reasons
+	CancelReasonRefused     = "refused"
+	CancelReasonUnreachable = "unreachable"
+	CancelReasonAddress     = "address"
+	CancelReasonOther       = "other"
+
+	// Contact Templates
+	ContactDownstairs = "downstairs"
+	ContactLate       = "late"
+	ContactAtDoor     = "at_door"
+	ContactNoAnswer   = "no_answer"
 )
 
 type OrderListItem struct {
 	ID      int
-	Status  string
+	Status  string // ключ каталога сообщений, см. orderStatus*
 	Address string
 	Time    string
 	Price   int
 }
 
+// OrderList — страница списка заказов на вкладке. Page считается с нуля.
+type OrderList struct {
+	Tab   models.CourierOrderTab
+	Page  int
+	Pages int
+	Items []OrderListItem
+}
+
 type BotConfig struct {
 	Token   string
 	Debug   bool
@@ -3,69 +3,162 @@ package bot
 import (
 	"fmt"
 	"log/slog"
-	"strings"
+	"slices"
+	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/navigation"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 type KeyboardManager struct {
-	log *slog.Logger
+	tr    i18n.Localizer
+	codec *callback.Codec
+	log   *slog.Logger
 }
 
-func NewkeyboardManager(log *slog.Logger) *KeyboardManager {
+func NewkeyboardManager(codec *callback.Codec, log *slog.Logger) *KeyboardManager {
 	return &KeyboardManager{
-		log: log,
+		tr:    i18n.For(i18n.Default),
+		codec: codec,
+		log:   log,
 	}
 }
 
+// WithLang возвращает менеджер, подписывающий кнопки на языке lang.
+func (km *KeyboardManager) WithLang(lang i18n.Lang) KeyboardManagerInterface {
+	return &KeyboardManager{
+		tr:    i18n.For(lang),
+		codec: km.codec,
+		log:   km.log,
+	}
+}
+
+// button кодирует data в callback кнопки. Данные ограничены по длине самими
+// действиями, так что ошибка здесь — ошибка в коде.
+func (km *KeyboardManager) button(text string, data callback.Data) tgbotapi.InlineKeyboardButton {
+	encoded, err := km.codec.Encode(data)
+	if err != nil {
+		km.log.Error("Failed to encode callback data", "action", data.Action, "error", err)
+	}
+
+	return tgbotapi.NewInlineKeyboardButtonData(text, encoded)
+}
+
 func (km *KeyboardManager) CreateAssignmentKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
 	km.log.Debug("Creating assignment keyboard for order", "orderID", orderID)
 
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Принять заказ", fmt.Sprintf("%s_%d", ActionAccept, orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить заказ", fmt.Sprintf("%s_%d", ActionReject, orderID)),
+			km.button(km.tr.T("button.accept_order"), callback.New(ActionAccept, orderID)),
+			km.button(km.tr.T("button.reject_order"), callback.New(ActionReject, orderID)),
 		),
 	)
 }
 
-func (km *KeyboardManager) CreateDeliveryKeyboard(orderID int, address, phone string) tgbotapi.InlineKeyboardMarkup {
+// Кнопки маршрута и связи не несут ни адрес, ни телефон: обработчики берут
+// их из заказа. «Забрал» и «В пути» сообщают клиенту о ходе доставки.
+func (km *KeyboardManager) CreateDeliveryKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup {
+	orderID := order.ID
 	km.log.Debug("Creating delivery keyboard for order", "orderID", orderID)
 
 	rows := [][]tgbotapi.InlineKeyboardButton{}
 
-	if address != "" {
+	if order.FullAddress() != "" {
 		navigationRow := tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗺️ Построить маршрут", fmt.Sprintf("nav_%d_%s", orderID, km.EscapeCallbackData(address))),
+			km.button(km.tr.T("button.route"), callback.New(ActionNavigate, orderID)),
 		)
 		rows = append(rows, navigationRow)
 	}
 
-	if phone != "" {
-		callRow := tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить клиенту", fmt.Sprintf("call_%d_%s", orderID, phone)),
+	if order.PhoneNumber != "" {
+		contactRow := tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.contact"), callback.New(ActionContact, orderID)),
 		)
-		rows = append(rows, callRow)
+		rows = append(rows, contactRow)
 	}
 
+	progressRow := tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.picked"), callback.New(StatusPicked, orderID)),
+		km.button(km.tr.T("button.on_the_way"), callback.New(StatusDelivering, orderID)),
+	)
+	rows = append(rows, progressRow)
+
 	completionRow := tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🏁 Доставка завершена", fmt.Sprintf("%s_%d", ActionComplete, orderID)),
-		tgbotapi.NewInlineKeyboardButtonData("🚨 Проблема с доставкой", fmt.Sprintf("%s_%d", ActionProblem, orderID)),
+		km.button(km.tr.T("button.complete"), callback.New(ActionComplete, orderID)),
+		km.button(km.tr.T("button.problem"), callback.New(ActionProblem, orderID)),
 	)
 	rows = append(rows, completionRow)
 
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func (km *KeyboardManager) CreateStatusKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
+func (km *KeyboardManager) CreateDeliveringKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup {
+	orderID := order.ID
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.route"), callback.New(ActionNavigate, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.contact"), callback.New(ActionContact, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.im_here"), callback.New(StatusArrived, orderID)),
+		),
+	)
+}
+
+func (km *KeyboardManager) CreateArrivedKeyboard(order *models.Order) tgbotapi.InlineKeyboardMarkup {
+	orderID := order.ID
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.contact"), callback.New(ActionContact, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.delivery_done"), callback.New(StatusDelivered, orderID)),
+			km.button(km.tr.T("button.problems"), callback.New(ActionProblem, orderID)),
+		),
+	)
+}
+
+// CreateContactKeyboard — готовые фразы для клиента, своё сообщение и
+// номер телефона.
+func (km *KeyboardManager) CreateContactKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
+	templateButton := func(template string) tgbotapi.InlineKeyboardButton {
+		return km.button(km.tr.T("contact.template."+template), callback.New(ContactTemplate, orderID).WithArg(template))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(templateButton(ContactDownstairs), templateButton(ContactAtDoor)),
+		tgbotapi.NewInlineKeyboardRow(templateButton(ContactLate)),
+		tgbotapi.NewInlineKeyboardRow(templateButton(ContactNoAnswer)),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.contact_write"), callback.New(ContactCompose, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.show_phone"), callback.New(ActionCall, orderID)),
+			km.button(km.tr.T("button.back"), callback.New(ActionBackToOrder, orderID)),
+		),
+	)
+}
+
+func (km *KeyboardManager) CreateComposeKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🚗 Забрал заказ", fmt.Sprintf("status_picked_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("🚚 В пути", fmt.Sprintf("status_delivery_%d", orderID)),
+			km.button(km.tr.T("button.cancel"), callback.New(ContactCancel, orderID)),
 		),
+	)
+}
+
+// CreatePhoneKeyboard прикрепляется к сообщению с номером клиента.
+func (km *KeyboardManager) CreatePhoneKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📍 На месте", fmt.Sprintf("status_arrived_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("✅ Доставлено", fmt.Sprintf("status_delivered_%d", orderID)),
+			km.button(km.tr.T("button.contact"), callback.New(ActionContact, orderID)),
 		),
 	)
 }
@@ -73,12 +166,12 @@ func (km *KeyboardManager) CreateStatusKeyboard(orderID int) tgbotapi.InlineKeyb
 func (km *KeyboardManager) CreateMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📋 Мои заказы"),
-			tgbotapi.NewKeyboardButton("ℹ️ Статус"),
+			tgbotapi.NewKeyboardButton(km.tr.T("menu.orders")),
+			tgbotapi.NewKeyboardButton(km.tr.T("menu.status")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("⚙️ Настройки"),
-			tgbotapi.NewKeyboardButton("🆘 Помощь"),
+			tgbotapi.NewKeyboardButton(km.tr.T("menu.settings")),
+			tgbotapi.NewKeyboardButton(km.tr.T("menu.help")),
 		),
 	)
 }
@@ -86,165 +179,433 @@ func (km *KeyboardManager) CreateMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup
 func (km *KeyboardManager) CreateSettingsKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔔 Уведомления", "settings_notifications"),
-			tgbotapi.NewInlineKeyboardButtonData("Режим работы", "settings_workmode"),
+			km.button(km.tr.T("settings.button.notifications"), callback.New(SettingsNotifications)),
+			km.button(km.tr.T("settings.button.workmode"), callback.New(SettingsWorkmode)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Контакты", "settings_contacts"),
-			tgbotapi.NewInlineKeyboardButtonData("↩️ Назад", "menu_main"),
+			km.button(km.tr.T("settings.button.schedule"), callback.New(SettingsSchedule)),
+			km.button(km.tr.T("settings.button.contacts"), callback.New(SettingsContacts)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("settings.button.language"), callback.New(SettingsLanguage)),
+			km.button(km.tr.T("settings.button.maps"), callback.New(SettingsMaps)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(MenuMain)),
 		),
 	)
 }
 
-func (km *KeyboardManager) CreateConfirmationKeyboard(action string, data interface{}) tgbotapi.InlineKeyboardMarkup {
-	callbackData := fmt.Sprintf("%s_confirm_%v", action, data)
+func (km *KeyboardManager) CreateLanguageKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, lang := range i18n.Languages {
+		label := i18n.For(lang).T("language.name")
+		if lang == km.tr.Lang() {
+			label = "• " + label + " •"
+		}
+		row = append(row, km.button(label, callback.New(SettingsLanguage).WithArg(string(lang))))
+	}
 
 	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(ActionSettings)),
+		),
+	)
+}
+
+// CreateNotificationSettingsKeyboard — по кнопке-переключателю на каждую
+// настройку, отметка показывает текущее значение.
+func (km *KeyboardManager) CreateNotificationSettingsKeyboard(settings *models.NotificationSettings) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, setting := range models.NotificationSettingsOrder {
+		mark := "⬜ "
+		if settings.Enabled(setting) {
+			mark = "✅ "
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(mark+km.tr.T("notifications.setting."+string(setting)), callback.New(SettingsNotifications).WithArg(string(setting))),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("settings.button.language"), callback.New(SettingsLanguage)),
+		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", callbackData),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "cancel_action"),
+			km.button(km.tr.T("button.back"), callback.New(ActionSettings)),
 		),
 	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func (km *KeyboardManager) CreateOrderListKeyboard(orders []OrderListItem) tgbotapi.InlineKeyboardMarkup {
+func (km *KeyboardManager) CreateMapAppKeyboard(current navigation.App) tgbotapi.InlineKeyboardMarkup {
 	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, app := range navigation.Apps {
+		label := km.tr.T("maps.app." + string(app))
+		if app == current {
+			label = "• " + label + " •"
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(label, callback.New(SettingsMaps).WithArg(string(app))),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.back"), callback.New(ActionSettings)),
+	))
 
-	for _, order := range orders {
-		row := tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("📦 Заказ #%d - %s", order.ID, order.Status),
-				fmt.Sprintf("order_details_%d", order.ID),
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// CreateNavigationKeyboard — URL-кнопки, открывающие маршрут до address в
+// приложениях apps, и кнопка выбора приложения.
+func (km *KeyboardManager) CreateNavigationKeyboard(apps []navigation.App, address string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, app := range apps {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(
+				km.tr.T("maps.open."+string(app)),
+				navigation.RouteURL(app, address),
 			),
-		)
-		rows = append(rows, row)
+		))
 	}
 
-	refreshRow := tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", "refresh_orders"),
-		tgbotapi.NewInlineKeyboardButtonData("↩️ Назад", "menu_main"),
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("maps.change"), callback.New(SettingsMaps)),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// CreateConfirmationKeyboard — подтверждение действия над id: confirm и
+// cancel получают id первым аргументом.
+func (km *KeyboardManager) CreateConfirmationKeyboard(confirm, cancel string, id int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.confirm"), callback.New(confirm, id)),
+			km.button(km.tr.T("button.cancel"), callback.New(cancel, id)),
+		),
 	)
-	rows = append(rows, refreshRow)
+}
+
+// orderListTabs — порядок вкладок в списке заказов.
+var orderListTabs = []models.CourierOrderTab{
+	models.CourierOrdersActive,
+	models.CourierOrdersToday,
+	models.CourierOrdersHistory,
+}
+
+// CreateOrderListKeyboard: у активных заказов под кнопкой заказа идут быстрые
+// действия. Адрес и телефон обработчик берёт из заказа.
+func (km *KeyboardManager) CreateOrderListKeyboard(list OrderList) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for _, order := range list.Items {
+		if list.Tab != models.CourierOrdersActive {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				km.button(
+					km.tr.T("orders.item_done", order.ID, order.Time, order.Price),
+					callback.New(ActionOrderDetails, order.ID),
+				),
+			))
+			continue
+		}
+
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(
+				km.button(
+					km.tr.T("orders.item", order.ID, km.tr.T(order.Status)),
+					callback.New(ActionOrderDetails, order.ID),
+				),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				km.button(km.tr.T("button.route_short"), callback.New(ActionNavigate, order.ID)),
+				km.button(km.tr.T("button.call"), callback.New(ActionCall, order.ID)),
+				km.button(km.tr.T("button.delivered"), callback.New(StatusDelivered, order.ID)),
+			),
+		)
+	}
+
+	var pager []tgbotapi.InlineKeyboardButton
+	if list.Page > 0 {
+		pager = append(pager, km.button(km.tr.T("button.prev"), orderListData(list.Tab, list.Page-1)))
+	}
+	pager = append(pager, km.button(km.tr.T("orders.page", list.Page+1, max(list.Pages, 1)), orderListData(list.Tab, list.Page)))
+	if list.Page+1 < list.Pages {
+		pager = append(pager, km.button(km.tr.T("button.next"), orderListData(list.Tab, list.Page+1)))
+	}
+	rows = append(rows, pager)
+
+	var tabs []tgbotapi.InlineKeyboardButton
+	for _, tab := range orderListTabs {
+		label := km.tr.T("orders.tab." + string(tab))
+		if tab == list.Tab {
+			label = km.tr.T("orders.tab_current", label)
+		}
+		tabs = append(tabs, km.button(label, orderListData(tab, 0)))
+	}
+	rows = append(rows, tabs)
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.back"), callback.New(MenuMain)),
+	))
 
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
+func orderListData(tab models.CourierOrderTab, page int) callback.Data {
+	return callback.New(ActionOrderList, page).WithArg(string(tab))
+}
+
 func (km *KeyboardManager) CreateProblemKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📞 Клиент не отвечает", fmt.Sprintf("problem_noanswer_%d", orderID)),
+			km.button(km.tr.T("problem.no_answer"), callback.New(ProblemNoAnswer, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("problem.wrong_address"), callback.New(ProblemWrongAddress, orderID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🏠 Неверный адрес", fmt.Sprintf("problem_wrongaddress_%d", orderID)),
+			km.button(km.tr.T("problem.payment"), callback.New(ProblemPayment, orderID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("💳 Проблема с оплатой", fmt.Sprintf("problem_payment_%d", orderID)),
+			km.button(km.tr.T("problem.technical"), callback.New(ProblemTechnical, orderID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🚗 Технические проблемы", fmt.Sprintf("problem_technical_%d", orderID)),
+			km.button(km.tr.T("button.cancel_order"), callback.New(ActionCancelOrder, orderID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("❔ Другое", fmt.Sprintf("problem_other_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("↩️ Назад", fmt.Sprintf("back_to_order_%d", orderID)),
+			km.button(km.tr.T("problem.other"), callback.New(ProblemOther, orderID)),
+			km.button(km.tr.T("button.back"), callback.New(ActionBackToOrder, orderID)),
 		),
 	)
 }
 
-func (km *KeyboardManager) CreateYesNoKeyboard(action string, id int) tgbotapi.InlineKeyboardMarkup {
+func (km *KeyboardManager) CreateCancelReasonKeyboard(orderID int) tgbotapi.InlineKeyboardMarkup {
+	reasonButton := func(text, reason string) tgbotapi.InlineKeyboardButton {
+		return km.button(text, callback.New(ActionCancelReason, orderID).WithArg(reason))
+	}
+
 	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(reasonButton(km.tr.T("cancel_reason.refused"), CancelReasonRefused)),
+		tgbotapi.NewInlineKeyboardRow(reasonButton(km.tr.T("cancel_reason.unreachable"), CancelReasonUnreachable)),
+		tgbotapi.NewInlineKeyboardRow(reasonButton(km.tr.T("cancel_reason.address"), CancelReasonAddress)),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Да", fmt.Sprintf("%s_yes_%d", action, id)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Нет", fmt.Sprintf("%s_no_%d", action, id)),
+			reasonButton(km.tr.T("cancel_reason.other"), CancelReasonOther),
+			km.button(km.tr.T("button.back"), callback.New(ActionBackToOrder, orderID)),
 		),
 	)
 }
 
-func (km *KeyboardManager) CreateChangeWorkmodeKeyboard(isActive bool) tgbotapi.InlineKeyboardMarkup {
-	changeMsg := "Закончить смену"
-	if !isActive {
-		changeMsg = "Начать смену"
+func (km *KeyboardManager) CreateChangeWorkmodeKeyboard(onShift, onBreak bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	switch {
+	case !onShift:
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("workmode.button.start"), callback.New(WorkmodeShiftStart)),
+		))
+	case onBreak:
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("workmode.button.resume"), callback.New(WorkmodeBreakEnd)),
+		))
+	default:
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("workmode.button.break"), callback.New(WorkmodeBreakStart)),
+			km.button(km.tr.T("workmode.button.end"), callback.New(WorkmodeShiftEnd)),
+		))
 	}
 
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.back"), callback.New(ActionSettings)),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (km *KeyboardManager) CreateNextActionsKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(changeMsg, fmt.Sprintf("change_workmode_%t", isActive)),
+			km.button(km.tr.T("menu.orders"), callback.New(ActionRefreshOrders)),
+			km.button(km.tr.T("button.stats"), callback.New(ActionStats)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("↩️ Назад", "settings"),
+			km.button(km.tr.T("button.new_order"), callback.New(ActionRefreshOrders)),
+			km.button(km.tr.T("menu.settings"), callback.New(ActionSettings)),
 		),
 	)
 }
 
-func (km *KeyboardManager) RemoveKeyboard() tgbotapi.ReplyKeyboardRemove {
-	return tgbotapi.NewRemoveKeyboard(true)
+func (km *KeyboardManager) CreateStatsShortcutKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.stats"), callback.New(ActionStats)),
+		),
+	)
 }
 
-func (km *KeyboardManager) ParseCallbackData(callbackData string) (action string, id int, err error) {
-	parts := strings.Split(callbackData, "_")
-	if len(parts) < 2 {
-		return "", 0, fmt.Errorf("invalid callback data format: %s", callbackData)
+func (km *KeyboardManager) CreateStatsKeyboard(period models.StatsPeriod) tgbotapi.InlineKeyboardMarkup {
+	periods := []struct {
+		period models.StatsPeriod
+		label  string
+		action string
+	}{
+		{models.StatsPeriodToday, km.tr.T("stats.button.today"), StatsToday},
+		{models.StatsPeriodWeek, km.tr.T("stats.button.week"), StatsWeek},
+		{models.StatsPeriodMonth, km.tr.T("stats.button.month"), StatsMonth},
 	}
 
-	action = parts[0]
-
-	if len(parts) > 1 {
-		var extractedID int
-		_, err := fmt.Sscanf(parts[len(parts)-1], "%d", &extractedID)
-		if err == nil {
-			id = extractedID
+	var row []tgbotapi.InlineKeyboardButton
+	for _, p := range periods {
+		label := p.label
+		if p.period == period {
+			label = "• " + label + " •"
 		}
+		row = append(row, km.button(label, callback.New(p.action)))
 	}
 
-	return action, id, nil
+	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.feedback"), callback.New(ActionFeedback)),
+			km.button(km.tr.T("menu.orders"), callback.New(ActionRefreshOrders)),
+		),
+	)
+}
+
+func (km *KeyboardManager) CreateFeedbackKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(StatsToday)),
+			km.button(km.tr.T("menu.orders"), callback.New(ActionRefreshOrders)),
+		),
+	)
 }
 
-// escapeCallbackData экранирует данные для callback_data
-// В Telegram callback_data не может превышать 64 байта и содержать некоторые символы
-func (km *KeyboardManager) EscapeCallbackData(data string) string {
-	if len(data) > 50 {
-		data = data[:50]
+// scheduleWeekdays — порядок дней в выборе окна, неделя начинается с понедельника.
+var scheduleWeekdays = []time.Weekday{
+	time.Monday,
+	time.Tuesday,
+	time.Wednesday,
+	time.Thursday,
+	time.Friday,
+	time.Saturday,
+	time.Sunday,
+}
+
+func (km *KeyboardManager) CreateScheduleKeyboard(availability *models.CourierAvailability) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for _, window := range availability.Windows {
+		label := km.tr.T("schedule.button.delete_window", km.tr.Weekday(window.Weekday), window.String())
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(label, callback.New(ScheduleDeleteWindow, window.ID)),
+		))
 	}
 
-	replacements := map[string]string{
-		" ":  "_",
-		"\n": "",
-		"\t": "",
-		",":  "",
-		".":  "",
-		":":  "",
-		";":  "",
+	for _, exception := range availability.Exceptions {
+		label := km.tr.T("schedule.button.delete_day_off", exception.Day.Format("02.01"))
+		if exception.Available {
+			label = km.tr.T("schedule.button.delete_hours", exception.Day.Format("02.01"), models.FormatMinutes(*exception.StartMinute), models.FormatMinutes(*exception.EndMinute))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			km.button(label, callback.New(ScheduleDeleteException, exception.ID)),
+		))
 	}
 
-	for old, new := range replacements {
-		data = strings.ReplaceAll(data, old, new)
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("schedule.button.add_window"), callback.New(ScheduleAddWindow)),
+			km.button(km.tr.T("schedule.button.add_exception"), callback.New(ScheduleAddException)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(ActionSettings)),
+		),
+	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (km *KeyboardManager) CreateWeekdayKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, weekday := range scheduleWeekdays {
+		row = append(row, km.button(km.tr.Weekday(weekday), callback.New(ScheduleWindowDay, int(weekday))))
 	}
 
-	return data
+	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(ActionSchedule)),
+		),
+	)
 }
 
-func (km *KeyboardManager) GetActionFromCallback(callback string) string {
-	prefixes := []string{
-		ActionAccept,
-		ActionReject,
-		ActionComplete,
-		ActionProblem,
-		ActionNavigate,
-		ActionCall,
-		ActionStatus,
-		ActionSettings,
-		ActionConfirm,
-		ActionConfirm,
-		ActionRefresh,
-		ActionMenu,
-		ActionOrderDetails,
-		ActionBackToOrder,
-		ActionChangeWorkmode,
+// CreateHourKeyboard — сетка часов fromHour..toHour включительно, к IDs
+// next дописывается выбранный час.
+func (km *KeyboardManager) CreateHourKeyboard(next callback.Data, fromHour, toHour int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+
+	for hour := fromHour; hour <= toHour; hour++ {
+		data := next
+		data.IDs = append(slices.Clip(next.IDs), hour)
+		row = append(row, km.button(fmt.Sprintf("%02d:00", hour), data))
+		if len(row) == 6 {
+			rows = append(rows, row)
+			row = nil
+		}
 	}
 
-	for _, prefix := range prefixes {
-		if strings.HasPrefix(callback, prefix) {
-			return prefix
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.back"), callback.New(ActionSchedule)),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (km *KeyboardManager) CreateExceptionDateKeyboard(today time.Time, days int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+
+	for i := 0; i < days; i++ {
+		day := today.AddDate(0, 0, i)
+		label := fmt.Sprintf("%s %s", km.tr.Weekday(day.Weekday()), day.Format("02.01"))
+		row = append(row, km.button(label, callback.New(ScheduleExceptionDate, scheduleDateID(day))))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
 		}
 	}
-	return "unknown"
+
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		km.button(km.tr.T("button.back"), callback.New(ActionSchedule)),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (km *KeyboardManager) CreateExceptionTypeKeyboard(day int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("schedule.button.day_off"), callback.New(ScheduleExceptionOff, day)),
+			km.button(km.tr.T("schedule.button.other_hours"), callback.New(ScheduleExceptionFrom, day)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			km.button(km.tr.T("button.back"), callback.New(ActionSchedule)),
+		),
+	)
+}
+
+func (km *KeyboardManager) RemoveKeyboard() tgbotapi.ReplyKeyboardRemove {
+	return tgbotapi.NewRemoveKeyboard(true)
 }
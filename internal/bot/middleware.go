@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// recoverPanics не даёт панике в обработчике уронить цикл обновлений.
+func (h *Handlers) recoverPanics(next CallbackHandler) CallbackHandler {
+	return func(ctx context.Context, req *CallbackRequest) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				h.log.Error("Panic in callback handler", "chatID", req.ChatID, "action", req.Data.Action, "panic", recovered, "stack", string(debug.Stack()))
+				req.Bot.SendMessage(req.ChatID, h.tr(req.ChatID).T("error.server"))
+			}
+		}()
+
+		next(ctx, req)
+	}
+}
+
+func (h *Handlers) logCallbacks(next CallbackHandler) CallbackHandler {
+	return func(ctx context.Context, req *CallbackRequest) {
+		start := time.Now()
+		next(ctx, req)
+		h.log.Debug("Callback handled", "chatID", req.ChatID, "action", req.Data.Action, "ids", req.Data.IDs, "duration", time.Since(start))
+	}
+}
+
+// requireCourier пропускает только зарегистрированных курьеров.
+func (h *Handlers) requireCourier(next CallbackHandler) CallbackHandler {
+	return func(ctx context.Context, req *CallbackRequest) {
+		courier, err := h.assignmentService.GetCourierByChatID(ctx, req.ChatID)
+		if err != nil {
+			h.log.Warn("Callback from unknown courier", "chatID", req.ChatID, "action", req.Data.Action, "error", err)
+			req.Bot.SendMessage(req.ChatID, h.tr(req.ChatID).T("error.access"))
+			return
+		}
+
+		req.Courier = courier
+		next(ctx, req)
+	}
+}
+
+// requireOwnOrder загружает заказ из первого ID и проверяет, что он не
+// отменён и назначен этому курьеру. Ставится после requireCourier.
+func (h *Handlers) requireOwnOrder(next CallbackHandler) CallbackHandler {
+	return func(ctx context.Context, req *CallbackRequest) {
+		order, ok := h.getActiveOrder(ctx, req.Bot, req.ChatID, req.Data.IDs[0])
+		if !ok {
+			return
+		}
+
+		if order.CourierID == nil || *order.CourierID != req.Courier.ID {
+			h.log.Warn("Courier tried to act on foreign order", "chatID", req.ChatID, "orderID", order.ID, "action", req.Data.Action)
+			req.Bot.SendMessage(req.ChatID, h.tr(req.ChatID).T("order.not_yours"))
+			return
+		}
+
+		req.Order = order
+		next(ctx, req)
+	}
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CallbackRequest — разобранное нажатие inline-кнопки. Courier и Order
+// заполняют middleware маршрута, обработчик получает их готовыми.
+type CallbackRequest struct {
+	Bot       BotInterface
+	Query     *tgbotapi.CallbackQuery
+	ChatID    int64
+	MessageID int
+	Data      callback.Data
+	Courier   *models.Courier
+	Order     *models.Order
+}
+
+type CallbackHandler func(ctx context.Context, req *CallbackRequest)
+
+type CallbackMiddleware func(next CallbackHandler) CallbackHandler
+
+// Route связывает действие кнопки с обработчиком. IDs — минимальное число
+// числовых аргументов, Arg — обязателен ли строковый аргумент. Если данных
+// не хватает, обработчик не вызывается.
+type Route struct {
+	Action     string
+	IDs        int
+	Arg        bool
+	Handler    CallbackHandler
+	Middleware []CallbackMiddleware
+}
+
+func (r Route) accepts(data callback.Data) bool {
+	return len(data.IDs) >= r.IDs && (!r.Arg || data.Arg != "")
+}
+
+// Router выбирает маршрут по точному совпадению действия, без префиксов.
+type Router struct {
+	routes     map[string]Route
+	handlers   map[string]CallbackHandler
+	middleware []CallbackMiddleware
+	fallback   CallbackHandler
+	unmatched  CallbackHandler
+}
+
+// NewRouter создаёт роутер. fallback получает нажатия без маршрута или с
+// неполными данными, middleware оборачивают все маршруты, включая fallback.
+func NewRouter(fallback CallbackHandler, middleware ...CallbackMiddleware) *Router {
+	return &Router{
+		routes:     make(map[string]Route),
+		handlers:   make(map[string]CallbackHandler),
+		middleware: middleware,
+		fallback:   fallback,
+		unmatched:  chain(fallback, middleware),
+	}
+}
+
+// Handle регистрирует маршруты. Повторная регистрация действия — ошибка в
+// коде, поэтому, как и http.ServeMux, Handle паникует.
+func (r *Router) Handle(routes ...Route) {
+	for _, route := range routes {
+		if route.Action == "" || route.Handler == nil {
+			panic("bot: route without action or handler")
+		}
+		if _, exists := r.routes[route.Action]; exists {
+			panic(fmt.Sprintf("bot: duplicate route for action %q", route.Action))
+		}
+
+		handler := chain(route.Handler, route.Middleware)
+		r.routes[route.Action] = route
+		r.handlers[route.Action] = chain(func(ctx context.Context, req *CallbackRequest) {
+			if !route.accepts(req.Data) {
+				r.fallback(ctx, req)
+				return
+			}
+			handler(ctx, req)
+		}, r.middleware)
+	}
+}
+
+func (r *Router) Dispatch(ctx context.Context, req *CallbackRequest) {
+	handler, ok := r.handlers[req.Data.Action]
+	if !ok {
+		r.unmatched(ctx, req)
+		return
+	}
+
+	handler(ctx, req)
+}
+
+// Match проверяет, что у data есть маршрут и данных ему хватает.
+func (r *Router) Match(data callback.Data) error {
+	route, ok := r.routes[data.Action]
+	if !ok {
+		return fmt.Errorf("no route for action %q", data.Action)
+	}
+
+	if !route.accepts(data) {
+		return fmt.Errorf("action %q: route needs %d ids and arg=%t, button has %d ids and arg %q", data.Action, route.IDs, route.Arg, len(data.IDs), data.Arg)
+	}
+
+	return nil
+}
+
+// chain оборачивает handler так, что первый middleware выполняется первым.
+func chain(handler CallbackHandler, middleware []CallbackMiddleware) CallbackHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+// routes — таблица маршрутов inline-кнопок. Каждое действие, которое
+// умеет создавать KeyboardManager, должно быть здесь, это проверяет
+// TestEveryButtonHasRoute.
+func (h *Handlers) routes() []Route {
+	courier := []CallbackMiddleware{h.requireCourier}
+	ownOrder := []CallbackMiddleware{h.requireCourier, h.requireOwnOrder}
+
+	orderRoute := func(action string, handle func(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int)) Route {
+		return Route{Action: action, IDs: 1, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			handle(ctx, req.Bot, req.ChatID, req.Order, req.MessageID)
+		}}
+	}
+
+	statusRoute := func(action string) Route {
+		return orderRoute(action, func(ctx context.Context, bot BotInterface, chatID int64, order *models.Order, messageID int) {
+			h.HandleStatusUpdate(ctx, bot, chatID, action, order, messageID)
+		})
+	}
+
+	actionRoute := func(action string, handle func(ctx context.Context, req *CallbackRequest)) Route {
+		return Route{Action: action, Middleware: courier, Handler: handle}
+	}
+
+	settings := func(ctx context.Context, req *CallbackRequest) {
+		h.HandleSettings(ctx, req.Bot, req.ChatID, req.Data)
+	}
+	workmode := func(ctx context.Context, req *CallbackRequest) {
+		h.HandleChangeWorkmode(ctx, req.Bot, req.ChatID, req.Data.Action)
+	}
+	stats := func(ctx context.Context, req *CallbackRequest) {
+		h.HandleStatistics(ctx, req.Bot, req.ChatID, req.Data.Action, req.MessageID)
+	}
+	schedule := func(ids int) func(action string) Route {
+		return func(action string) Route {
+			return Route{Action: action, IDs: ids, Middleware: courier, Handler: func(ctx context.Context, req *CallbackRequest) {
+				h.HandleSchedule(ctx, req.Bot, req.ChatID, req.Data)
+			}}
+		}
+	}
+
+	return []Route{
+		{Action: ActionAccept, IDs: 1, Middleware: courier, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleAcceptOrder(ctx, req.Bot, req.ChatID, req.Data.IDs[0], req.MessageID)
+		}},
+		{Action: ActionReject, IDs: 1, Middleware: courier, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleRejectOrder(ctx, req.Bot, req.ChatID, req.Data.IDs[0], req.MessageID)
+		}},
+
+		orderRoute(ActionComplete, h.HandleCompleteOrder),
+		orderRoute(ActionProblem, h.HandleProblemOrder),
+		orderRoute(ProblemNoAnswer, h.HandleProblemOrder),
+		orderRoute(ProblemWrongAddress, h.HandleProblemOrder),
+		orderRoute(ProblemPayment, h.HandleProblemOrder),
+		orderRoute(ProblemTechnical, h.HandleProblemOrder),
+		orderRoute(ProblemOther, h.HandleProblemOrder),
+		orderRoute(ActionOrderDetails, h.HandleOrderDetails),
+		orderRoute(ActionBackToOrder, h.HandleOrderDetails),
+		orderRoute(ActionConfirmDelivery, h.HandleDeliveryConfirmation),
+		orderRoute(ActionCancelDelivery, h.HandleDeliveryCancel),
+		orderRoute(ActionCancelOrder, h.HandleCancelOrder),
+		{Action: ActionNavigate, IDs: 1, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleNavigation(req.Bot, req.ChatID, req.Courier, req.Order)
+		}},
+		{Action: ActionCall, IDs: 1, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleCallCustomer(ctx, req.Bot, req.ChatID, req.Order)
+		}},
+		orderRoute(ActionContact, h.HandleContact),
+		{Action: ContactTemplate, IDs: 1, Arg: true, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleContactTemplate(ctx, req.Bot, req.ChatID, req.Order.ID, req.Data.Arg)
+		}},
+		{Action: ContactCompose, IDs: 1, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleContactCompose(req.Bot, req.ChatID, req.Order.ID)
+		}},
+		{Action: ContactCancel, IDs: 1, Middleware: courier, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleContactCancel(req.Bot, req.ChatID)
+		}},
+		{Action: ActionCancelReason, IDs: 1, Arg: true, Middleware: ownOrder, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleCancelReason(ctx, req.Bot, req.ChatID, req.Order.ID, req.Data.Arg)
+		}},
+
+		statusRoute(StatusPicked),
+		statusRoute(StatusDelivering),
+		statusRoute(StatusArrived),
+		statusRoute(StatusDelivered),
+
+		actionRoute(ActionSettings, settings),
+		actionRoute(SettingsNotifications, func(ctx context.Context, req *CallbackRequest) {
+			h.HandleNotificationSettings(ctx, req.Bot, req.ChatID, req.Data.Arg, req.MessageID)
+		}),
+		actionRoute(SettingsWorkmode, settings),
+		actionRoute(SettingsContacts, settings),
+		actionRoute(SettingsSchedule, settings),
+		actionRoute(SettingsLanguage, settings),
+		actionRoute(SettingsMaps, settings),
+
+		actionRoute(WorkmodeShiftStart, workmode),
+		actionRoute(WorkmodeShiftEnd, workmode),
+		actionRoute(WorkmodeBreakStart, workmode),
+		actionRoute(WorkmodeBreakEnd, workmode),
+
+		actionRoute(ActionStats, stats),
+		actionRoute(StatsToday, stats),
+		actionRoute(StatsWeek, stats),
+		actionRoute(StatsMonth, stats),
+		actionRoute(ActionFeedback, func(ctx context.Context, req *CallbackRequest) {
+			h.HandleFeedback(ctx, req.Bot, req.ChatID, req.MessageID)
+		}),
+
+		actionRoute(ActionRefreshOrders, func(ctx context.Context, req *CallbackRequest) {
+			h.HandleRefresh(ctx, req.Bot, req.ChatID, req.MessageID)
+		}),
+		{Action: ActionOrderList, IDs: 1, Arg: true, Middleware: courier, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleOrderList(ctx, req.Bot, req.ChatID, req.Data.Arg, req.Data.IDs[0], req.MessageID)
+		}},
+		// Главное меню регистрирует нового курьера, поэтому оно доступно без
+		// проверки.
+		{Action: MenuMain, Handler: func(ctx context.Context, req *CallbackRequest) {
+			h.HandleMenu(ctx, req.Bot, req.ChatID)
+		}},
+
+		schedule(0)(ActionSchedule),
+		schedule(0)(ScheduleAddWindow),
+		schedule(1)(ScheduleWindowDay),
+		schedule(2)(ScheduleWindowFrom),
+		schedule(3)(ScheduleWindowTo),
+		schedule(1)(ScheduleDeleteWindow),
+		schedule(0)(ScheduleAddException),
+		schedule(1)(ScheduleExceptionDate),
+		schedule(1)(ScheduleExceptionOff),
+		schedule(1)(ScheduleExceptionFrom),
+		schedule(3)(ScheduleExceptionTo),
+		schedule(1)(ScheduleDeleteException),
+	}
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/navigation"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestEveryButtonHasRoute собирает все клавиатуры KeyboardManager на
+// тестовых данных и проверяет, что каждая кнопка декодируется и попадает
+// в свой маршрут.
+func TestEveryButtonHasRoute(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	codec := callback.NewCodec("secret")
+	h := NewHandlers(nil, nil, NewkeyboardManager(codec, log), codec, log)
+
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	start, end := 9*60, 18*60
+	day := scheduleDateID(now)
+	order := &models.Order{ID: 1, City: "Москва", Address: "Тверская, 1", PhoneNumber: "+70000000000"}
+
+	var keyboards []tgbotapi.InlineKeyboardMarkup
+	for _, lang := range i18n.Languages {
+		km := h.keyboardManager.WithLang(lang)
+
+		keyboards = append(keyboards,
+			km.CreateAssignmentKeyboard(1),
+			km.CreateDeliveryKeyboard(order),
+			km.CreateDeliveringKeyboard(order),
+			km.CreateArrivedKeyboard(order),
+			km.CreateContactKeyboard(1),
+			km.CreateComposeKeyboard(1),
+			km.CreatePhoneKeyboard(1),
+			km.CreateSettingsKeyboard(),
+			km.CreateLanguageKeyboard(),
+			km.CreateNotificationSettingsKeyboard(models.DefaultNotificationSettings(1)),
+			km.CreateMapAppKeyboard(navigation.Yandex),
+			km.CreateNavigationKeyboard(navigation.Apps, order.FullAddress()),
+			km.CreateConfirmationKeyboard(ActionConfirmDelivery, ActionCancelDelivery, 1),
+			km.CreateOrderListKeyboard(OrderList{Tab: models.CourierOrdersActive, Page: 1, Pages: 3, Items: []OrderListItem{{ID: 1, Status: orderStatusAccepted}}}),
+			km.CreateOrderListKeyboard(OrderList{Tab: models.CourierOrdersHistory, Pages: 2, Items: []OrderListItem{{ID: 1, Status: orderStatusDelivered}}}),
+			km.CreateProblemKeyboard(1),
+			km.CreateChangeWorkmodeKeyboard(false, false),
+			km.CreateChangeWorkmodeKeyboard(true, false),
+			km.CreateChangeWorkmodeKeyboard(true, true),
+			km.CreateCancelReasonKeyboard(1),
+			km.CreateStatsKeyboard(models.StatsPeriodToday),
+			km.CreateFeedbackKeyboard(),
+			km.CreateNextActionsKeyboard(),
+			km.CreateStatsShortcutKeyboard(),
+			km.CreateScheduleKeyboard(&models.CourierAvailability{
+				Windows: []*models.AvailabilityWindow{{ID: 1, Weekday: time.Monday, StartMinute: start, EndMinute: end}},
+				Exceptions: []*models.AvailabilityException{
+					{ID: 1, Day: now},
+					{ID: 2, Day: now, Available: true, StartMinute: &start, EndMinute: &end},
+				},
+			}),
+			km.CreateWeekdayKeyboard(),
+			// Следующие шаги те же, что передаёт HandleSchedule.
+			km.CreateHourKeyboard(callback.New(ScheduleWindowFrom, int(time.Monday)), 0, 23),
+			km.CreateHourKeyboard(callback.New(ScheduleWindowTo, int(time.Monday), 9), 10, 24),
+			km.CreateHourKeyboard(callback.New(ScheduleExceptionFrom, day), 0, 23),
+			km.CreateHourKeyboard(callback.New(ScheduleExceptionTo, day, 9), 10, 24),
+			km.CreateExceptionDateKeyboard(now, scheduleExceptionDays),
+			km.CreateExceptionTypeKeyboard(day),
+		)
+	}
+
+	for _, keyboard := range keyboards {
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				if button.URL != nil {
+					continue
+				}
+
+				if button.CallbackData == nil || *button.CallbackData == "" {
+					t.Errorf("button %q has no callback data", button.Text)
+					continue
+				}
+
+				data, err := h.codec.Decode(*button.CallbackData)
+				if err != nil {
+					t.Errorf("button %q: %v", button.Text, err)
+					continue
+				}
+
+				if err := h.router.Match(data); err != nil {
+					t.Errorf("button %q: %v", button.Text, err)
+				}
+			}
+		}
+	}
+}
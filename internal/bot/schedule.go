@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+)
+
+const scheduleExceptionDays = 14
+
+// HandleSchedule ведёт курьера по экранам расписания. Выбор окна
+// накапливается в IDs callback: schedule_from [день, с] превращается
+// в schedule_to [день, с, до], и только последний шаг пишет в базу.
+// Дата исключения передаётся числом вида 20060102.
+func (h *Handlers) HandleSchedule(ctx context.Context, bot BotInterface, chatID int64, data callback.Data) {
+	args := data.IDs
+
+	tr := h.tr(chatID)
+	keyboards := h.keyboards(chatID)
+
+	var err error
+
+	switch {
+	case data.Action == ScheduleAddWindow:
+		bot.SendMessageWithInlineKeyboard(chatID, tr.T("schedule.new_window"), keyboards.CreateWeekdayKeyboard())
+	case data.Action == ScheduleWindowDay && len(args) == 1:
+		h.sendHourPicker(bot, chatID, tr.T("schedule.from_hour"), callback.New(ScheduleWindowFrom, args[0]), 0, 23)
+	case data.Action == ScheduleWindowFrom && len(args) == 2:
+		h.sendHourPicker(bot, chatID, tr.T("schedule.to_hour"), callback.New(ScheduleWindowTo, args...), args[1]+1, 24)
+	case data.Action == ScheduleWindowTo && len(args) == 3:
+		err = h.assignmentService.AddAvailabilityWindow(ctx, chatID, time.Weekday(args[0]), args[1]*60, args[2]*60)
+		h.afterScheduleChange(ctx, bot, chatID, err, tr.T("schedule.window_added"))
+	case data.Action == ScheduleDeleteWindow && len(args) == 1:
+		err = h.assignmentService.RemoveAvailabilityWindow(ctx, chatID, args[0])
+		h.afterScheduleChange(ctx, bot, chatID, err, tr.T("schedule.window_removed"))
+	case data.Action == ScheduleAddException:
+		keyboard := keyboards.CreateExceptionDateKeyboard(h.assignmentService.Now(), scheduleExceptionDays)
+		bot.SendMessageWithInlineKeyboard(chatID, tr.T("schedule.new_exception"), keyboard)
+	case data.Action == ScheduleExceptionDate && len(args) == 1:
+		bot.SendMessageWithInlineKeyboard(chatID, tr.T("schedule.exception_type"), keyboards.CreateExceptionTypeKeyboard(args[0]))
+	case data.Action == ScheduleExceptionOff && len(args) == 1:
+		day, ok := h.parseScheduleDay(bot, chatID, args[0])
+		if !ok {
+			return
+		}
+		err = h.assignmentService.AddAvailabilityException(ctx, chatID, day, false, 0, 0)
+		h.afterScheduleChange(ctx, bot, chatID, err, tr.T("schedule.day_off_added"))
+	case data.Action == ScheduleExceptionFrom && len(args) == 1:
+		h.sendHourPicker(bot, chatID, tr.T("schedule.from_hour"), callback.New(ScheduleExceptionFrom, args[0]), 0, 23)
+	case data.Action == ScheduleExceptionFrom && len(args) == 2:
+		h.sendHourPicker(bot, chatID, tr.T("schedule.to_hour"), callback.New(ScheduleExceptionTo, args...), args[1]+1, 24)
+	case data.Action == ScheduleExceptionTo && len(args) == 3:
+		day, ok := h.parseScheduleDay(bot, chatID, args[0])
+		if !ok {
+			return
+		}
+		err = h.assignmentService.AddAvailabilityException(ctx, chatID, day, true, args[1]*60, args[2]*60)
+		h.afterScheduleChange(ctx, bot, chatID, err, tr.T("schedule.exception_added"))
+	case data.Action == ScheduleDeleteException && len(args) == 1:
+		err = h.assignmentService.RemoveAvailabilityException(ctx, chatID, args[0])
+		h.afterScheduleChange(ctx, bot, chatID, err, tr.T("schedule.exception_removed"))
+	default:
+		h.showSchedule(ctx, bot, chatID)
+	}
+}
+
+// scheduleDateID упаковывает дату исключения в число для callback.
+func scheduleDateID(day time.Time) int {
+	return day.Year()*10000 + int(day.Month())*100 + day.Day()
+}
+
+func (h *Handlers) parseScheduleDay(bot BotInterface, chatID int64, value int) (time.Time, bool) {
+	day := time.Date(value/10000, time.Month(value/100%100), value%100, 0, 0, 0, 0, h.assignmentService.Now().Location())
+	if scheduleDateID(day) != value {
+		h.log.Warn("Invalid schedule date", "value", value)
+		h.HandleUnknownCommand(bot, chatID)
+		return time.Time{}, false
+	}
+
+	return day, true
+}
+
+func (h *Handlers) sendHourPicker(bot BotInterface, chatID int64, title string, next callback.Data, fromHour, toHour int) {
+	if fromHour > toHour {
+		h.HandleUnknownCommand(bot, chatID)
+		return
+	}
+
+	bot.SendMessageWithInlineKeyboard(chatID, title, h.keyboards(chatID).CreateHourKeyboard(next, fromHour, toHour))
+}
+
+func (h *Handlers) afterScheduleChange(ctx context.Context, bot BotInterface, chatID int64, err error, success string) {
+	switch {
+	case errors.Is(err, assignment.ErrInvalidAvailability):
+		bot.SendMessage(chatID, h.tr(chatID).T("schedule.invalid_time"))
+		return
+	case errors.Is(err, interfaces.ErrNotFound):
+		bot.SendMessage(chatID, h.tr(chatID).T("schedule.already_removed"))
+	case err != nil:
+		h.log.Error("Failed to update availability", "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("error.server"))
+		return
+	default:
+		bot.SendMessage(chatID, success)
+	}
+
+	h.showSchedule(ctx, bot, chatID)
+}
+
+func (h *Handlers) showSchedule(ctx context.Context, bot BotInterface, chatID int64) {
+	availability, err := h.assignmentService.GetAvailability(ctx, chatID)
+	if err != nil {
+		h.log.Error("Failed to get availability", "chatID", chatID, "error", err)
+		bot.SendMessage(chatID, h.tr(chatID).T("schedule.load_failed"))
+		return
+	}
+
+	keyboard := h.keyboards(chatID).CreateScheduleKeyboard(availability)
+	bot.SendMessageWithInlineKeyboard(chatID, h.formatSchedule(h.tr(chatID), availability), keyboard)
+}
+
+func (h *Handlers) formatSchedule(tr i18n.Localizer, availability *models.CourierAvailability) string {
+	var builder strings.Builder
+
+	builder.WriteString(tr.T("schedule.title"))
+
+	if len(availability.Windows) == 0 {
+		builder.WriteString(tr.T("schedule.no_windows"))
+	}
+	for _, window := range availability.Windows {
+		builder.WriteString(tr.T("schedule.window", tr.Weekday(window.Weekday), window.String()))
+	}
+
+	if len(availability.Exceptions) > 0 {
+		builder.WriteString(tr.T("schedule.exceptions"))
+	}
+	for _, exception := range availability.Exceptions {
+		if exception.Available {
+			builder.WriteString(tr.T("schedule.exception_hours", exception.Day.Format("02.01"), models.FormatMinutes(*exception.StartMinute), models.FormatMinutes(*exception.EndMinute)))
+		} else {
+			builder.WriteString(tr.T("schedule.exception_off", exception.Day.Format("02.01")))
+		}
+	}
+
+	builder.WriteString(tr.T("schedule.footer"))
+
+	return builder.String()
+}
@@ -0,0 +1,142 @@
+// Package callback кодирует callback data inline-кнопок. Формат версии 1:
+//
+//	1<подпись>action|arg|ids|
+//
+// ids — числа в base36 через запятую. Последнее поле всегда пустое: раньше
+// в нём был ключ payload, и старые кнопки с ним по-прежнему разбираются.
+// Адрес, телефон и прочие данные обработчики берут из заказа по ID.
+// Подпись — усечённый HMAC-SHA256 от версии и тела, поэтому подделанные
+// кнопки отклоняются.
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	version = "1"
+
+	// MaxLength — ограничение Telegram на callback_data в байтах.
+	MaxLength = 64
+
+	signatureBytes = 8
+	separator      = "|"
+)
+
+var (
+	// ErrStale — кнопка старого формата или неизвестной версии. Такие кнопки
+	// не выполняются, курьеру предлагается открыть экран заново.
+	ErrStale            = errors.New("callback data has unsupported version")
+	ErrInvalidSignature = errors.New("callback data signature mismatch")
+	ErrMalformed        = errors.New("malformed callback data")
+	ErrTooLong          = errors.New("callback data exceeds telegram limit")
+)
+
+var encoding = base64.RawURLEncoding
+
+type Data struct {
+	Action string
+	Arg    string // короткий строковый аргумент: причина, язык, ответ
+	IDs    []int
+}
+
+func New(action string, ids ...int) Data {
+	return Data{Action: action, IDs: ids}
+}
+
+func (d Data) WithArg(arg string) Data {
+	d.Arg = arg
+	return d
+}
+
+// ID возвращает i-й числовой аргумент.
+func (d Data) ID(i int) (int, bool) {
+	if i < 0 || i >= len(d.IDs) {
+		return 0, false
+	}
+
+	return d.IDs[i], true
+}
+
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret string) *Codec {
+	return &Codec{secret: []byte(secret)}
+}
+
+func (c *Codec) Encode(data Data) (string, error) {
+	if strings.Contains(data.Action, separator) || strings.Contains(data.Arg, separator) {
+		return "", fmt.Errorf("%w: separator in action %q", ErrMalformed, data.Action)
+	}
+
+	ids := make([]string, len(data.IDs))
+	for i, id := range data.IDs {
+		if id < 0 {
+			return "", fmt.Errorf("%w: negative id in action %q", ErrMalformed, data.Action)
+		}
+		ids[i] = strconv.FormatInt(int64(id), 36)
+	}
+
+	body := strings.Join([]string{data.Action, data.Arg, strings.Join(ids, ","), ""}, separator)
+	encoded := version + c.sign(body) + body
+
+	if len(encoded) > MaxLength {
+		return "", fmt.Errorf("%w: action %q takes %d bytes", ErrTooLong, data.Action, len(encoded))
+	}
+
+	return encoded, nil
+}
+
+// Decode проверяет подпись и разбирает callback data.
+func (c *Codec) Decode(raw string) (Data, error) {
+	if !strings.HasPrefix(raw, version) {
+		return Data{}, ErrStale
+	}
+
+	signatureLength := encoding.EncodedLen(signatureBytes)
+	if len(raw) < len(version)+signatureLength {
+		return Data{}, ErrMalformed
+	}
+
+	signature := raw[len(version) : len(version)+signatureLength]
+	body := raw[len(version)+signatureLength:]
+
+	if !hmac.Equal([]byte(signature), []byte(c.sign(body))) {
+		return Data{}, ErrInvalidSignature
+	}
+
+	fields := strings.Split(body, separator)
+	if len(fields) != 4 || fields[0] == "" {
+		return Data{}, ErrMalformed
+	}
+
+	data := Data{Action: fields[0], Arg: fields[1]}
+
+	if fields[2] != "" {
+		for _, value := range strings.Split(fields[2], ",") {
+			id, err := strconv.ParseInt(value, 36, 64)
+			if err != nil {
+				return Data{}, fmt.Errorf("%w: invalid id %q", ErrMalformed, value)
+			}
+			data.IDs = append(data.IDs, int(id))
+		}
+	}
+
+	return data, nil
+}
+
+func (c *Codec) sign(body string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(version))
+	mac.Write([]byte(body))
+
+	return encoding.EncodeToString(mac.Sum(nil)[:signatureBytes])
+}
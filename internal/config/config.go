@@ -3,6 +3,9 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,10 +16,63 @@ type Config struct {
 	DBUser           string
 	DBPassword       string
 	DBName           string
-	WebhookSecret    string
+	WebhookSecrets   map[string]string
 	TelegramBotToken string
 	HTTPAddr         string
 	Env              string
+
+	LeaderLockID            int64
+	LeaderElectionInterval  time.Duration
+	AssignmentCheckInterval time.Duration
+
+	InboxPollInterval time.Duration
+	InboxMaxAttempts  int
+
+	WebhookTimestampTolerance time.Duration
+	WebhookNonceCleanup       time.Duration
+
+	WebhookRequireToken bool
+
+	TracingExporter string
+	TracingFile     string
+
+	HealthCheckTimeout time.Duration
+	SchedulerMaxLag    time.Duration
+	ShutdownDrainDelay time.Duration
+	ShutdownTimeout    time.Duration
+
+	ShiftIdleTimeout   time.Duration
+	ShiftCheckInterval time.Duration
+
+	CourierIdleThreshold time.Duration
+	LastSeenDebounce     time.Duration
+
+	Timezone                     string
+	AvailabilityReminderInterval time.Duration
+	AssignmentHoldLead           time.Duration
+	HeldAssignmentInterval       time.Duration
+	DailySummaryAt               time.Duration
+	DailySummaryInterval         time.Duration
+
+	CallbackSecret string
+
+	CustomerNotifiers     []string
+	CustomerNotifyEvents  []string
+	CustomerTemplatesFile string
+	CustomerNotifyFile    string
+	SMSGatewayURL         string
+	SMSGatewayToken       string
+	SMSSender             string
+	SMTPAddr              string
+	SMTPUser              string
+	SMTPPassword          string
+	EmailFrom             string
+	CustomerBotToken      string
+
+	PublicURL      string
+	ReviewSecret   string
+	ReviewWindow   time.Duration
+	RatingHalfLife time.Duration
 }
 
 func Load() *Config {
@@ -31,10 +87,63 @@ func Load() *Config {
 		DBUser:           getEnv("DB_USER", "postgres"),
 		DBPassword:       getEnv("DB_PASSWORD", "postgres"),
 		DBName:           getEnv("DB_NAME", "courier-bot"),
-		WebhookSecret:    getEnv("WEBHOOK_SECRET", ""),
+		WebhookSecrets:   parseWebhookSecrets(getEnv("WEBHOOK_SECRET", ""), getEnv("WEBHOOK_SECRETS", "")),
 		TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
 		HTTPAddr:         getEnv("HTTP_ADDR", ":8080"),
 		Env:              getEnv("ENV", "local"),
+
+		LeaderLockID:            getEnvInt64("LEADER_LOCK_ID", 7413),
+		LeaderElectionInterval:  getEnvDuration("LEADER_ELECTION_INTERVAL", 5*time.Second),
+		AssignmentCheckInterval: getEnvDuration("ASSIGNMENT_CHECK_INTERVAL", 5*time.Second),
+
+		InboxPollInterval: getEnvDuration("INBOX_POLL_INTERVAL", time.Second),
+		InboxMaxAttempts:  int(getEnvInt64("INBOX_MAX_ATTEMPTS", 5)),
+
+		WebhookTimestampTolerance: getEnvDuration("WEBHOOK_TIMESTAMP_TOLERANCE", 5*time.Minute),
+		WebhookNonceCleanup:       getEnvDuration("WEBHOOK_NONCE_CLEANUP_INTERVAL", 10*time.Minute),
+
+		WebhookRequireToken: getEnvBool("WEBHOOK_REQUIRE_TOKEN", false),
+
+		TracingExporter: getEnv("TRACING_EXPORTER", "none"),
+		TracingFile:     getEnv("TRACING_FILE", "traces.jsonl"),
+
+		HealthCheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 3*time.Second),
+		SchedulerMaxLag:    getEnvDuration("SCHEDULER_MAX_LAG", time.Minute),
+		ShutdownDrainDelay: getEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout:    getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		ShiftIdleTimeout:   getEnvDuration("SHIFT_IDLE_TIMEOUT", 30*time.Minute),
+		ShiftCheckInterval: getEnvDuration("SHIFT_CHECK_INTERVAL", time.Minute),
+
+		CourierIdleThreshold: getEnvDuration("COURIER_IDLE_THRESHOLD", 15*time.Minute),
+		LastSeenDebounce:     getEnvDuration("LAST_SEEN_DEBOUNCE", time.Minute),
+
+		Timezone:                     getEnv("TIMEZONE", "Local"),
+		AvailabilityReminderInterval: getEnvDuration("AVAILABILITY_REMINDER_INTERVAL", time.Minute),
+		AssignmentHoldLead:           getEnvDuration("ASSIGNMENT_HOLD_LEAD", time.Hour),
+		HeldAssignmentInterval:       getEnvDuration("HELD_ASSIGNMENT_INTERVAL", time.Minute),
+		DailySummaryAt:               getEnvDuration("DAILY_SUMMARY_AT", 21*time.Hour),
+		DailySummaryInterval:         getEnvDuration("DAILY_SUMMARY_INTERVAL", time.Minute),
+
+		CallbackSecret: getEnv("CALLBACK_SECRET", ""),
+
+		CustomerNotifiers:     getEnvList("CUSTOMER_NOTIFIERS", ""),
+		CustomerNotifyEvents:  getEnvList("CUSTOMER_NOTIFY_EVENTS", "accepted,picked_up,approaching,delivered"),
+		CustomerTemplatesFile: getEnv("CUSTOMER_TEMPLATES_FILE", ""),
+		CustomerNotifyFile:    getEnv("CUSTOMER_NOTIFY_FILE", "customer_notifications.jsonl"),
+		SMSGatewayURL:         getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken:       getEnv("SMS_GATEWAY_TOKEN", ""),
+		SMSSender:             getEnv("SMS_SENDER", ""),
+		SMTPAddr:              getEnv("SMTP_ADDR", ""),
+		SMTPUser:              getEnv("SMTP_USER", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		EmailFrom:             getEnv("EMAIL_FROM", ""),
+		CustomerBotToken:      getEnv("CUSTOMER_BOT_TOKEN", ""),
+
+		PublicURL:      getEnv("PUBLIC_URL", ""),
+		ReviewSecret:   getEnv("REVIEW_SECRET", ""),
+		ReviewWindow:   getEnvDuration("REVIEW_WINDOW", 7*24*time.Hour),
+		RatingHalfLife: getEnvDuration("RATING_HALF_LIFE", 30*24*time.Hour),
 	}
 }
 
@@ -45,3 +154,89 @@ func getEnv(key, defaultValue string) string {
 
 	return defaultValue
 }
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		slog.Warn("Invalid integer in env, using default", "key", key, "value", value)
+		return defaultValue
+	}
+
+	return parsed
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("Invalid duration in env, using default", "key", key, "value", value)
+		return defaultValue
+	}
+
+	return parsed
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("Invalid boolean in env, using default", "key", key, "value", value)
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// getEnvList читает список через запятую. Пустая строка — пустой список,
+// так можно выключить, например, все уведомления клиенту.
+func getEnvList(key, defaultValue string) []string {
+	var result []string
+
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// parseWebhookSecrets собирает активные ключи подписи: WEBHOOK_SECRET
+// становится ключом "default", WEBHOOK_SECRETS задаётся как "kid1:secret1,kid2:secret2".
+func parseWebhookSecrets(legacySecret, secrets string) map[string]string {
+	result := make(map[string]string)
+
+	if legacySecret != "" {
+		result["default"] = legacySecret
+	}
+
+	for _, pair := range strings.Split(secrets, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		keyID, secret, ok := strings.Cut(pair, ":")
+		if !ok || keyID == "" || secret == "" {
+			slog.Warn("Invalid entry in WEBHOOK_SECRETS, skipping", "keyID", keyID)
+			continue
+		}
+
+		result[keyID] = secret
+	}
+
+	return result
+}
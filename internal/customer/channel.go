@@ -0,0 +1,50 @@
+// Package customer доставляет сообщения клиенту заказа: то, что курьер
+// пишет через бота (Channel), и уведомления о ходе доставки (SMS, письмо,
+// Telegram). Телефон клиента остаётся на стороне канала, курьер его не видит.
+// Для переписки с курьером настоящего канала пока нет, есть только
+// локальная заглушка LogChannel.
+package customer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+var ErrNoContact = errors.New("customer has no contact")
+
+type Channel interface {
+	Send(ctx context.Context, order *models.Order, text string) error
+}
+
+// LogChannel ничего не отправляет, а пишет сообщение в лог. Подходит для
+// локального запуска и как запасной вариант, пока канал не подключён.
+type LogChannel struct {
+	log *slog.Logger
+}
+
+func NewLogChannel(log *slog.Logger) *LogChannel {
+	return &LogChannel{log: log}
+}
+
+func (c *LogChannel) Send(ctx context.Context, order *models.Order, text string) error {
+	if order.PhoneNumber == "" {
+		return ErrNoContact
+	}
+
+	c.log.Info("Customer message (log channel)", "orderID", order.ID, "phone", maskPhone(order.PhoneNumber), "text", text)
+	return nil
+}
+
+// maskPhone оставляет от номера две последние цифры, чтобы он не попадал в
+// логи целиком.
+func maskPhone(phone string) string {
+	if len(phone) <= 2 {
+		return strings.Repeat("*", len(phone))
+	}
+
+	return strings.Repeat("*", len(phone)-2) + phone[len(phone)-2:]
+}
@@ -0,0 +1,290 @@
+package customer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	ChannelSMS      = "sms"
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+	ChannelFile     = "file"
+)
+
+// Recipient — адреса клиента заказа. Канал берёт нужный ему адрес, а если
+// его нет, возвращает ErrNoContact.
+type Recipient struct {
+	OrderID        int
+	Name           string
+	Phone          string
+	Email          string
+	TelegramChatID int64
+}
+
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// SMSNotifier отправляет SMS через HTTP-шлюз: POST с JSON {to, from, text}
+// и Bearer-токеном. Любой ответ, кроме 2xx, считается ошибкой.
+type SMSNotifier struct {
+	url    string
+	token  string
+	sender string
+	client *http.Client
+}
+
+func NewSMSNotifier(url, token, sender string) *SMSNotifier {
+	return &SMSNotifier{
+		url:    url,
+		token:  token,
+		sender: sender,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *SMSNotifier) Channel() string {
+	return ChannelSMS
+}
+
+func (n *SMSNotifier) Notify(ctx context.Context, recipient Recipient, message Message) error {
+	if recipient.Phone == "" {
+		return ErrNoContact
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"to":   recipient.Phone,
+		"from": n.sender,
+		"text": message.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode sms request: %v", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build sms request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call sms gateway: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("sms gateway returned %s", response.Status)
+	}
+
+	return nil
+}
+
+// emailTimeout ограничивает всю отправку письма: зависший SMTP-сервер не
+// должен держать уведомление дольше, чем длится дренаж при остановке.
+const emailTimeout = 30 * time.Second
+
+// EmailNotifier отправляет письмо через SMTP. Без имени пользователя
+// сервер используется без авторизации.
+type EmailNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewEmailNotifier(addr, username, password, from string) *EmailNotifier {
+	notifier := &EmailNotifier{addr: addr, from: from}
+
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		notifier.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return notifier
+}
+
+func (n *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, recipient Recipient, message Message) error {
+	if recipient.Email == "" {
+		return ErrNoContact
+	}
+
+	// Адрес приходит от магазина и попадает в заголовки письма, поэтому
+	// сначала проверяем, что это действительно один адрес.
+	to, err := mail.ParseAddress(recipient.Email)
+	if err != nil {
+		return fmt.Errorf("invalid customer email: %v", err)
+	}
+
+	var body strings.Builder
+	body.WriteString("From: " + n.from + "\r\n")
+	body.WriteString("To: " + to.String() + "\r\n")
+	body.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	body.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+
+	if err := n.send(ctx, to.Address, []byte(body.String())); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	return nil
+}
+
+// send повторяет smtp.SendMail, но соединение обрывается, как только
+// отменён ctx или истёк emailTimeout.
+func (n *EmailNotifier) send(ctx context.Context, to string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(n.addr)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if n.auth != nil {
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// TelegramNotifier пишет клиенту от имени отдельного клиентского бота.
+// Бот курьеров для этого не подходит: его сообщения он принимает за
+// сообщения курьеров.
+type TelegramNotifier struct {
+	api *tgbotapi.BotAPI
+}
+
+func NewTelegramNotifier(api *tgbotapi.BotAPI) *TelegramNotifier {
+	return &TelegramNotifier{api: api}
+}
+
+func (n *TelegramNotifier) Channel() string {
+	return ChannelTelegram
+}
+
+func (n *TelegramNotifier) Notify(ctx context.Context, recipient Recipient, message Message) error {
+	if recipient.TelegramChatID == 0 {
+		return ErrNoContact
+	}
+
+	if _, err := n.api.Send(tgbotapi.NewMessage(recipient.TelegramChatID, message.Text)); err != nil {
+		return fmt.Errorf("failed to send telegram message: %v", err)
+	}
+
+	return nil
+}
+
+// FileNotifier дописывает уведомления в файл JSON Lines вместо отправки.
+// Он нужен для локального запуска и проверки шаблонов без внешних сервисов
+// и включается явно: CUSTOMER_NOTIFIERS=file.
+type FileNotifier struct {
+	path string
+	log  *slog.Logger
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string, log *slog.Logger) *FileNotifier {
+	return &FileNotifier{path: path, log: log}
+}
+
+func (n *FileNotifier) Channel() string {
+	return ChannelFile
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, recipient Recipient, message Message) error {
+	line, err := json.Marshal(map[string]any{
+		"time":             time.Now().Format(time.RFC3339),
+		"order_id":         recipient.OrderID,
+		"phone":            maskPhone(recipient.Phone),
+		"email":            recipient.Email,
+		"telegram_chat_id": recipient.TelegramChatID,
+		"subject":          message.Subject,
+		"text":             message.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %v", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write notification: %v", err)
+	}
+
+	n.log.Info("Customer notification written to file", "orderID", recipient.OrderID, "path", n.path)
+	return nil
+}
@@ -0,0 +1,60 @@
+package customer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// ReviewLinks подписывает ссылки на оценку заказа. Токен — номер заказа и
+// HMAC от него, так что ссылку нельзя подобрать для чужого заказа, а
+// хранить выданные токены не нужно.
+type ReviewLinks struct {
+	baseURL string
+	secret  []byte
+}
+
+func NewReviewLinks(baseURL, secret string) *ReviewLinks {
+	return &ReviewLinks{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}
+}
+
+func (l *ReviewLinks) URL(orderID int) string {
+	return l.baseURL + "/review/" + l.Token(orderID)
+}
+
+func (l *ReviewLinks) Token(orderID int) string {
+	id := strconv.Itoa(orderID)
+	return id + "." + l.sign(id)
+}
+
+// Verify возвращает номер заказа из токена, если подпись верна.
+func (l *ReviewLinks) Verify(token string) (int, bool) {
+	id, signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, false
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(l.sign(id))) {
+		return 0, false
+	}
+
+	orderID, err := strconv.Atoi(id)
+	if err != nil || orderID <= 0 {
+		return 0, false
+	}
+
+	return orderID, true
+}
+
+func (l *ReviewLinks) sign(id string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte("review."))
+	mac.Write([]byte(id))
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
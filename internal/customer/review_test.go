@@ -0,0 +1,44 @@
+package customer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReviewLinksVerify(t *testing.T) {
+	links := NewReviewLinks("https://example.com/", "secret")
+
+	token := links.Token(42)
+	if got := links.URL(42); got != "https://example.com/review/"+token {
+		t.Fatalf("URL(42) = %q", got)
+	}
+
+	orderID, ok := links.Verify(token)
+	if !ok || orderID != 42 {
+		t.Fatalf("Verify(%q) = %d, %v, want 42, true", token, orderID, ok)
+	}
+
+	_, signature, _ := strings.Cut(token, ".")
+	tampered := []byte(signature)
+	tampered[0] ^= 1
+
+	invalid := map[string]string{
+		"empty":                 "",
+		"no signature":          "42",
+		"tampered signature":    "42." + string(tampered),
+		"signature of another":  "43." + signature,
+		"other secret":          NewReviewLinks("https://example.com", "other").Token(42),
+		"non-numeric id":        "abc." + links.sign("abc"),
+		"zero id":               "0." + links.sign("0"),
+		"negative id":           "-1." + links.sign("-1"),
+		"id with trailing text": "42x." + links.sign("42x"),
+	}
+
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if orderID, ok := links.Verify(token); ok {
+				t.Fatalf("Verify(%q) = %d, true, want false", token, orderID)
+			}
+		})
+	}
+}
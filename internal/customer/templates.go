@@ -0,0 +1,138 @@
+package customer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+// Template — тема и текст уведомления в синтаксисе text/template. Тема
+// нужна только письмам.
+type Template struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
+
+// TemplateData — поля, доступные в шаблонах.
+type TemplateData struct {
+	OrderID      int
+	CustomerName string
+	CourierName  string
+	Address      string
+	// ReviewURL — ссылка на оценку доставки, пустая, если отзывы выключены.
+	ReviewURL string
+}
+
+var defaultTemplates = map[models.CustomerEvent]Template{
+	models.CustomerEventAccepted: {
+		Subject: "Заказ #{{.OrderID}} передан курьеру",
+		Text:    "Здравствуйте{{with .CustomerName}}, {{.}}{{end}}! Курьер{{with .CourierName}} {{.}}{{end}} принял ваш заказ #{{.OrderID}} и скоро заберёт его.",
+	},
+	models.CustomerEventPickedUp: {
+		Subject: "Заказ #{{.OrderID}} в пути",
+		Text:    "Курьер забрал заказ #{{.OrderID}} и везёт его по адресу {{.Address}}.",
+	},
+	models.CustomerEventApproaching: {
+		Subject: "Курьер подъезжает",
+		Text:    "Курьер подъезжает с заказом #{{.OrderID}}. Пожалуйста, будьте на связи.",
+	},
+	models.CustomerEventDelivered: {
+		Subject: "Заказ #{{.OrderID}} доставлен",
+		Text:    "Заказ #{{.OrderID}} доставлен. Спасибо, что выбрали нас!{{with .ReviewURL}} Оцените доставку: {{.}}{{end}}",
+	},
+}
+
+// Сообщения об ошибках, которые клиент видит на странице оценки доставки.
+var (
+	ReviewFormUnreadable = "Не удалось прочитать форму, попробуйте ещё раз."
+	ReviewRatingRequired = fmt.Sprintf("Выберите оценку от %d до %d.", models.MinReviewRating, models.MaxReviewRating)
+	ReviewInvalid        = fmt.Sprintf("Выберите оценку от %d до %d, комментарий — не длиннее %d символов.", models.MinReviewRating, models.MaxReviewRating, models.MaxReviewComment)
+)
+
+type compiledTemplate struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+type Templates struct {
+	templates map[models.CustomerEvent]compiledTemplate
+}
+
+// LoadTemplates собирает шаблоны уведомлений. Файл path — JSON вида
+// {"accepted": {"subject": "...", "text": "..."}}; события, которых в нём
+// нет, берут шаблон по умолчанию. Пустой path — только шаблоны по умолчанию.
+func LoadTemplates(path string) (*Templates, error) {
+	sources := make(map[models.CustomerEvent]Template, len(defaultTemplates))
+	for event, source := range defaultTemplates {
+		sources[event] = source
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read customer templates: %v", err)
+		}
+
+		var overrides map[models.CustomerEvent]Template
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, fmt.Errorf("failed to parse customer templates: %v", err)
+		}
+
+		for event, source := range overrides {
+			if !event.IsValid() {
+				return nil, fmt.Errorf("unknown customer event %q in templates", event)
+			}
+			if strings.TrimSpace(source.Text) == "" {
+				return nil, fmt.Errorf("empty text for customer event %q", event)
+			}
+			sources[event] = source
+		}
+	}
+
+	templates := &Templates{templates: make(map[models.CustomerEvent]compiledTemplate, len(sources))}
+	for event, source := range sources {
+		subject, err := template.New(string(event) + ".subject").Option("missingkey=error").Parse(source.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse subject for %q: %v", event, err)
+		}
+
+		text, err := template.New(string(event) + ".text").Option("missingkey=error").Parse(source.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse text for %q: %v", event, err)
+		}
+
+		templates.templates[event] = compiledTemplate{subject: subject, text: text}
+	}
+
+	// Неизвестное поле в шаблоне text/template находит только при
+	// выполнении, поэтому пробуем отрисовать каждый шаблон сразу.
+	sample := TemplateData{OrderID: 1, CustomerName: "Иван", CourierName: "Пётр", Address: "Москва, Тверская, 1", ReviewURL: "https://example.com/review/1.0"}
+	for event := range templates.templates {
+		if _, err := templates.Render(event, sample); err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
+
+func (t *Templates) Render(event models.CustomerEvent, data TemplateData) (Message, error) {
+	compiled, ok := t.templates[event]
+	if !ok {
+		return Message{}, fmt.Errorf("no template for customer event %q", event)
+	}
+
+	var subject, text strings.Builder
+	if err := compiled.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject for %q: %v", event, err)
+	}
+	if err := compiled.text.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render text for %q: %v", event, err)
+	}
+
+	return Message{Subject: subject.String(), Text: text.String()}, nil
+}
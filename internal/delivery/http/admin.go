@@ -0,0 +1,545 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/auth"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/service/admin"
+	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+)
+
+type AdminHandler struct {
+	adminService      *admin.Service
+	assignmentService *assignment.Service
+	log               *slog.Logger
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+type CustomerMessageRequest struct {
+	Text string `json:"text"`
+}
+
+type AssignOrderRequest struct {
+	CourierID int `json:"courier_id"`
+}
+
+type UnassignOrderRequest struct {
+	Reassign bool `json:"reassign"`
+}
+
+type ListResponse[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+func NewAdminHandler(adminService *admin.Service, assignmentService *assignment.Service, log *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminService:      adminService,
+		assignmentService: assignmentService,
+		log:               log,
+	}
+}
+
+func (h *AdminHandler) HandleListCouriers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.CourierFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+	}
+
+	if value := query.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid active filter", h.log)
+			return
+		}
+		filter.IsActive = &active
+	}
+
+	page, err := parsePage(query.Get("limit"), query.Get("offset"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), h.log)
+		return
+	}
+	filter.Page = page
+
+	couriers, total, err := h.adminService.ListCouriers(r.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to list couriers", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to list couriers", h.log)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newListResponse(couriers, total, filter.Page), h.log)
+}
+
+func (h *AdminHandler) HandleGetCourier(w http.ResponseWriter, r *http.Request) {
+	courierID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	courier, err := h.adminService.GetCourier(r.Context(), courierID)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to get courier")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, courier, h.log)
+}
+
+func (h *AdminHandler) HandleGetCourierAvailability(w http.ResponseWriter, r *http.Request) {
+	courierID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	availability, err := h.adminService.GetCourierAvailability(r.Context(), courierID)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to get courier availability")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, availability, h.log)
+}
+
+func (h *AdminHandler) HandleListCourierReviews(w http.ResponseWriter, r *http.Request) {
+	courierID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := models.ReviewFilter{CourierID: courierID}
+
+	if value := query.Get("include_hidden"); value != "" {
+		includeHidden, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid include_hidden filter", h.log)
+			return
+		}
+		filter.IncludeHidden = includeHidden
+	}
+
+	var err error
+	if filter.Page, err = parsePage(query.Get("limit"), query.Get("offset")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), h.log)
+		return
+	}
+
+	reviews, total, err := h.adminService.ListCourierReviews(r.Context(), filter)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to list courier reviews")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newListResponse(reviews, total, filter.Page), h.log)
+}
+
+// HandleListAvailableCouriers отвечает, кто по расписанию работает в момент
+// at или во время доставки заказа order_id.
+func (h *AdminHandler) HandleListAvailableCouriers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	at, err := parseOptionalTime(query.Get("at"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid at, RFC 3339 expected", h.log)
+		return
+	}
+
+	orderID, err := parseOptionalInt(query.Get("order_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order_id", h.log)
+		return
+	}
+
+	if at == nil && orderID != 0 {
+		order, err := h.assignmentService.GetOrderByID(r.Context(), orderID)
+		if err != nil {
+			h.writeServiceError(w, err, "Failed to get order")
+			return
+		}
+
+		if order.DeliveryDate == nil {
+			writeError(w, http.StatusConflict, "Order has no delivery date", h.log)
+			return
+		}
+		at = order.DeliveryDate
+	}
+
+	if at == nil {
+		writeError(w, http.StatusBadRequest, "Either at or order_id is required", h.log)
+		return
+	}
+
+	couriers, err := h.adminService.ListAvailableCouriers(r.Context(), *at)
+	if err != nil {
+		h.log.Error("Failed to list available couriers", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to list available couriers", h.log)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newListResponse(couriers, len(couriers), models.Page{}), h.log)
+}
+
+func (h *AdminHandler) HandleActivateCourier(w http.ResponseWriter, r *http.Request) {
+	h.setCourierActive(w, r, true)
+}
+
+func (h *AdminHandler) HandleDeactivateCourier(w http.ResponseWriter, r *http.Request) {
+	h.setCourierActive(w, r, false)
+}
+
+func (h *AdminHandler) setCourierActive(w http.ResponseWriter, r *http.Request, active bool) {
+	courierID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	courier, err := h.adminService.SetCourierActive(r.Context(), courierID, active)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to update courier")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, courier, h.log)
+}
+
+func (h *AdminHandler) HandleDeleteCourier(w http.ResponseWriter, r *http.Request) {
+	courierID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeleteCourier(r.Context(), courierID); err != nil {
+		h.writeServiceError(w, err, "Failed to delete courier")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) HandleGetOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	details, err := h.adminService.GetOrderDetails(r.Context(), orderID)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to get order")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, details, h.log)
+}
+
+func (h *AdminHandler) HandleAssignOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var request AssignOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.CourierID <= 0 {
+		writeError(w, http.StatusBadRequest, "courier_id is required", h.log)
+		return
+	}
+
+	if err := h.assignmentService.AssignManually(r.Context(), orderID, request.CourierID); err != nil {
+		h.writeServiceError(w, err, "Failed to assign order")
+		return
+	}
+
+	h.HandleGetOrder(w, r)
+}
+
+func (h *AdminHandler) HandleUnassignOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var request UnassignOrderRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON payload", h.log)
+			return
+		}
+	}
+
+	if err := h.assignmentService.Unassign(r.Context(), orderID, request.Reassign); err != nil {
+		h.writeServiceError(w, err, "Failed to unassign order")
+		return
+	}
+
+	h.HandleGetOrder(w, r)
+}
+
+func (h *AdminHandler) HandleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var request CancelOrderRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON payload", h.log)
+			return
+		}
+	}
+
+	err := h.assignmentService.CancelOrder(r.Context(), orderID, models.CancelSourceDispatcher, request.Reason)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to cancel order")
+		return
+	}
+
+	tokenName := ""
+	if token, ok := auth.TokenFromContext(r.Context()); ok {
+		tokenName = token.Name
+	}
+
+	h.log.Info("Order cancelled by dispatcher", "orderID", orderID, "reason", request.Reason, "token", tokenName)
+	h.HandleGetOrder(w, r)
+}
+
+func (h *AdminHandler) HandleListOrderMessages(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.assignmentService.ListContactMessages(r.Context(), orderID)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to list order messages")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, messages, h.log)
+}
+
+// HandleRelayCustomerMessage принимает ответ клиента от канала клиента и
+// передаёт его курьеру заказа.
+func (h *AdminHandler) HandleRelayCustomerMessage(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var request CustomerMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text is required", h.log)
+		return
+	}
+
+	message, err := h.assignmentService.RelayCustomerMessage(r.Context(), orderID, strings.TrimSpace(request.Text))
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to relay customer message")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, message, h.log)
+}
+
+func (h *AdminHandler) HandleListOrderNotifications(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	notifications, err := h.assignmentService.ListCustomerNotifications(r.Context(), orderID)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to list order notifications")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, notifications, h.log)
+}
+
+func (h *AdminHandler) HandleHideReview(w http.ResponseWriter, r *http.Request) {
+	h.setReviewHidden(w, r, true)
+}
+
+func (h *AdminHandler) HandleUnhideReview(w http.ResponseWriter, r *http.Request) {
+	h.setReviewHidden(w, r, false)
+}
+
+func (h *AdminHandler) setReviewHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	reviewID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.assignmentService.SetReviewHidden(r.Context(), reviewID, hidden)
+	if err != nil {
+		h.writeServiceError(w, err, "Failed to update review")
+		return
+	}
+
+	tokenName := ""
+	if token, ok := auth.TokenFromContext(r.Context()); ok {
+		tokenName = token.Name
+	}
+
+	h.log.Info("Review moderated by dispatcher", "reviewID", reviewID, "hidden", hidden, "token", tokenName)
+	writeJSON(w, http.StatusOK, review, h.log)
+}
+
+func (h *AdminHandler) HandleListAssignments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var filter models.AssignmentFilter
+
+	if value := query.Get("status"); value != "" {
+		status := models.CourierResponseStatus(value)
+		if !status.IsValid() {
+			writeError(w, http.StatusBadRequest, "Invalid status filter", h.log)
+			return
+		}
+		filter.Status = status
+	}
+
+	var err error
+	if filter.CourierID, err = parseOptionalInt(query.Get("courier_id")); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid courier_id filter", h.log)
+		return
+	}
+
+	if filter.OrderID, err = parseOptionalInt(query.Get("order_id")); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order_id filter", h.log)
+		return
+	}
+
+	if filter.From, err = parseOptionalTime(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid from filter, RFC 3339 expected", h.log)
+		return
+	}
+
+	if filter.To, err = parseOptionalTime(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid to filter, RFC 3339 expected", h.log)
+		return
+	}
+
+	if filter.Page, err = parsePage(query.Get("limit"), query.Get("offset")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), h.log)
+		return
+	}
+
+	assignments, total, err := h.adminService.ListAssignments(r.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to list assignments", "error", err)
+		writeError(w, http.StatusInternalServerError, "Failed to list assignments", h.log)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newListResponse(assignments, total, filter.Page), h.log)
+}
+
+func (h *AdminHandler) HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func (h *AdminHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "Invalid ID", h.log)
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *AdminHandler) writeServiceError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, interfaces.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error(), h.log)
+	case errors.Is(err, assignment.ErrOrderAlreadyCancelled),
+		errors.Is(err, assignment.ErrOrderAlreadyDelivered),
+		errors.Is(err, assignment.ErrOrderAlreadyAssigned),
+		errors.Is(err, assignment.ErrOrderNotAssigned),
+		errors.Is(err, admin.ErrCourierHasHistory),
+		errors.Is(err, admin.ErrCourierHasOrders):
+		writeError(w, http.StatusConflict, err.Error(), h.log)
+	default:
+		h.log.Error(fallback, "error", err)
+		writeError(w, http.StatusInternalServerError, fallback, h.log)
+	}
+}
+
+func newListResponse[T any](items []T, total int, page models.Page) ListResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return ListResponse[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
+
+func parsePage(limitValue, offsetValue string) (models.Page, error) {
+	limit, err := parseOptionalInt(limitValue)
+	if err != nil || limit < 0 {
+		return models.Page{}, errors.New("invalid limit")
+	}
+
+	offset, err := parseOptionalInt(offsetValue)
+	if err != nil || offset < 0 {
+		return models.Page{}, errors.New("invalid offset")
+	}
+
+	return models.Page{Limit: limit, Offset: offset}, nil
+}
+
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string, log *slog.Logger) {
+	writeJSON(w, statusCode, WebHookResponse{Success: false, Error: message}, log)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any, log *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error("Failed to encode response", "Error", err)
+	}
+}
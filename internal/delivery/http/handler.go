@@ -3,7 +3,6 @@ package delivery
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -13,34 +12,50 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/service/inbox"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type WebhookHandler struct {
-	assignmentService *assignment.Service
-	webhookSecret     string
-	log               *slog.Logger
+	inboxService *inbox.Service
+	verifier     *SignatureVerifier
+	log          *slog.Logger
 }
 
 type WebhookPayload struct {
-	OrderID int `json:"order_id" validate:"required,min=1"`
+	Event    string                  `json:"event"`
+	OrderID  int                     `json:"order_id" validate:"required,min=1"`
+	Reason   string                  `json:"reason,omitempty"`
+	Changes  []string                `json:"changes,omitempty"`
+	Customer *models.CustomerContact `json:"customer,omitempty"`
 }
 
+// WebHookResponse на повтор события несёт состояние исходного события в
+// inbox: статус обработки, время обработки и последнюю ошибку.
 type WebHookResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+	Success     bool               `json:"success"`
+	Message     string             `json:"message"`
+	Error       string             `json:"error,omitempty"`
+	EventID     int                `json:"event_id,omitempty"`
+	Duplicate   bool               `json:"duplicate,omitempty"`
+	Status      models.InboxStatus `json:"status,omitempty"`
+	ProcessedAt *time.Time         `json:"processed_at,omitempty"`
+	LastError   string             `json:"last_error,omitempty"`
 }
 
-func NewWebhookHandler(assignmentService *assignment.Service, webhookSecret string, log *slog.Logger) *WebhookHandler {
+func NewWebhookHandler(inboxService *inbox.Service, verifier *SignatureVerifier, log *slog.Logger) *WebhookHandler {
 	return &WebhookHandler{
-		assignmentService: assignmentService,
-		webhookSecret:     webhookSecret,
-		log:               log,
+		inboxService: inboxService,
+		verifier:     verifier,
+		log:          log,
 	}
 }
 
-func (h *WebhookHandler) HandleNewOrderWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+func (h *WebhookHandler) HandleOrderWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		h.log.Warn("Invalid HTTP mehtod for webhook", "Method", r.Method)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -57,87 +72,86 @@ func (h *WebhookHandler) HandleNewOrderWebhook(ctx context.Context, w http.Respo
 
 	r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 
-	if h.webhookSecret != "" {
-		validSignature := h.verifySignature(bodyBytes, r)
-
-		if !validSignature {
-			h.log.Warn("Invalid webhook signature for order")
+	if h.verifier.Enabled() {
+		if err := h.verifier.Verify(ctx, r, bodyBytes); err != nil {
+			h.log.Warn("Webhook signature rejected", "keyID", r.Header.Get(headerKeyID), "reason", err)
 			h.sendErrorResponse(w, "Invalid signature", http.StatusUnauthorized)
+			metrics.WebhookRequests.WithLabelValues("unknown", "rejected").Inc()
 			return
 		}
+	} else {
+		h.log.Debug("Webhook secrets not set, signature verifacation disabled")
 	}
 
 	var webhook WebhookPayload
 	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
 		h.log.Error("Failed to decode webhook payload", "Error", err)
 		h.sendErrorResponse(w, "Invalid JSON payload", http.StatusBadRequest)
+		metrics.WebhookRequests.WithLabelValues("unknown", "invalid").Inc()
 		return
 	}
 
 	if webhook.OrderID <= 0 {
 		h.log.Warn("Invalid order ID", "orderID", webhook.OrderID)
 		h.sendErrorResponse(w, "Invalid order ID", http.StatusBadRequest)
+		metrics.WebhookRequests.WithLabelValues("unknown", "invalid").Inc()
 		return
 	}
 
-	h.log.Info("Received new order webhook", "orderID", webhook.OrderID)
-
-	h.sendSuccessResponse(w, "Order processing started")
-
-	go h.processOrderAssignment(ctx, webhook.OrderID)
-}
-
-func (h *WebhookHandler) processOrderAssignment(ctx context.Context, orderID int) {
-	startTime := time.Now()
-	h.log.Info("Starting async order assignment processing", "orderID", orderID)
+	if webhook.Event == "" {
+		webhook.Event = models.EventOrderCreated
+	}
 
-	if err := h.assignmentService.ProcessNewOrder(ctx, orderID); err != nil {
-		h.log.Error("Failed to process order", "orderID", orderID, "Error", err)
+	if !models.IsKnownOrderEvent(webhook.Event) {
+		h.log.Warn("Unknown order event type", "event", webhook.Event, "orderID", webhook.OrderID)
+		h.sendErrorResponse(w, "Unknown event type", http.StatusBadRequest)
+		metrics.WebhookRequests.WithLabelValues("unknown", "invalid").Inc()
 		return
 	}
 
-	processingTime := time.Since(startTime)
-	h.log.Info("Order assignment processing completed", "orderID", orderID, "time", processingTime)
-}
+	h.log.Info("Received order webhook", "event", webhook.Event, "orderID", webhook.OrderID)
+	trace.SpanFromContext(ctx).SetAttributes(tracing.OrderID(webhook.OrderID), attribute.String("webhook.event", webhook.Event))
 
-func (h *WebhookHandler) verifySignature(bodyBytes []byte, r *http.Request) bool {
-	if h.webhookSecret == "" {
-		h.log.Debug("Webhook secret not set, signature verifacation disabled")
-		return true
-	}
+	key := h.idempotencyKey(r, webhook.Event, webhook.OrderID, bodyBytes)
 
-	signatureHeader := r.Header.Get("X-Signature")
-	if signatureHeader == "" {
-		h.log.Warn("Missing X-Signature header in webhook request")
-		return false
-	}
-
-	expectedSignature, err := h.computeHMACSHA256(bodyBytes, h.webhookSecret)
+	event, duplicate, err := h.inboxService.Record(ctx, key, webhook.Event, webhook.OrderID, bodyBytes)
 	if err != nil {
-		h.log.Error("Failed to compute HMAC signature", "Error", err)
-		return false
+		h.log.Error("Failed to record webhook in inbox", "orderID", webhook.OrderID, "Error", err)
+		h.sendErrorResponse(w, "Failed to accept webhook", http.StatusInternalServerError)
+		metrics.WebhookRequests.WithLabelValues(webhook.Event, "error").Inc()
+		return
 	}
 
-	h.log.Debug("Signature verification", "Expected", expectedSignature, "Received", signatureHeader, "Body", string(bodyBytes))
-
-	isValid := hmac.Equal([]byte(signatureHeader), []byte(expectedSignature))
-
-	if !isValid {
-		h.log.Warn("Invalid webhook signature", "Expected", expectedSignature, "Received", signatureHeader)
+	result, message := "accepted", "Order event accepted"
+	if duplicate {
+		result, message = "duplicate", "Order event already received"
 	}
+	metrics.WebhookRequests.WithLabelValues(webhook.Event, result).Inc()
 
-	return isValid
+	h.sendSuccessResponse(w, WebHookResponse{
+		Success:     true,
+		Message:     message,
+		EventID:     event.ID,
+		Duplicate:   duplicate,
+		Status:      event.Status,
+		ProcessedAt: event.ProcessedAt,
+		LastError:   event.LastError.String,
+	})
 }
 
-func (h *WebhookHandler) computeHMACSHA256(data []byte, secret string) (string, error) {
-	mac := hmac.New(sha256.New, []byte(secret))
+func (h *WebhookHandler) idempotencyKey(r *http.Request, eventType string, orderID int, body []byte) string {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		return key
+	}
 
-	if _, err := mac.Write(data); err != nil {
-		return "", fmt.Errorf("failed to write data to HMAC: %v", err)
+	// Изменений одного заказа может быть много, поэтому для них ключ
+	// включает хеш тела, а не только тип события и ID заказа.
+	if eventType == models.EventOrderUpdated {
+		sum := sha256.Sum256(body)
+		return fmt.Sprintf("%s:%d:%s", eventType, orderID, hex.EncodeToString(sum[:8]))
 	}
 
-	signature := mac.Sum(nil)
-	return hex.EncodeToString(signature), nil
+	return fmt.Sprintf("%s:%d", eventType, orderID)
 }
 
 func (h *WebhookHandler) sendErrorResponse(w http.ResponseWriter, errorMsg string, statusCode int) {
@@ -154,15 +168,10 @@ func (h *WebhookHandler) sendErrorResponse(w http.ResponseWriter, errorMsg strin
 	}
 }
 
-func (h *WebhookHandler) sendSuccessResponse(w http.ResponseWriter, message string) {
+func (h *WebhookHandler) sendSuccessResponse(w http.ResponseWriter, response WebHookResponse) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	response := WebHookResponse{
-		Success: true,
-		Message: message,
-	}
-
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log.Error("Failed to encode success response", "Error", err)
 	}
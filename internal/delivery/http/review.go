@@ -0,0 +1,130 @@
+package delivery
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+)
+
+//go:embed review.html
+var reviewPageSource string
+
+var reviewPage = template.Must(template.New("review").Parse(reviewPageSource))
+
+const maxReviewFormSize = 16 << 10
+
+// ReviewHandler — страница оценки доставки, куда ведёт ссылка из
+// уведомления клиенту. Доступ по подписанному токену, без авторизации.
+type ReviewHandler struct {
+	assignmentService *assignment.Service
+	links             *customer.ReviewLinks
+	log               *slog.Logger
+}
+
+type reviewPageData struct {
+	State      string
+	OrderID    int
+	Error      string
+	Comment    string
+	Ratings    []int
+	MaxComment int
+}
+
+func NewReviewHandler(assignmentService *assignment.Service, links *customer.ReviewLinks, log *slog.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		assignmentService: assignmentService,
+		links:             links,
+		log:               log,
+	}
+}
+
+func (h *ReviewHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /review/{token}", h.HandleReviewForm)
+	mux.HandleFunc("POST /review/{token}", h.HandleSubmitReview)
+}
+
+func (h *ReviewHandler) HandleReviewForm(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.links.Verify(r.PathValue("token"))
+	if !ok {
+		h.render(w, http.StatusNotFound, reviewPageData{State: "invalid"})
+		return
+	}
+
+	if _, err := h.assignmentService.ReviewableOrder(r.Context(), orderID); err != nil {
+		h.renderError(w, orderID, err)
+		return
+	}
+
+	h.render(w, http.StatusOK, reviewPageData{State: "form", OrderID: orderID})
+}
+
+func (h *ReviewHandler) HandleSubmitReview(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := h.links.Verify(r.PathValue("token"))
+	if !ok {
+		h.render(w, http.StatusNotFound, reviewPageData{State: "invalid"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxReviewFormSize)
+	if err := r.ParseForm(); err != nil {
+		h.render(w, http.StatusBadRequest, reviewPageData{State: "form", OrderID: orderID, Error: customer.ReviewFormUnreadable})
+		return
+	}
+
+	comment := r.PostFormValue("comment")
+	rating, err := strconv.Atoi(r.PostFormValue("rating"))
+	if err != nil {
+		h.render(w, http.StatusBadRequest, reviewPageData{State: "form", OrderID: orderID, Comment: comment, Error: customer.ReviewRatingRequired})
+		return
+	}
+
+	if _, err := h.assignmentService.SubmitReview(r.Context(), orderID, rating, comment); err != nil {
+		if errors.Is(err, assignment.ErrInvalidReview) {
+			h.render(w, http.StatusBadRequest, reviewPageData{State: "form", OrderID: orderID, Comment: comment, Error: customer.ReviewInvalid})
+			return
+		}
+
+		h.renderError(w, orderID, err)
+		return
+	}
+
+	h.render(w, http.StatusOK, reviewPageData{State: "thanks", OrderID: orderID})
+}
+
+func (h *ReviewHandler) renderError(w http.ResponseWriter, orderID int, err error) {
+	switch {
+	case errors.Is(err, assignment.ErrAlreadyReviewed):
+		h.render(w, http.StatusConflict, reviewPageData{State: "reviewed", OrderID: orderID})
+	case errors.Is(err, assignment.ErrReviewClosed):
+		h.render(w, http.StatusConflict, reviewPageData{State: "closed", OrderID: orderID})
+	case errors.Is(err, interfaces.ErrNotFound), errors.Is(err, assignment.ErrReviewsDisabled):
+		h.render(w, http.StatusNotFound, reviewPageData{State: "invalid"})
+	default:
+		h.log.Error("Failed to handle review", "orderID", orderID, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *ReviewHandler) render(w http.ResponseWriter, status int, data reviewPageData) {
+	data.MaxComment = models.MaxReviewComment
+	// Звёзды в разметке идут от 5 к 1 и разворачиваются стилями: так
+	// подсветку выбранной оценки делает CSS без скриптов.
+	for rating := models.MaxReviewRating; rating >= models.MinReviewRating; rating-- {
+		data.Ratings = append(data.Ratings, rating)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := reviewPage.Execute(w, data); err != nil {
+		h.log.Error("Failed to render review page", "error", err)
+	}
+}
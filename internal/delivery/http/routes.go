@@ -0,0 +1,55 @@
+package delivery
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/CAATHARSIS/courier-bot/internal/auth"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+type Route struct {
+	Method  string
+	Path    string
+	Scope   models.Scope
+	Handler http.HandlerFunc
+}
+
+func (h *AdminHandler) Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/api/v1/couriers", models.ScopeRead, h.HandleListCouriers},
+		{http.MethodGet, "/api/v1/couriers/available", models.ScopeRead, h.HandleListAvailableCouriers},
+		{http.MethodGet, "/api/v1/couriers/{id}", models.ScopeRead, h.HandleGetCourier},
+		{http.MethodGet, "/api/v1/couriers/{id}/availability", models.ScopeRead, h.HandleGetCourierAvailability},
+		{http.MethodGet, "/api/v1/couriers/{id}/reviews", models.ScopeRead, h.HandleListCourierReviews},
+		{http.MethodPost, "/api/v1/couriers/{id}/activate", models.ScopeDispatcher, h.HandleActivateCourier},
+		{http.MethodPost, "/api/v1/couriers/{id}/deactivate", models.ScopeDispatcher, h.HandleDeactivateCourier},
+		{http.MethodDelete, "/api/v1/couriers/{id}", models.ScopeAdmin, h.HandleDeleteCourier},
+		{http.MethodGet, "/api/v1/orders/{id}", models.ScopeRead, h.HandleGetOrder},
+		{http.MethodPost, "/api/v1/orders/{id}/assign", models.ScopeDispatcher, h.HandleAssignOrder},
+		{http.MethodPost, "/api/v1/orders/{id}/unassign", models.ScopeDispatcher, h.HandleUnassignOrder},
+		{http.MethodPost, "/api/v1/orders/{id}/cancel", models.ScopeDispatcher, h.HandleCancelOrder},
+		{http.MethodGet, "/api/v1/orders/{id}/messages", models.ScopeRead, h.HandleListOrderMessages},
+		{http.MethodPost, "/api/v1/orders/{id}/messages", models.ScopeDispatcher, h.HandleRelayCustomerMessage},
+		{http.MethodGet, "/api/v1/orders/{id}/notifications", models.ScopeRead, h.HandleListOrderNotifications},
+		{http.MethodPost, "/api/v1/reviews/{id}/hide", models.ScopeDispatcher, h.HandleHideReview},
+		{http.MethodPost, "/api/v1/reviews/{id}/unhide", models.ScopeDispatcher, h.HandleUnhideReview},
+		{http.MethodGet, "/api/v1/assignments", models.ScopeRead, h.HandleListAssignments},
+	}
+}
+
+// Register вешает маршруты админского API на mux, каждый за проверкой
+// токена с нужным scope. Что таблица маршрутов совпадает с openapi.json,
+// проверяет TestRoutesMatchOpenAPISpec.
+func (h *AdminHandler) Register(mux *http.ServeMux, authService *auth.Service) {
+	for _, route := range h.Routes() {
+		pattern := route.Method + " " + route.Path
+		mux.HandleFunc(pattern, tracing.Middleware(pattern, authService.Require(route.Scope, route.Handler)))
+	}
+
+	mux.HandleFunc("GET /api/v1/openapi.json", h.HandleOpenAPI)
+}
@@ -0,0 +1,179 @@
+package delivery
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type specParameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type specOperation struct {
+	Scope      string          `json:"x-required-scope"`
+	Parameters []specParameter `json:"parameters"`
+}
+
+type spec struct {
+	Paths      map[string]map[string]specOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]specParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// TestRoutesMatchOpenAPISpec сверяет таблицу маршрутов с openapi.json:
+// каждый маршрут описан с тем же scope, параметры пути совпадают
+// с шаблоном, а параметры запроса — с тем, что читает обработчик.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	var doc spec
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("failed to parse openapi spec: %v", err)
+	}
+
+	handlerQueries := queryParamsByHandler(t, "admin.go")
+
+	documented := make(map[string]specOperation)
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			documented[strings.ToUpper(method)+" "+path] = operation
+		}
+	}
+
+	var h *AdminHandler
+	for _, route := range h.Routes() {
+		key := route.Method + " " + route.Path
+
+		operation, ok := documented[key]
+		if !ok {
+			t.Errorf("%s is not documented", key)
+			continue
+		}
+		delete(documented, key)
+
+		if operation.Scope != string(route.Scope) {
+			t.Errorf("%s: documented scope %q, route requires %q", key, operation.Scope, route.Scope)
+		}
+
+		var pathParams, queryParams []string
+		for _, param := range operation.Parameters {
+			if param.Ref != "" {
+				resolved, ok := doc.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+				if !ok {
+					t.Errorf("%s: unknown parameter %s", key, param.Ref)
+					continue
+				}
+				param = resolved
+			}
+
+			switch param.In {
+			case "path":
+				if !param.Required {
+					t.Errorf("%s: path parameter %q is not required", key, param.Name)
+				}
+				pathParams = append(pathParams, param.Name)
+			case "query":
+				queryParams = append(queryParams, param.Name)
+			}
+		}
+
+		var wantPath []string
+		for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			wantPath = append(wantPath, match[1])
+		}
+
+		if !equalSets(pathParams, wantPath) {
+			t.Errorf("%s: documented path parameters %v, route has %v", key, pathParams, wantPath)
+		}
+
+		handler := handlerName(route.Handler)
+		if !equalSets(queryParams, handlerQueries[handler]) {
+			t.Errorf("%s: documented query parameters %v, %s reads %v", key, queryParams, handler, handlerQueries[handler])
+		}
+	}
+
+	for key := range documented {
+		t.Errorf("%s is documented but has no route", key)
+	}
+}
+
+// queryParamsByHandler собирает параметры запроса, которые методы
+// AdminHandler читают через query.Get("...").
+func queryParamsByHandler(t *testing.T, filename string) map[string][]string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", filename, err)
+	}
+
+	params := make(map[string][]string)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != "Get" {
+				return true
+			}
+
+			receiver, ok := selector.X.(*ast.Ident)
+			if !ok || receiver.Name != "query" {
+				return true
+			}
+
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+
+			name, err := strconv.Unquote(literal.Value)
+			if err == nil {
+				params[fn.Name.Name] = append(params[fn.Name.Name], name)
+			}
+
+			return true
+		})
+	}
+
+	return params
+}
+
+// handlerName возвращает имя метода по значению вида h.HandleListCouriers.
+func handlerName(handler any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func equalSets(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
@@ -0,0 +1,134 @@
+package delivery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+const (
+	headerSignature = "X-Signature"
+	headerTimestamp = "X-Timestamp"
+	headerNonce     = "X-Nonce"
+	headerKeyID     = "X-Key-Id"
+
+	defaultKeyID = "default"
+)
+
+var (
+	errMissingHeaders   = errors.New("missing signature headers")
+	errUnknownKey       = errors.New("unknown signing key")
+	errStaleTimestamp   = errors.New("timestamp outside tolerance window")
+	errInvalidSignature = errors.New("invalid signature")
+	errReplayedRequest  = errors.New("nonce already used")
+)
+
+// SignatureVerifier проверяет подпись вебхука: HMAC-SHA256 от
+// "<timestamp>.<nonce>.<body>" секретом, выбранным по X-Key-Id.
+type SignatureVerifier struct {
+	secrets   map[string]string
+	tolerance time.Duration
+	nonces    interfaces.WebhookNonce
+	log       *slog.Logger
+}
+
+func NewSignatureVerifier(secrets map[string]string, tolerance time.Duration, nonces interfaces.WebhookNonce, log *slog.Logger) *SignatureVerifier {
+	return &SignatureVerifier{
+		secrets:   secrets,
+		tolerance: tolerance,
+		nonces:    nonces,
+		log:       log,
+	}
+}
+
+func (v *SignatureVerifier) Enabled() bool {
+	return len(v.secrets) > 0
+}
+
+func (v *SignatureVerifier) Verify(ctx context.Context, r *http.Request, body []byte) error {
+	signature := r.Header.Get(headerSignature)
+	timestampHeader := r.Header.Get(headerTimestamp)
+	nonce := r.Header.Get(headerNonce)
+
+	if signature == "" || timestampHeader == "" || nonce == "" {
+		return errMissingHeaders
+	}
+
+	keyID := r.Header.Get(headerKeyID)
+	if keyID == "" {
+		keyID = defaultKeyID
+	}
+
+	secret, ok := v.secrets[keyID]
+	if !ok {
+		return errUnknownKey
+	}
+
+	unixSeconds, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %v", err)
+	}
+
+	timestamp := time.Unix(unixSeconds, 0)
+	if skew := time.Since(timestamp); skew > v.tolerance || skew < -v.tolerance {
+		return errStaleTimestamp
+	}
+
+	expected := v.compute(secret, timestampHeader, nonce, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errInvalidSignature
+	}
+
+	fresh, err := v.nonces.Remember(ctx, keyID, nonce, timestamp.Add(v.tolerance))
+	if err != nil {
+		return fmt.Errorf("failed to check nonce: %v", err)
+	}
+
+	if !fresh {
+		return errReplayedRequest
+	}
+
+	return nil
+}
+
+func (v *SignatureVerifier) compute(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (v *SignatureVerifier) RunNonceCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := v.nonces.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				v.log.Error("Failed to clean up webhook nonces", "error", err)
+				continue
+			}
+
+			if deleted > 0 {
+				v.log.Debug("Expired webhook nonces deleted", "count", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+func DBPing(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// MigrationVersion проверяет, что схема не в dirty-состоянии и накатана
+// как минимум до версии, которую ожидает этот бинарник.
+func MigrationVersion(db *sql.DB, expected uint) CheckFunc {
+	return func(ctx context.Context) error {
+		var version uint
+		var dirty bool
+
+		err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+		if err != nil {
+			return fmt.Errorf("failed to read migration version: %v", err)
+		}
+
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+
+		if version < expected {
+			return fmt.Errorf("schema version %d is behind expected %d", version, expected)
+		}
+
+		return nil
+	}
+}
+
+// Heartbeat проверяет, что фоновый цикл отмечался не позже maxAge назад.
+// Пока active возвращает false (например, инстанс не лидер), проверка
+// считается пройденной.
+func Heartbeat(last func() time.Time, maxAge time.Duration, active func() bool) CheckFunc {
+	return func(ctx context.Context) error {
+		if !active() {
+			return nil
+		}
+
+		lastBeat := last()
+		if lastBeat.IsZero() {
+			return fmt.Errorf("no heartbeat yet")
+		}
+
+		if age := time.Since(lastBeat); age > maxAge {
+			return fmt.Errorf("last heartbeat %s ago, limit %s", age.Round(time.Second), maxAge)
+		}
+
+		return nil
+	}
+}
+
+// Cached запоминает результат проверки на ttl, чтобы частые пробы не
+// упирались во внешние API.
+func Cached(ttl time.Duration, check CheckFunc) CheckFunc {
+	var mu sync.Mutex
+	var checkedAt time.Time
+	var lastErr error
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+
+		lastErr = check(ctx)
+		checkedAt = time.Now()
+
+		return lastErr
+	}
+}
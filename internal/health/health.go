@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusShutdown = "shutting_down"
+)
+
+// Checker собирает проверки готовности. Liveness отвечает только за то,
+// что процесс жив и обслуживает HTTP; readiness прогоняет все проверки.
+type Checker struct {
+	checks       []namedCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+	log          *slog.Logger
+}
+
+func NewChecker(timeout time.Duration, log *slog.Logger) *Checker {
+	return &Checker{
+		timeout: timeout,
+		log:     log,
+	}
+}
+
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown переводит readiness в failing, чтобы балансировщик
+// перестал слать трафик ещё до остановки HTTP-сервера.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) HandleLive(w http.ResponseWriter, r *http.Request) {
+	c.writeReport(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+func (c *Checker) HandleReady(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != StatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	c.writeReport(w, statusCode, report)
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			started := time.Now()
+			err := nc.check(ctx)
+
+			result := CheckResult{
+				Status:     StatusOK,
+				DurationMS: time.Since(started).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = StatusFailing
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = StatusFailing
+			}
+			mu.Unlock()
+		}(check)
+	}
+
+	wg.Wait()
+
+	if c.shuttingDown.Load() {
+		report.Status = StatusShutdown
+	}
+
+	return report
+}
+
+func (c *Checker) writeReport(w http.ResponseWriter, statusCode int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		c.log.Error("Failed to encode health report", "error", err)
+	}
+}
@@ -0,0 +1,405 @@
+package i18n
+
+var en = map[string]string{
+	"language.name":    "🇬🇧 English",
+	"language.title":   "🌐 *Language*\n\nChoose the interface language:",
+	"language.changed": "✅ Interface language: English",
+
+	"menu.orders":   "📋 My orders",
+	"menu.status":   "ℹ️ Status",
+	"menu.settings": "⚙️ Settings",
+	"menu.help":     "🆘 Help",
+	"menu.courier":  "Courier",
+
+	"command.start": "Start the bot",
+
+	"weekday.0": "Sun",
+	"weekday.1": "Mon",
+	"weekday.2": "Tue",
+	"weekday.3": "Wed",
+	"weekday.4": "Thu",
+	"weekday.5": "Fri",
+	"weekday.6": "Sat",
+
+	"duration.minutes#one":   "%d minute",
+	"duration.minutes#other": "%d minutes",
+	"duration.hours":         "%d h %d min",
+
+	"error.server":         "Server error, please try again later ⌛",
+	"error.command":        "❌ Failed to process the command, please try again.",
+	"error.access":         "❌ Access check failed",
+	"error.unknown_action": "❌ Unknown action.",
+
+	"callback.stale":   "This button is outdated, please open the screen again",
+	"callback.invalid": "This button failed verification",
+
+	"button.back":          "↩️ Back",
+	"button.confirm":       "✅ Confirm",
+	"button.cancel":        "❌ Cancel",
+	"button.yes":           "✅ Yes",
+	"button.no":            "❌ No",
+	"button.refresh":       "🔄 Refresh",
+	"button.prev":          "◀️ Prev",
+	"button.next":          "Next ▶️",
+	"button.stats":         "📊 Statistics",
+	"button.feedback":      "⭐ Feedback",
+	"button.new_order":     "🔄 New order",
+	"button.accept":        "✅ Accept",
+	"button.reject":        "❌ Decline",
+	"button.accept_order":  "✅ Accept order",
+	"button.reject_order":  "❌ Decline order",
+	"button.route":         "🗺️ Build route",
+	"button.route_short":   "🗺️ Route",
+	"button.call":          "📞 Call",
+	"button.show_phone":    "📞 Show number",
+	"button.contact":       "💬 Contact customer",
+	"button.contact_short": "💬 Customer",
+	"button.contact_write": "✍️ Write a message",
+	"button.complete":      "🏁 Delivery completed",
+	"button.delivery_done": "✅ Delivery completed",
+	"button.problem":       "🚨 Delivery problem",
+	"button.problem_short": "🚨 Problem",
+	"button.problems":      "Something went wrong",
+	"button.picked":        "🚗 Picked up",
+	"button.on_the_way":    "🚚 On the way",
+	"button.im_here":       "📍 I'm here",
+	"button.delivered":     "✅ Delivered",
+	"button.cancel_order":  "❌ Cancel order",
+
+	"start.new": "Welcome, %s!\n\n" +
+		"You are now registered as a courier.\n",
+	"start.back": "Welcome back, %s!\n\n",
+	"start.intro": "I'm the delivery courier bot and I'll help you with your work.\n\n" +
+		"*Main commands:*\n" +
+		"• 📋 My orders - view active orders\n" +
+		"• ℹ️ Status - information about your status\n" +
+		"• ⚙️ Settings - notification settings\n" +
+		"• 🆘 Help - how to use the bot\n\n",
+	"start.new_hint":  "Start a shift in settings to receive orders!",
+	"start.back_hint": "New orders are on their way!",
+
+	"help.text": "🆘 *Bot help*\n\n" +
+		"*How the bot works:*\n" +
+		"• 📦 You get notified about new orders\n" +
+		"• ✅ You can accept or decline an order\n" +
+		"• 🗺️ Navigate to the delivery address\n" +
+		"• 📞 Contact the customer\n" +
+		"• 🏁 Mark delivery statuses\n\n" +
+		"*Main buttons:*\n" +
+		"• ✅ Accept - take the order\n" +
+		"• ❌ Decline - refuse the order\n" +
+		"• 🗺️ Build route - open navigation\n" +
+		"• 📞 Call - contact the customer\n" +
+		"• 🏁 Delivery completed - mark the order as done\n\n" +
+		"If something goes wrong, contact the administrator.",
+
+	"unknown.command": "❓ Unknown command\n\n" +
+		"Use the menu buttons or send /help for help.",
+
+	"location.live_started": "📍 Live location sharing is on",
+
+	"orders.load_failed":   "❌ Failed to load your orders. Please try again later.",
+	"orders.title.active":  "📋 *Your active orders*\n\n",
+	"orders.title.today":   "✅ *Delivered today*\n\n",
+	"orders.title.history": "🗂 *Order history*\n\n",
+	"orders.empty.active": "You have no active orders right now.\n\n" +
+		"💡 *Tip:* Make sure you are on shift.\n" +
+		"New orders will arrive automatically!",
+	"orders.empty.today":   "You haven't delivered any orders today yet.",
+	"orders.empty.history": "No delivered orders yet.",
+	"orders.total":         "Total orders: %d\n\nChoose an order to see its details:",
+	"orders.tab.active":    "📋 Active",
+	"orders.tab.today":     "✅ Today",
+	"orders.tab.history":   "🗂 History",
+	"orders.tab_current":   "• %s",
+	"orders.page":          "🔄 %d/%d",
+	"orders.item_done":     "📦 #%d · %s · 💰 %d",
+	"orders.item":          "📦 Order #%d - %s",
+
+	"order_status.pending":     "⏳ Awaiting confirmation",
+	"order_status.waiting":     "⏳ Awaiting response",
+	"order_status.accepted":    "✅ Accepted",
+	"order_status.in_delivery": "🚗 Out for delivery",
+	"order_status.delivered":   "✅ Delivered",
+	"order_status.rejected":    "❌ Declined",
+	"order_status.expired":     "⏰ Expired",
+	"order_status.processing":  "📋 Processing",
+
+	"time.not_set":    "⏰ No time set",
+	"time.overdue":    "🚨 URGENT! Overdue",
+	"time.in_minutes": "🚨 in %d min",
+	"time.today":      "🕐 Today at %s",
+	"time.tomorrow":   "📅 Tomorrow at %s",
+	"time.weekday":    "📅 %s at %s",
+	"time.date":       "📅 %s at %s",
+
+	"status.load_failed": "❌ Failed to load your status. Please try again later.",
+	"status.ready":       "You are ready to take new orders! 🚀",
+	"status.no_shift":    "Start a shift in settings to receive orders.",
+	"status.on_break":    "You are on a break, new orders are paused.",
+	"status.text": "ℹ️ *Your status*\n\n" +
+		"• 📱 Status: *%s*\n" +
+		"• 📦 Active orders: *%d*\n" +
+		"• 📊 Deliveries today: *%d*\n" +
+		"• 💰 Earned today: *%d*\n" +
+		"• ⭐ Rating: *%s*\n\n" +
+		"%s",
+
+	"shift_status.off":   "Off shift",
+	"shift_status.break": "On a break",
+	"shift_status.on":    "On shift",
+
+	"stats.load_failed":      "❌ Failed to load statistics. Please try again later.",
+	"stats.period.today":     "today",
+	"stats.period.week":      "this week",
+	"stats.period.month":     "this month",
+	"stats.button.today":     "Today",
+	"stats.button.week":      "Week",
+	"stats.button.month":     "Month",
+	"stats.title":            "📊 *Statistics for %s*\n",
+	"stats.since":            "_since %s_\n\n",
+	"stats.deliveries#one":   "• 📦 Delivered: *%d order*\n",
+	"stats.deliveries#other": "• 📦 Delivered: *%d orders*\n",
+	"stats.earnings":         "• 💰 Earned: *%d*\n",
+	"stats.acceptance":       "• ✅ Offers accepted: *%d of %d (%.0f%%)*\n",
+	"stats.acceptance_none":  "• ✅ Offers accepted: *—*\n",
+	"stats.avg_time":         "• ⏱ Average delivery time: *%s*\n",
+	"stats.avg_time_none":    "• ⏱ Average delivery time: *—*\n",
+	"stats.on_time":          "• 🎯 On time: *%.0f%%*\n",
+	"stats.on_time_none":     "• 🎯 On time: *—*\n",
+	"stats.rating":           "• ⭐ Rating: *%s*",
+	"rating.none":            "no ratings yet",
+
+	"feedback.title":       "⭐ *Customer feedback*\nRating: *%s*, reviews: *%d*\n\n",
+	"feedback.item":        "%s order #%d, %s\n",
+	"feedback.comment":     "_%s_\n",
+	"feedback.none":        "No reviews yet. They will appear once customers rate their deliveries.",
+	"feedback.load_failed": "❌ Failed to load feedback. Please try again later.",
+
+	"settings.title": "⚙️ *Settings*\n\n" +
+		"Choose a setting to change:",
+	"settings.button.notifications": "🔔 Notifications",
+	"settings.button.workmode":      "Work mode",
+	"settings.button.schedule":      "📅 Schedule",
+	"settings.button.contacts":      "Contacts",
+	"settings.button.language":      "🌐 Language",
+	"settings.button.maps":          "🗺️ Navigator",
+	"settings.contacts":             "Contact information is coming soon.",
+
+	"notifications.title": "🔔 *Notifications*\n\n" +
+		"• Quiet mode — all messages arrive without sound.\n" +
+		"• Silent offers — only off shift: during a shift new orders always arrive with sound.\n" +
+		"• Everything else can be turned off. Assignments, cancellations and customer messages always arrive.",
+	"notifications.setting.quiet":         "Quiet mode",
+	"notifications.setting.silent_offers": "Silent offers",
+	"notifications.setting.order_ready":   "Order assembled",
+	"notifications.setting.reminders":     "Schedule reminders",
+	"notifications.setting.shift_summary": "Shift summary",
+	"notifications.setting.daily_summary": "Daily summary",
+	"notifications.load_failed":           "❌ Failed to save notification settings. Please try again later.",
+
+	"daily_summary.title":  "🌙 *Daily summary, %s*\n\n",
+	"daily_summary.footer": "\n_You can turn off the daily summary in notification settings._",
+
+	"workmode.current":           "⚙️ *Current status: %s*",
+	"workmode.started_at":        "\n\nShift started at %s",
+	"workmode.button.start":      "▶️ Start shift",
+	"workmode.button.resume":     "▶️ Back from break",
+	"workmode.button.break":      "☕ Break",
+	"workmode.button.end":        "⏹ End shift",
+	"workmode.shift_started":     "▶️ *Shift started*\n\nYou are now *\"active\"*, wait for new order notifications",
+	"workmode.break_started":     "☕ *Break*\n\nNo new orders will arrive until you are back from the break",
+	"workmode.break_ended":       "▶️ *Welcome back!*\n\nWait for new order notifications",
+	"workmode.has_undelivered":   "❌ You can't end the shift while you have undelivered orders or unanswered offers.\n\nAnswer the offers, and complete or cancel the orders in «My orders».",
+	"workmode.already_open":      "ℹ️ The shift has already started.",
+	"workmode.not_open":          "ℹ️ The shift hasn't started.",
+	"workmode.already_on_break":  "ℹ️ You are already on a break.",
+	"workmode.not_on_break":      "ℹ️ You are not on a break.",
+	"next.title":                 "What's next?",
+	"navigation.no_address":      "❌ Failed to get the address for navigation",
+	"call.no_phone":              "❌ Failed to get the phone number",
+	"order.processing_error":     "❌ Failed to process the order",
+	"order.accepting":            "✅ Accepting the order...",
+	"order.accept_failed":        "❌ Failed to accept the order. Please try again later.",
+	"order.reject_failed":        "❌ Failed to decline the order. Please try again later.",
+	"order.cancel_failed":        "❌ Failed to cancel the order. Please try again later.",
+	"order.details_failed":       "❌ Failed to get the order details.",
+	"order.back_failed":          "❌ Failed to return to the order.",
+	"order.not_found":            "❌ Order not found.",
+	"order.not_yours":            "❌ This order is not assigned to you.",
+	"order.already_cancelled":    "ℹ️ Order #%d has already been cancelled.",
+	"order.already_delivered":    "ℹ️ Order #%d has already been delivered.",
+	"order.cancelled_locked":     "🚫 Order #%d is cancelled, no actions are available.",
+	"delivery.confirm_error":     "❌ Failed to confirm the order.",
+	"delivery.update_failed":     "❌ Failed to update the order status.",
+	"problem.no_answer":          "📞 Customer doesn't answer",
+	"problem.wrong_address":      "🏠 Wrong address",
+	"problem.payment":            "💳 Payment problem",
+	"problem.technical":          "🚗 Technical problems",
+	"problem.other":              "❔ Other",
+	"cancel_reason.refused":      "🙅 Customer refused",
+	"cancel_reason.unreachable":  "📵 Customer unreachable",
+	"cancel_reason.address":      "🏠 Wrong address",
+	"cancel_reason.other":        "❔ Other",
+	"navigation.text":            "🗺️ *Route for order #%d*\n\n*Address:* %s",
+	"call.text":                  "📞 *Customer phone for order #%d*\n\n`%s`\n\nThe number is available while you are delivering the order: this message is deleted once it is closed.",
+	"order.completed":            "✅ *Order #%d completed!*\n\nCongratulations on a successful delivery!",
+	"order.problem":              "🚨 *Problem with order #%d*\n\nChoose the problem type:",
+	"order.cancel":               "❌ *Cancelling order #%d*\n\nChoose the cancellation reason:",
+	"delivery.confirmed":         "🎉 *Order #%d delivered!*\n\n✅ The delivery is completed and confirmed!\n\nThank you for your work!",
+	"delivery.confirm_cancelled": "ℹ️ *Delivery confirmation cancelled*\n\nOrder #%d stays active.\n\nYou can complete the delivery later or report a problem.",
+
+	"cancel_reason.text.refused":     "The customer refused the order",
+	"cancel_reason.text.unreachable": "The customer is unreachable",
+	"cancel_reason.text.address":     "Wrong address",
+	"cancel_reason.text.other":       "Other",
+
+	"maps.title":             "🗺️ *Navigator*\n\nWhich app should open routes to customers?",
+	"maps.changed":           "✅ Routes will open in %s",
+	"maps.change":            "⚙️ Choose app",
+	"navigation.choose_hint": "\n\n💡 Pick an app in settings to open routes with one tap.",
+
+	"maps.app.yandex": "Yandex Maps",
+	"maps.app.2gis":   "2GIS",
+	"maps.app.google": "Google Maps",
+	"maps.app.apple":  "Apple Maps",
+
+	"maps.open.yandex": "🧭 Open in Yandex Maps",
+	"maps.open.2gis":   "🧭 Open in 2GIS",
+	"maps.open.google": "🧭 Open in Google Maps",
+	"maps.open.apple":  "🧭 Open in Apple Maps",
+
+	"order.details": "📋 *Order #%d details*\n\n" +
+		"*Status:* %s\n" +
+		"*Address:* %s %s\n" +
+		"*Customer:* %s\n" +
+		"*Delivery date:* %s\n\n" +
+		"Use the buttons below to manage the delivery:",
+	"order.picked": "📦 *Order #%d picked up!*\n\n" +
+		"✅ You have picked up the order from the restaurant.\n\n" +
+		"*Order information:*\n" +
+		"• Delivery address: %s, %s\n" +
+		"• Customer: %s\n\n" +
+		"🚗 You can head to the customer now.",
+	"contact.menu": "💬 *Contact the customer of order #%d*\n\n" +
+		"Messages reach the customer through the bot, your number stays hidden. " +
+		"Pick a quick message or write your own.",
+	"contact.message.downstairs": "Your courier has arrived and is waiting downstairs.",
+	"contact.message.late":       "Your courier is running about 10 minutes late, sorry for the wait.",
+	"contact.message.at_door":    "Your courier is at your door.",
+	"contact.message.no_answer":  "Your courier cannot reach you by phone, please answer the call.",
+
+	"contact.template.downstairs": "🚪 I'm downstairs",
+	"contact.template.late":       "⏱️ Running 10 min late",
+	"contact.template.at_door":    "🔔 I'm at the door",
+	"contact.template.no_answer":  "📵 Can't reach you",
+	"contact.compose_prompt":      "✍️ Send one message with what to pass to the customer of order #%d.",
+	"contact.compose_cancelled":   "The message was not sent to the customer.",
+	"contact.text_only":           "Only text can be passed to the customer. Write a message or tap “Cancel”.",
+	"contact.sent":                "✅ Sent to the customer:\n\n%s",
+	"contact.send_failed":         "❌ Failed to pass the message to the customer. Please try again later.",
+	"contact.closed":              "ℹ️ Order #%d is closed, the customer can no longer be contacted through the bot.",
+	"contact.incoming":            "💬 *Message from the customer of order #%d:*\n\n%s",
+
+	"order.delivering": "🚗 *Order #%d is on the way!*\n\n" +
+		"📍 You are heading to the customer.\n\n" +
+		"*Tips:*\n" +
+		"• 🗺️ Use navigation for the best route\n" +
+		"• 📞 Call the customer 10-15 minutes before arrival\n" +
+		"• ⏱️ Keep the current traffic in mind\n\n" +
+		"Estimated arrival time: *15-20 minutes*",
+	"order.arrived": "📍 *You have arrived!*\n\n" +
+		"Order #%d is ready to be handed over.\n\n" +
+		"*Steps:*\n" +
+		"1. 💬 Contact the customer to meet\n" +
+		"2. ✅ Hand over the order\n" +
+		"3. 💰 Take the payment (if needed)\n" +
+		"4. 🏁 Confirm the delivery\n\n" +
+		"Customer: %s",
+	"order.confirm_delivery": "🏁 *Delivery confirmation*\n\n" +
+		"Order #%d is ready to be marked as delivered.\n\n" +
+		"*Please confirm:*\n" +
+		"✅ The order is handed over to the customer\n" +
+		"✅ The payment is received (if required)\n" +
+		"The order will be completed after confirmation.",
+
+	"schedule.title":                 "📅 *Your schedule*\n\n",
+	"schedule.no_windows":            "No weekly windows yet.\n",
+	"schedule.window":                "• %s %s\n",
+	"schedule.exceptions":            "\n*Exceptions:*\n",
+	"schedule.exception_hours":       "• %s: %s–%s\n",
+	"schedule.exception_off":         "• %s: day off\n",
+	"schedule.footer":                "\nI'll remind you to start a shift when a window begins. Tap an entry to delete it.",
+	"schedule.new_window":            "📅 *New window*\n\nChoose a weekday:",
+	"schedule.new_exception":         "📅 *Exception*\n\nChoose a date:",
+	"schedule.exception_type":        "What changes on this day?",
+	"schedule.from_hour":             "From what hour?",
+	"schedule.to_hour":               "Until what hour?",
+	"schedule.window_added":          "✅ Window added",
+	"schedule.window_removed":        "🗑 Window removed",
+	"schedule.day_off_added":         "✅ Day off added",
+	"schedule.exception_added":       "✅ Exception added",
+	"schedule.exception_removed":     "🗑 Exception removed",
+	"schedule.invalid_time":          "❌ Invalid time. Please try again.",
+	"schedule.already_removed":       "ℹ️ This entry has already been removed.",
+	"schedule.load_failed":           "❌ Failed to load the schedule. Please try again later.",
+	"schedule.button.add_window":     "➕ Window",
+	"schedule.button.add_exception":  "➕ Exception",
+	"schedule.button.delete_window":  "🗑 %s %s",
+	"schedule.button.delete_day_off": "🗑 %s day off",
+	"schedule.button.delete_hours":   "🗑 %s %s–%s",
+	"schedule.button.day_off":        "🚫 Day off",
+	"schedule.button.other_hours":    "🕐 Other hours",
+
+	"offer.title":          "New order!",
+	"offer.deadline#one":   "⏰ *You have %d minute to decide*\n\n",
+	"offer.deadline#other": "⏰ *You have %d minutes to decide*\n\n",
+	"offer.prompt":         "Accept or decline the order:",
+	"offer.stale":          "ℹ️ The offer for order #%d is no longer relevant",
+	"offer.expired":        "⏰ The time to accept the order has expired",
+	"offer.accepted":       "✅ Order #%d accepted! Delivery details are coming.",
+	"offer.rejected":       "❌ You declined order #%d.",
+
+	"delivery.title":                 "Delivery of order #%d",
+	"delivery.controls":              "*Use the buttons below to manage the delivery*",
+	"delivery.date":                  "%s at %s",
+	"delivery.date_not_set":          "not set",
+	"delivery.card.address":          "*Delivery address:*\n%s, %s\n",
+	"delivery.card.flat":             "*Apartment:*\n%s\n",
+	"delivery.card.entrance":         "*Entrance:*\n%s\n",
+	"delivery.card.date":             "*Delivery date:*\n%s\n",
+	"delivery.card.customer":         "*Customer:*\n%s\n",
+	"delivery.card.total":            "*Order total:*\n%d\n\n",
+	"delivery.card.delivery_price":   "*Delivery fee:*\n%d\n\n",
+	"delivery.card.no_entrance":      "*No entrance given, contact the customer for details\n\n*",
+	"delivery.card.no_flat":          "*No apartment given, contact the customer for details\n\n*",
+	"delivery.card.no_flat_entrance": "*No apartment or entrance given, contact the customer for details\n\n*",
+
+	"order.changed":              "✏️ Order #%d changed",
+	"order.assembled":            "📦 Order #%d is packed and ready for pickup.",
+	"order_change.address":       "delivery address",
+	"order_change.delivery_date": "delivery time",
+	"order_change.contacts":      "customer contacts",
+	"cancel.notice":              "🚫 *Order #%d cancelled by %s*",
+	"cancel.reason":              "\n\n*Reason:* %s",
+	"cancel.thanks":              "✅ Order #%d cancelled. Thank you for telling us the reason.",
+	"cancel.offer_revoked":       "🚫 The offer for order #%d was withdrawn: the order is cancelled.",
+	"cancel.source.shop":         "the shop",
+	"cancel.source.dispatcher":   "the dispatcher",
+	"cancel.source.courier":      "the courier",
+	"manual.offer_revoked":       "ℹ️ The offer for order #%d was withdrawn by the dispatcher.",
+	"manual.assigned":            "📌 The dispatcher assigned order #%d to you",
+	"manual.unassigned":          "ℹ️ Order #%d was taken off you by the dispatcher.",
+	"shift.end.courier":          "Shift ended",
+	"shift.end.inactivity":       "Shift ended automatically due to inactivity",
+	"shift.end.admin":            "Shift ended by the administrator",
+	"shift.summary.title":        "🏁 *%s*\n\n",
+	"shift.summary.started":      "• 🕐 Started: *%s*\n",
+	"shift.summary.worked":       "• ⏱ Worked: *%s*\n",
+	"shift.summary.breaks#one":   "• ☕ Breaks: *%d break, %s*\n",
+	"shift.summary.breaks#other": "• ☕ Breaks: *%d breaks, %s*\n",
+	"shift.summary.deliveries":   "• 📦 Delivered: *%d*\n",
+	"shift.summary.earnings":     "• 💰 Earned: *%d*",
+	"shift.summary.restart":      "\n\nStart a shift in settings when you are ready to take orders.",
+	"availability.reminder":      "⏰ *Your scheduled shift is %s–%s*\n\nStart a shift in settings to receive orders.",
+}
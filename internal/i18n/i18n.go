@@ -0,0 +1,228 @@
+// Package i18n — каталог сообщений бота. Ключи общие для всех языков,
+// формы множественного числа лежат под ключами вида "key#one", "key#few".
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	Default = RU
+)
+
+// Languages — порядок языков в переключателе настроек.
+var Languages = []Lang{RU, EN}
+
+var catalogs = map[Lang]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+var pluralRules = map[Lang]func(n int) string{
+	RU: russianPlural,
+	EN: englishPlural,
+}
+
+var pluralForms = map[Lang][]string{
+	RU: {"one", "few", "many"},
+	EN: {"one", "other"},
+}
+
+// Parse возвращает поддерживаемый язык или Default.
+func Parse(value string) Lang {
+	lang := Lang(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+
+	return Default
+}
+
+// FromTelegram подбирает язык по LanguageCode пользователя Telegram.
+// Русскоязычным по умолчанию считается и пустой код, и соседние языки.
+func FromTelegram(code string) Lang {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+
+	switch code {
+	case "", "ru", "uk", "be", "kk":
+		return RU
+	}
+
+	if _, ok := catalogs[Lang(code)]; ok {
+		return Lang(code)
+	}
+
+	return EN
+}
+
+type Localizer struct {
+	lang Lang
+}
+
+func For(lang Lang) Localizer {
+	return Localizer{lang: Parse(string(lang))}
+}
+
+func (l Localizer) Lang() Lang {
+	return l.lang
+}
+
+// T форматирует сообщение key. Отсутствующий перевод берётся из языка по
+// умолчанию, отсутствующий ключ возвращается как есть.
+func (l Localizer) T(key string, args ...any) string {
+	message, ok := catalogs[l.lang][key]
+	if !ok {
+		message, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// N выбирает форму множественного числа для n. Само n передаётся первым
+// аргументом форматирования.
+func (l Localizer) N(key string, n int, args ...any) string {
+	form := pluralRules[l.lang](n)
+	return l.T(key+"#"+form, append([]any{n}, args...)...)
+}
+
+func (l Localizer) Weekday(weekday time.Weekday) string {
+	return l.T(fmt.Sprintf("weekday.%d", int(weekday)))
+}
+
+func (l Localizer) Duration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return l.N("duration.minutes", minutes)
+	}
+
+	return l.T("duration.hours", minutes/60, minutes%60)
+}
+
+// Match ищет среди keys тот, чей текст на любом из языков совпадает с text.
+// Нужен для кнопок reply-клавиатуры, которые приходят обычным сообщением.
+func Match(text string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		for _, catalog := range catalogs {
+			if message, ok := catalog[key]; ok && message == text {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z]`)
+
+func verbs(message string) string {
+	return strings.Join(verbPattern.FindAllString(strings.ReplaceAll(message, "%%", ""), -1), " ")
+}
+
+// Validate проверяет, что каждый ключ есть во всех языках, у множественных
+// ключей заведены все формы языка, а глаголы форматирования совпадают с
+// языком по умолчанию.
+func Validate() error {
+	plural := make(map[string]bool)
+	keys := make(map[string]bool)
+
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			base, _, isPlural := strings.Cut(key, "#")
+			keys[base] = true
+			if isPlural {
+				plural[base] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var errs []error
+
+	for _, lang := range Languages {
+		catalog := catalogs[lang]
+
+		for _, key := range sorted {
+			if !plural[key] {
+				message, ok := catalog[key]
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: missing key %q", lang, key))
+					continue
+				}
+				if want := verbs(catalogs[Default][key]); verbs(message) != want {
+					errs = append(errs, fmt.Errorf("%s: key %q has verbs %q, want %q", lang, key, verbs(message), want))
+				}
+				continue
+			}
+
+			want := verbs(catalogs[Default][key+"#"+pluralForms[Default][0]])
+			for _, form := range pluralForms[lang] {
+				message, ok := catalog[key+"#"+form]
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: missing plural form %q of key %q", lang, form, key))
+					continue
+				}
+				if verbs(message) != want {
+					errs = append(errs, fmt.Errorf("%s: key %q#%s has verbs %q, want %q", lang, key, form, verbs(message), want))
+				}
+			}
+		}
+
+		for key := range catalog {
+			base, form, isPlural := strings.Cut(key, "#")
+			if isPlural && !slices.Contains(pluralForms[lang], form) {
+				errs = append(errs, fmt.Errorf("%s: unknown plural form %q of key %q", lang, form, base))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func russianPlural(n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+func englishPlural(n int) string {
+	if n == 1 {
+		return "one"
+	}
+
+	return "other"
+}
@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	counts := []int{0, 1, 2, 3, 4, 5, 11, 12, 14, 21, 22, 25, 101, 111, 112, 1001}
+
+	russian := map[int]string{
+		0: "many", 1: "one", 2: "few", 4: "few", 5: "many", 11: "many", 12: "many", 14: "many",
+		21: "one", 22: "few", 25: "many", 101: "one", 111: "many", 112: "many", 1001: "one",
+	}
+	for n, want := range russian {
+		if got := russianPlural(n); got != want {
+			t.Errorf("russianPlural(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	for _, lang := range Languages {
+		rule, ok := pluralRules[lang]
+		if !ok {
+			t.Errorf("%s: no plural rule", lang)
+			continue
+		}
+
+		for _, n := range counts {
+			if form := rule(n); !slices.Contains(pluralForms[lang], form) {
+				t.Errorf("%s: rule(%d) = %q, not one of %v", lang, n, form, pluralForms[lang])
+			}
+		}
+
+		for key := range catalogs[lang] {
+			base, _, isPlural := strings.Cut(key, "#")
+			if !isPlural {
+				continue
+			}
+
+			for _, n := range counts {
+				if _, ok := catalogs[lang][base+"#"+rule(n)]; !ok {
+					t.Errorf("%s: no form %q of key %q for %d", lang, rule(n), base, n)
+				}
+			}
+		}
+	}
+}
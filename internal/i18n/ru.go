@@ -0,0 +1,409 @@
+package i18n
+
+var ru = map[string]string{
+	"language.name":    "🇷🇺 Русский",
+	"language.title":   "🌐 *Язык*\n\nВыберите язык интерфейса:",
+	"language.changed": "✅ Язык интерфейса: русский",
+
+	"menu.orders":   "📋 Мои заказы",
+	"menu.status":   "ℹ️ Статус",
+	"menu.settings": "⚙️ Настройки",
+	"menu.help":     "🆘 Помощь",
+	"menu.courier":  "Курьер",
+
+	"command.start": "Запустить бота",
+
+	"weekday.0": "Вс",
+	"weekday.1": "Пн",
+	"weekday.2": "Вт",
+	"weekday.3": "Ср",
+	"weekday.4": "Чт",
+	"weekday.5": "Пт",
+	"weekday.6": "Сб",
+
+	"duration.minutes#one":  "%d минута",
+	"duration.minutes#few":  "%d минуты",
+	"duration.minutes#many": "%d минут",
+	"duration.hours":        "%d ч %d мин",
+
+	"error.server":         "Ошибка на стороне сервера, попробуйте позже ⌛",
+	"error.command":        "❌ Ошибка обработки команды, попробуйте еще раз.",
+	"error.access":         "❌ Ошибка проверки доступа",
+	"error.unknown_action": "❌ Неизвестное действие.",
+
+	"callback.stale":   "Эта кнопка устарела, откройте экран заново",
+	"callback.invalid": "Кнопка не прошла проверку",
+
+	"button.back":          "↩️ Назад",
+	"button.confirm":       "✅ Подтвердить",
+	"button.cancel":        "❌ Отмена",
+	"button.yes":           "✅ Да",
+	"button.no":            "❌ Нет",
+	"button.refresh":       "🔄 Обновить",
+	"button.prev":          "◀️ Пред.",
+	"button.next":          "След. ▶️",
+	"button.stats":         "📊 Статистика",
+	"button.feedback":      "⭐ Отзывы",
+	"button.new_order":     "🔄 Новый заказ",
+	"button.accept":        "✅ Принять",
+	"button.reject":        "❌ Отклонить",
+	"button.accept_order":  "✅ Принять заказ",
+	"button.reject_order":  "❌ Отклонить заказ",
+	"button.route":         "🗺️ Построить маршрут",
+	"button.route_short":   "🗺️ Маршрут",
+	"button.call":          "📞 Позвонить",
+	"button.show_phone":    "📞 Показать номер",
+	"button.contact":       "💬 Связаться с клиентом",
+	"button.contact_short": "💬 Клиент",
+	"button.contact_write": "✍️ Написать своё",
+	"button.complete":      "🏁 Доставка завершена",
+	"button.delivery_done": "✅ Доставка завершена",
+	"button.problem":       "🚨 Проблема с доставкой",
+	"button.problem_short": "🚨 Проблема",
+	"button.problems":      "Возникли проблемы",
+	"button.picked":        "🚗 Забрал заказ",
+	"button.on_the_way":    "🚚 В пути",
+	"button.im_here":       "📍 Я на месте",
+	"button.delivered":     "✅ Доставлено",
+	"button.cancel_order":  "❌ Отменить заказ",
+
+	"start.new": "Добро пожаловать, %s!\n\n" +
+		"Вы успешно зарегистрированы как курьер.\n",
+	"start.back": "С возвращением, %s!\n\n",
+	"start.intro": "Я - бот для курьеров доставки. Буду сопровождать вас в вашей работе.\n\n" +
+		"*Основные команды:*\n" +
+		"• 📋 Мои заказы - посмотреть активные заказы\n" +
+		"• ℹ️ Статус - информация о вашем статусе\n" +
+		"• ⚙️ Настройки - настройки уведомлений\n" +
+		"• 🆘 Помощь - справка по использованию\n\n",
+	"start.new_hint":  "Начните смену в настройках, чтобы получать заказы!",
+	"start.back_hint": "Ожидайте новые заказы!",
+
+	"help.text": "🆘 *Помощь по боту*\n\n" +
+		"*Как работает бот:*\n" +
+		"• 📦 Вы получаете уведомления о новых заказах\n" +
+		"• ✅ Можете принять или отклонить заказ\n" +
+		"• 🗺️ Использовать навигацию к адресу доставки\n" +
+		"• 📞 Связаться с клиентом\n" +
+		"• 🏁 Отмечать статусы доставки\n\n" +
+		"*Основные кнопки:*\n" +
+		"• ✅ Принять - взять заказ в работу\n" +
+		"• ❌ Отклонить - отказаться от заказа\n" +
+		"• 🗺️ Построить маршрут - открыть навигацию\n" +
+		"• 📞 Позвонить - связаться с клиентом\n" +
+		"• 🏁 Доставка завершена - отметить выполнение\n\n" +
+		"Если возникли проблемы, обратитесь к администратору.",
+
+	"unknown.command": "❓ Неизвестная команда\n\n" +
+		"Используйте кнопки меню или введите /help для справки.",
+
+	"location.live_started": "📍 Трансляция геопозиции включена",
+
+	"orders.load_failed":   "❌ Не удалось загрузить список заказов. Попробуйте позже.",
+	"orders.title.active":  "📋 *Ваши активные заказы*\n\n",
+	"orders.title.today":   "✅ *Доставлено сегодня*\n\n",
+	"orders.title.history": "🗂 *История заказов*\n\n",
+	"orders.empty.active": "На данный момент у вас нет активных заказов.\n\n" +
+		"💡 *Совет:* Убедитесь, что вы на смене.\n" +
+		"Новые заказы будут приходить автоматически!",
+	"orders.empty.today":   "Сегодня вы ещё не доставили ни одного заказа.",
+	"orders.empty.history": "Доставленных заказов пока нет.",
+	"orders.total":         "Всего заказов: %d\n\nВыберите заказ для просмотра деталей:",
+	"orders.tab.active":    "📋 Активные",
+	"orders.tab.today":     "✅ Сегодня",
+	"orders.tab.history":   "🗂 История",
+	"orders.tab_current":   "• %s",
+	"orders.page":          "🔄 %d/%d",
+	"orders.item_done":     "📦 #%d · %s · 💰 %d",
+	"orders.item":          "📦 Заказ #%d - %s",
+
+	"order_status.pending":     "⏳ Ожидает подтверждения",
+	"order_status.waiting":     "⏳ Ожидает ответа",
+	"order_status.accepted":    "✅ Принят в работу",
+	"order_status.in_delivery": "🚗 В доставке",
+	"order_status.delivered":   "✅ Доставлен",
+	"order_status.rejected":    "❌ Отклонен",
+	"order_status.expired":     "⏰ Время истекло",
+	"order_status.processing":  "📋 В обработке",
+
+	"time.not_set":    "⏰ Время не указано",
+	"time.overdue":    "🚨 СРОЧНО! Просрочен",
+	"time.in_minutes": "🚨 через %d мин",
+	"time.today":      "🕐 Сегодня в %s",
+	"time.tomorrow":   "📅 Завтра в %s",
+	"time.weekday":    "📅 %s в %s",
+	"time.date":       "📅 %s в %s",
+
+	"status.load_failed": "❌ Не удалось загрузить статус. Попробуйте позже.",
+	"status.ready":       "Вы готовы принимать новые заказы! 🚀",
+	"status.no_shift":    "Начните смену в настройках, чтобы получать заказы.",
+	"status.on_break":    "Вы на перерыве, новые заказы не приходят.",
+	"status.text": "ℹ️ *Ваш статус*\n\n" +
+		"• 📱 Статус: *%s*\n" +
+		"• 📦 Активных заказов: *%d*\n" +
+		"• 📊 Доставок сегодня: *%d*\n" +
+		"• 💰 Заработано сегодня: *%d*\n" +
+		"• ⭐ Рейтинг: *%s*\n\n" +
+		"%s",
+
+	"shift_status.off":   "Не на смене",
+	"shift_status.break": "На перерыве",
+	"shift_status.on":    "На смене",
+
+	"stats.load_failed":     "❌ Не удалось загрузить статистику. Попробуйте позже.",
+	"stats.period.today":    "сегодня",
+	"stats.period.week":     "эту неделю",
+	"stats.period.month":    "этот месяц",
+	"stats.button.today":    "Сегодня",
+	"stats.button.week":     "Неделя",
+	"stats.button.month":    "Месяц",
+	"stats.title":           "📊 *Статистика за %s*\n",
+	"stats.since":           "_с %s_\n\n",
+	"stats.deliveries#one":  "• 📦 Доставлено: *%d заказ*\n",
+	"stats.deliveries#few":  "• 📦 Доставлено: *%d заказа*\n",
+	"stats.deliveries#many": "• 📦 Доставлено: *%d заказов*\n",
+	"stats.earnings":        "• 💰 Заработано: *%d*\n",
+	"stats.acceptance":      "• ✅ Принято предложений: *%d из %d (%.0f%%)*\n",
+	"stats.acceptance_none": "• ✅ Принято предложений: *—*\n",
+	"stats.avg_time":        "• ⏱ Среднее время доставки: *%s*\n",
+	"stats.avg_time_none":   "• ⏱ Среднее время доставки: *—*\n",
+	"stats.on_time":         "• 🎯 Вовремя: *%.0f%%*\n",
+	"stats.on_time_none":    "• 🎯 Вовремя: *—*\n",
+	"stats.rating":          "• ⭐ Рейтинг: *%s*",
+	"rating.none":           "нет оценок",
+
+	"feedback.title":       "⭐ *Отзывы клиентов*\nРейтинг: *%s*, отзывов: *%d*\n\n",
+	"feedback.item":        "%s заказ #%d, %s\n",
+	"feedback.comment":     "_%s_\n",
+	"feedback.none":        "Отзывов пока нет. Они появятся, когда клиенты оценят доставку.",
+	"feedback.load_failed": "❌ Не удалось загрузить отзывы. Попробуйте позже.",
+
+	"settings.title": "⚙️ *Настройки*\n\n" +
+		"Выберите настройку для изменения:",
+	"settings.button.notifications": "🔔 Уведомления",
+	"settings.button.workmode":      "Режим работы",
+	"settings.button.schedule":      "📅 Расписание",
+	"settings.button.contacts":      "Контакты",
+	"settings.button.language":      "🌐 Язык",
+	"settings.button.maps":          "🗺️ Навигатор",
+	"settings.contacts":             "Контактная информация скоро появится.",
+
+	"notifications.title": "🔔 *Уведомления*\n\n" +
+		"• Тихий режим — все сообщения приходят без звука.\n" +
+		"• Предложения без звука — только вне смены: во время смены новые заказы всегда приходят со звуком.\n" +
+		"• Остальное можно отключить совсем. Назначение, отмена заказа и сообщения клиента приходят всегда.",
+	"notifications.setting.quiet":         "Тихий режим",
+	"notifications.setting.silent_offers": "Предложения без звука",
+	"notifications.setting.order_ready":   "Заказ собран",
+	"notifications.setting.reminders":     "Напоминания о расписании",
+	"notifications.setting.shift_summary": "Итоги смены",
+	"notifications.setting.daily_summary": "Итоги дня",
+	"notifications.load_failed":           "❌ Не удалось сохранить настройки уведомлений. Попробуйте позже.",
+
+	"daily_summary.title":  "🌙 *Итоги дня, %s*\n\n",
+	"daily_summary.footer": "\n_Отключить итоги дня можно в настройках уведомлений._",
+
+	"workmode.current":           "⚙️ *Текущий статус: %s*",
+	"workmode.started_at":        "\n\nСмена начата в %s",
+	"workmode.button.start":      "▶️ Начать смену",
+	"workmode.button.resume":     "▶️ Вернуться с перерыва",
+	"workmode.button.break":      "☕ Перерыв",
+	"workmode.button.end":        "⏹ Закончить смену",
+	"workmode.shift_started":     "▶️ *Смена начата*\n\nТеперь ваш статус *\"активен\"*, ждите уведомлений о новых заказах",
+	"workmode.break_started":     "☕ *Перерыв*\n\nНовые заказы приходить не будут, пока вы не вернётесь с перерыва",
+	"workmode.break_ended":       "▶️ *С возвращением!*\n\nЖдите уведомлений о новых заказах",
+	"workmode.has_undelivered":   "❌ Нельзя закончить смену, пока у вас есть недоставленные заказы или предложения без ответа.\n\nОтветьте на предложения, а заказы завершите или отмените в разделе «Мои заказы».",
+	"workmode.already_open":      "ℹ️ Смена уже начата.",
+	"workmode.not_open":          "ℹ️ Смена не начата.",
+	"workmode.already_on_break":  "ℹ️ Вы уже на перерыве.",
+	"workmode.not_on_break":      "ℹ️ Вы не на перерыве.",
+	"next.title":                 "Что дальше?",
+	"navigation.no_address":      "❌ Не удалось получить адрес для навигации",
+	"call.no_phone":              "❌ Не удалось получить номер телефона",
+	"order.processing_error":     "❌ Ошибка обработки заказа",
+	"order.accepting":            "✅ Принимаем заказ...",
+	"order.accept_failed":        "❌ Не удалось принять заказ. Попробуйте позже.",
+	"order.reject_failed":        "❌ Не удалось отклонить заказ. Попробуйте позже.",
+	"order.cancel_failed":        "❌ Не удалось отменить заказ. Попробуйте позже.",
+	"order.details_failed":       "❌ Не удалось получить информацию о заказе.",
+	"order.back_failed":          "❌ Не удалось вернуться к заказу.",
+	"order.not_found":            "❌ Не удалось найти заказ.",
+	"order.not_yours":            "❌ Этот заказ не назначен вам.",
+	"order.already_cancelled":    "ℹ️ Заказ #%d уже отменён.",
+	"order.already_delivered":    "ℹ️ Заказ #%d уже доставлен.",
+	"order.cancelled_locked":     "🚫 Заказ #%d отменён, действия с ним недоступны.",
+	"delivery.confirm_error":     "❌ Ошибка подтверждения заказа.",
+	"delivery.update_failed":     "❌ Не удалось обновить статус заказа.",
+	"problem.no_answer":          "📞 Клиент не отвечает",
+	"problem.wrong_address":      "🏠 Неверный адрес",
+	"problem.payment":            "💳 Проблема с оплатой",
+	"problem.technical":          "🚗 Технические проблемы",
+	"problem.other":              "❔ Другое",
+	"cancel_reason.refused":      "🙅 Клиент отказался",
+	"cancel_reason.unreachable":  "📵 Клиент недоступен",
+	"cancel_reason.address":      "🏠 Неверный адрес",
+	"cancel_reason.other":        "❔ Другое",
+	"navigation.text":            "🗺️ *Маршрут к заказу #%d*\n\n*Адрес:* %s",
+	"call.text":                  "📞 *Телефон клиента заказа #%d*\n\n`%s`\n\nНомер доступен, пока заказ у вас в работе: после завершения это сообщение удалится.",
+	"order.completed":            "✅ *Заказ #%d завершен!*\n\nПоздравляем с успешной доставкой!",
+	"order.problem":              "🚨 *Проблема с заказом #%d*\n\nВыберите тип проблемы:",
+	"order.cancel":               "❌ *Отмена заказа #%d*\n\nУкажите причину отмены:",
+	"delivery.confirmed":         "🎉 *Заказ #%d доставлен!*\n\n✅ Доставка успешно завершена и подтверждена!\n\nСпасибо за вашу работу!",
+	"delivery.confirm_cancelled": "ℹ️ *Подтверждение доставки отменено*\n\nЗаказ #%d остается активным.\n\nВы можете завершить доставку позже или сообщить о проблеме.",
+
+	"cancel_reason.text.refused":     "Клиент отказался от заказа",
+	"cancel_reason.text.unreachable": "Клиент недоступен",
+	"cancel_reason.text.address":     "Неверный адрес",
+	"cancel_reason.text.other":       "Другое",
+
+	"maps.title":             "🗺️ *Навигатор*\n\nВ каком приложении открывать маршрут к клиенту?",
+	"maps.changed":           "✅ Приложение для маршрутов: %s",
+	"maps.change":            "⚙️ Выбрать приложение",
+	"navigation.choose_hint": "\n\n💡 Выберите приложение в настройках, и маршрут будет открываться одной кнопкой.",
+
+	"maps.app.yandex": "Яндекс Карты",
+	"maps.app.2gis":   "2ГИС",
+	"maps.app.google": "Google Maps",
+	"maps.app.apple":  "Apple Maps",
+
+	"maps.open.yandex": "🧭 Открыть в Яндекс Картах",
+	"maps.open.2gis":   "🧭 Открыть в 2ГИС",
+	"maps.open.google": "🧭 Открыть в Google Maps",
+	"maps.open.apple":  "🧭 Открыть в Apple Maps",
+
+	"order.details": "📋 *Детали заказа #%d*\n\n" +
+		"*Статус:* %s\n" +
+		"*Адрес:* %s %s\n" +
+		"*Клиент:* %s\n" +
+		"*Дата доставки:* %s\n\n" +
+		"Используйте кнопки ниже для управления доставкой:",
+	"order.picked": "📦 *Заказ #%d забран!*\n\n" +
+		"✅ Вы успешно забрали заказ у ресторана.\n\n" +
+		"*Информация о заказе:*\n" +
+		"• Адрес доставки: %s, %s\n" +
+		"• Клиент: %s\n\n" +
+		"🚗 Теперь можете начать доставку к клиенту.",
+	"contact.menu": "💬 *Связь с клиентом по заказу #%d*\n\n" +
+		"Сообщения уходят клиенту через бота, ваш номер он не увидит. " +
+		"Выберите готовую фразу или напишите своё сообщение.",
+	"contact.message.downstairs": "Курьер на месте и ждёт вас внизу.",
+	"contact.message.late":       "Курьер задерживается примерно на 10 минут, извините за ожидание.",
+	"contact.message.at_door":    "Курьер у вашей двери.",
+	"contact.message.no_answer":  "Курьер не может до вас дозвониться, пожалуйста, ответьте на звонок.",
+
+	"contact.template.downstairs": "🚪 Я внизу",
+	"contact.template.late":       "⏱️ Опаздываю на 10 минут",
+	"contact.template.at_door":    "🔔 Я у двери",
+	"contact.template.no_answer":  "📵 Не могу дозвониться",
+	"contact.compose_prompt":      "✍️ Напишите одним сообщением, что передать клиенту заказа #%d.",
+	"contact.compose_cancelled":   "Сообщение клиенту не отправлено.",
+	"contact.text_only":           "Клиенту можно передать только текст. Напишите сообщение или нажмите «Отмена».",
+	"contact.sent":                "✅ Передано клиенту:\n\n%s",
+	"contact.send_failed":         "❌ Не удалось передать сообщение клиенту. Попробуйте позже.",
+	"contact.closed":              "ℹ️ Заказ #%d закрыт, связаться с клиентом через бота уже нельзя.",
+	"contact.incoming":            "💬 *Сообщение от клиента по заказу #%d:*\n\n%s",
+
+	"order.delivering": "🚗 *Заказ #%d в пути!*\n\n" +
+		"📍 Вы направляетесь к клиенту.\n\n" +
+		"*Рекомендации:*\n" +
+		"• 🗺️ Используйте навигацию для оптимального маршрута\n" +
+		"• 📞 Свяжитесь с клиентом за 10-15 минут до прибытия\n" +
+		"• ⏱️ Учитывайте текущую дорожную ситуацию\n\n" +
+		"Ориентировочное время прибытия: *15-20 минут*",
+	"order.arrived": "📍 *Вы на месте!*\n\n" +
+		"Заказ #%d готов к передаче клиенту.\n\n" +
+		"*Действия:*\n" +
+		"1. 💬 Свяжитесь с клиентом для встречи\n" +
+		"2. ✅ Передайте заказ\n" +
+		"3. 💰 Примите оплату (если необходимо)\n" +
+		"4. 🏁 Подтвердите доставку\n\n" +
+		"Клиент: %s",
+	"order.confirm_delivery": "🏁 *Подтверждение доставки*\n\n" +
+		"Заказ #%d готов к отметке как доставленный.\n\n" +
+		"*Пожалуйста, подтвердите:*\n" +
+		"✅ Заказ передан клиенту\n" +
+		"✅ Оплата получена (если требуется)\n" +
+		"После подтверждения заказ будет завершен.",
+
+	"schedule.title":                 "📅 *Ваше расписание*\n\n",
+	"schedule.no_windows":            "Недельных окон пока нет.\n",
+	"schedule.window":                "• %s %s\n",
+	"schedule.exceptions":            "\n*Исключения:*\n",
+	"schedule.exception_hours":       "• %s: %s–%s\n",
+	"schedule.exception_off":         "• %s: выходной\n",
+	"schedule.footer":                "\nВ начале окна я напомню начать смену. Нажмите на запись, чтобы удалить её.",
+	"schedule.new_window":            "📅 *Новое окно*\n\nВыберите день недели:",
+	"schedule.new_exception":         "📅 *Исключение*\n\nВыберите дату:",
+	"schedule.exception_type":        "Что изменить в этот день?",
+	"schedule.from_hour":             "С какого часа?",
+	"schedule.to_hour":               "До какого часа?",
+	"schedule.window_added":          "✅ Окно добавлено",
+	"schedule.window_removed":        "🗑 Окно удалено",
+	"schedule.day_off_added":         "✅ Выходной добавлен",
+	"schedule.exception_added":       "✅ Исключение добавлено",
+	"schedule.exception_removed":     "🗑 Исключение удалено",
+	"schedule.invalid_time":          "❌ Некорректное время. Попробуйте ещё раз.",
+	"schedule.already_removed":       "ℹ️ Эта запись уже удалена.",
+	"schedule.load_failed":           "❌ Не удалось загрузить расписание. Попробуйте позже.",
+	"schedule.button.add_window":     "➕ Окно",
+	"schedule.button.add_exception":  "➕ Исключение",
+	"schedule.button.delete_window":  "🗑 %s %s",
+	"schedule.button.delete_day_off": "🗑 %s выходной",
+	"schedule.button.delete_hours":   "🗑 %s %s–%s",
+	"schedule.button.day_off":        "🚫 Выходной",
+	"schedule.button.other_hours":    "🕐 Другие часы",
+
+	"offer.title":         "Новый заказ!",
+	"offer.deadline#one":  "⏰ *У вас %d минута, чтобы принять решение*\n\n",
+	"offer.deadline#few":  "⏰ *У вас %d минуты, чтобы принять решение*\n\n",
+	"offer.deadline#many": "⏰ *У вас %d минут, чтобы принять решение*\n\n",
+	"offer.prompt":        "Примите или отклоните заказ:",
+	"offer.stale":         "ℹ️ Предложение по заказу #%d больше не актуально",
+	"offer.expired":       "⏰ Время для принятия заказа истекло",
+	"offer.accepted":      "✅ Заказ #%d принят! Ожидайте детали доставки.",
+	"offer.rejected":      "❌ Вы отказались от заказа #%d.",
+
+	"delivery.title":                 "Доставка заказа #%d",
+	"delivery.controls":              "*Используйте кнопки ниже для управления доставкой*",
+	"delivery.date":                  "%s в %s",
+	"delivery.date_not_set":          "не указано",
+	"delivery.card.address":          "*Адрес доставки:*\n%s, %s\n",
+	"delivery.card.flat":             "*Квартира:*\n%s\n",
+	"delivery.card.entrance":         "*Подъезд:*\n%s\n",
+	"delivery.card.date":             "*Дата доставки:*\n%s\n",
+	"delivery.card.customer":         "*Клиент:*\n%s\n",
+	"delivery.card.total":            "*Сумма заказа:*\n%d\n\n",
+	"delivery.card.delivery_price":   "*Стоимость доставки:*\n%d\n\n",
+	"delivery.card.no_entrance":      "*Подъезд не указан, для уточнения информации свяжитесь с клиентом\n\n*",
+	"delivery.card.no_flat":          "*Квартира не указана, для уточнения информации свяжитесь с клиентом\n\n*",
+	"delivery.card.no_flat_entrance": "*Квартира и подъезд не указаны, для уточнения информации свяжитесь с клиентом\n\n*",
+
+	"order.changed":              "✏️ Заказ #%d изменён",
+	"order.assembled":            "📦 Заказ #%d собран и готов к выдаче.",
+	"order_change.address":       "адрес доставки",
+	"order_change.delivery_date": "время доставки",
+	"order_change.contacts":      "контакты клиента",
+	"cancel.notice":              "🚫 *Заказ #%d отменён %s*",
+	"cancel.reason":              "\n\n*Причина:* %s",
+	"cancel.thanks":              "✅ Заказ #%d отменён. Спасибо, что сообщили причину.",
+	"cancel.offer_revoked":       "🚫 Предложение по заказу #%d отозвано: заказ отменён.",
+	"cancel.source.shop":         "магазином",
+	"cancel.source.dispatcher":   "диспетчером",
+	"cancel.source.courier":      "курьером",
+	"manual.offer_revoked":       "ℹ️ Предложение по заказу #%d отозвано диспетчером.",
+	"manual.assigned":            "📌 Диспетчер назначил вам заказ #%d",
+	"manual.unassigned":          "ℹ️ Заказ #%d снят с вас диспетчером.",
+	"shift.end.courier":          "Смена завершена",
+	"shift.end.inactivity":       "Смена завершена автоматически из-за неактивности",
+	"shift.end.admin":            "Смена завершена администратором",
+	"shift.summary.title":        "🏁 *%s*\n\n",
+	"shift.summary.started":      "• 🕐 Начало: *%s*\n",
+	"shift.summary.worked":       "• ⏱ Отработано: *%s*\n",
+	"shift.summary.breaks#one":   "• ☕ Перерывы: *%d перерыв, %s*\n",
+	"shift.summary.breaks#few":   "• ☕ Перерывы: *%d перерыва, %s*\n",
+	"shift.summary.breaks#many":  "• ☕ Перерывы: *%d перерывов, %s*\n",
+	"shift.summary.deliveries":   "• 📦 Доставлено: *%d*\n",
+	"shift.summary.earnings":     "• 💰 Заработано: *%d*",
+	"shift.summary.restart":      "\n\nНачните смену в настройках, когда будете готовы принимать заказы.",
+	"availability.reminder":      "⏰ *По расписанию у вас смена %s–%s*\n\nНачните смену в настройках, чтобы получать заказы.",
+}
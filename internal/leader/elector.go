@@ -0,0 +1,153 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Task func(ctx context.Context)
+
+type namedTask struct {
+	name string
+	run  Task
+}
+
+// Elector держит session-level advisory lock в Postgres. Пока лок у этого
+// инстанса, он выполняет singleton-задачи; при потере соединения задачи
+// останавливаются, и лок забирает другой инстанс.
+type Elector struct {
+	db       *sql.DB
+	lockID   int64
+	interval time.Duration
+	log      *slog.Logger
+	tasks    []namedTask
+	isLeader atomic.Bool
+}
+
+func NewElector(db *sql.DB, lockID int64, interval time.Duration, log *slog.Logger) *Elector {
+	return &Elector{
+		db:       db,
+		lockID:   lockID,
+		interval: interval,
+		log:      log,
+	}
+}
+
+func (e *Elector) Register(name string, task Task) {
+	e.tasks = append(e.tasks, namedTask{name: name, run: task})
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+func (e *Elector) Run(ctx context.Context) {
+	e.log.Info("Starting leader election", "lockID", e.lockID)
+
+	for {
+		conn, acquired, err := e.tryAcquire(ctx)
+		if err != nil {
+			e.log.Error("Leader election attempt failed", "error", err)
+		}
+
+		if acquired {
+			e.lead(ctx, conn)
+		}
+
+		select {
+		case <-ctx.Done():
+			e.log.Info("Leader election stopped")
+			return
+		case <-time.After(e.interval):
+		}
+	}
+}
+
+func (e *Elector) tryAcquire(ctx context.Context) (*sql.Conn, bool, error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for leader lock: %v", err)
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, e.lockID).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to try advisory lock: %v", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return conn, true, nil
+}
+
+func (e *Elector) lead(ctx context.Context, conn *sql.Conn) {
+	e.isLeader.Store(true)
+	e.log.Info("Became leader", "lockID", e.lockID)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	for _, task := range e.tasks {
+		wg.Add(1)
+		go func(t namedTask) {
+			defer wg.Done()
+			e.log.Info("Starting leader task", "task", t.name)
+			t.run(leaderCtx)
+			e.log.Info("Leader task stopped", "task", t.name)
+		}(task)
+	}
+
+	e.holdLock(leaderCtx, conn)
+
+	cancel()
+	wg.Wait()
+
+	e.release(conn)
+	e.isLeader.Store(false)
+	e.log.Info("Stepped down from leadership", "lockID", e.lockID)
+}
+
+func (e *Elector) holdLock(ctx context.Context, conn *sql.Conn) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, e.interval)
+			err := conn.PingContext(pingCtx)
+			cancel()
+
+			if err != nil && ctx.Err() == nil {
+				e.log.Error("Lost connection holding leader lock", "error", err)
+				return
+			}
+		}
+	}
+}
+
+func (e *Elector) release(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, e.lockID); err != nil {
+		e.log.Warn("Failed to release leader lock, discarding the session", "error", err)
+		conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	if err := conn.Close(); err != nil {
+		e.log.Warn("Failed to close leader lock connection", "error", err)
+	}
+}
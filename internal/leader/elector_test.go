@@ -0,0 +1,151 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+)
+
+const testInterval = 50 * time.Millisecond
+
+// tasks считает запуски singleton-задачи по всем инстансам.
+type tasks struct {
+	running    atomic.Int32
+	maxRunning atomic.Int32
+	started    atomic.Int32
+}
+
+type instance struct {
+	elector *Elector
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// startInstance поднимает «инстанс приложения»: свой пул соединений и свой
+// Elector с общим lockID.
+func startInstance(t *testing.T, lockID int64, counters *tasks) *instance {
+	t.Helper()
+
+	elector := NewElector(dbtest.Open(t), lockID, testInterval, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	elector.Register("singleton", func(ctx context.Context) {
+		counters.started.Add(1)
+		current := counters.running.Add(1)
+		for {
+			previous := counters.maxRunning.Load()
+			if current <= previous || counters.maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		<-ctx.Done()
+		counters.running.Add(-1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	inst := &instance{elector: elector, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(inst.done)
+		elector.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-inst.done
+	})
+
+	return inst
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(testInterval / 5)
+	}
+}
+
+func TestTwoInstancesShareOneDatabase(t *testing.T) {
+	lockID := dbtest.Unique()
+
+	var counters tasks
+
+	first := startInstance(t, lockID, &counters)
+	second := startInstance(t, lockID, &counters)
+
+	waitFor(t, "a leader", func() bool {
+		return first.elector.IsLeader() || second.elector.IsLeader()
+	})
+
+	// Несколько циклов выборов: второй инстанс должен оставаться ведомым.
+	time.Sleep(5 * testInterval)
+
+	if first.elector.IsLeader() && second.elector.IsLeader() {
+		t.Fatal("both instances are leaders")
+	}
+
+	if got := counters.running.Load(); got != 1 {
+		t.Fatalf("singleton task runs on %d instances, want 1", got)
+	}
+
+	leader, follower := first, second
+	if second.elector.IsLeader() {
+		leader, follower = second, first
+	}
+
+	// Лидер останавливается — лок и задачи переходят ко второму инстансу.
+	leader.cancel()
+	<-leader.done
+
+	waitFor(t, "failover", follower.elector.IsLeader)
+	waitFor(t, "singleton task on the new leader", func() bool { return counters.running.Load() == 1 })
+
+	if got := counters.maxRunning.Load(); got != 1 {
+		t.Fatalf("singleton task ran on %d instances at once, want 1", got)
+	}
+}
+
+func TestFailoverWhenLeaderConnectionDies(t *testing.T) {
+	lockID := dbtest.Unique()
+
+	var counters tasks
+
+	first := startInstance(t, lockID, &counters)
+	second := startInstance(t, lockID, &counters)
+
+	waitFor(t, "a leader", func() bool {
+		return first.elector.IsLeader() || second.elector.IsLeader()
+	})
+
+	// Обрываем сессию, держащую лок, как при падении инстанса или сети.
+	admin := dbtest.Open(t)
+	_, err := admin.Exec(`
+		SELECT pg_terminate_backend(pid)
+		FROM pg_locks
+		WHERE locktype = 'advisory'
+			AND granted
+			AND ((classid::BIGINT << 32) | objid::BIGINT) = $1
+	`, lockID)
+	if err != nil {
+		t.Fatalf("failed to terminate leader session: %v", err)
+	}
+
+	// Прежний лидер замечает обрыв на ближайшей проверке соединения и
+	// останавливает задачи; лидерство заново берёт один из инстансов.
+	waitFor(t, "leadership to be re-acquired", func() bool {
+		return counters.started.Load() >= 2 && counters.running.Load() == 1
+	})
+
+	if first.elector.IsLeader() && second.elector.IsLeader() {
+		t.Fatal("both instances are leaders")
+	}
+}
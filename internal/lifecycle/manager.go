@@ -0,0 +1,193 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Spawner запускает фоновую работу так, чтобы её дождались при остановке.
+type Spawner interface {
+	Go(ctx context.Context, name string, fn func(ctx context.Context))
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager владеет корневым контекстом приложения и останавливает его
+// в три шага: сначала прекращается приём новой работы (HTTP, polling,
+// воркеры), затем с дедлайном дожидаются начатые обработчики, и только
+// после этого закрываются ресурсы вроде соединений с базой.
+type Manager struct {
+	intakeCtx  context.Context
+	stopIntake context.CancelFunc
+	workCtx    context.Context
+	cancelWork context.CancelFunc
+
+	loops    sync.WaitGroup
+	inFlight atomic.Int64
+
+	mu          sync.Mutex
+	intakeHooks []hook
+	closeHooks  []hook
+
+	log *slog.Logger
+}
+
+func NewManager(log *slog.Logger) *Manager {
+	intakeCtx, stopIntake := context.WithCancel(context.Background())
+	workCtx, cancelWork := context.WithCancel(context.Background())
+
+	return &Manager{
+		intakeCtx:  intakeCtx,
+		stopIntake: stopIntake,
+		workCtx:    workCtx,
+		cancelWork: cancelWork,
+		log:        log,
+	}
+}
+
+// Context отменяется, как только начинается остановка; его получают
+// циклы, которые принимают новую работу.
+func (m *Manager) Context() context.Context {
+	return m.intakeCtx
+}
+
+// Run запускает долгоживущий цикл приёма работы на корневом контексте.
+func (m *Manager) Run(name string, fn func(ctx context.Context)) {
+	m.loops.Add(1)
+
+	go func() {
+		defer m.loops.Done()
+		fn(m.intakeCtx)
+		m.log.Info("Intake loop stopped", "loop", name)
+	}()
+}
+
+// Go запускает обработчик, который переживает отмену ctx (например, остановку
+// polling-цикла), но сохраняет его значения — трассу и т.п. Такой обработчик
+// отменяется только когда истёк дедлайн на дренаж.
+func (m *Manager) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	m.inFlight.Add(1)
+
+	workCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.workCtx, cancel)
+
+	go func() {
+		defer m.inFlight.Add(-1)
+		defer cancel()
+		defer stop()
+		defer func() {
+			if r := recover(); r != nil {
+				m.log.Error("Background task panicked", "task", name, "panic", r, "stack", string(debug.Stack()))
+			}
+		}()
+
+		fn(workCtx)
+	}()
+}
+
+func (m *Manager) InFlight() int64 {
+	return m.inFlight.Load()
+}
+
+// OnStopIntake регистрирует шаг остановки приёма работы, например
+// http.Server.Shutdown. Шаги выполняются в порядке регистрации.
+func (m *Manager) OnStopIntake(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.intakeHooks = append(m.intakeHooks, hook{name: name, fn: fn})
+}
+
+// OnClose регистрирует освобождение ресурса. Ресурсы закрываются в обратном
+// порядке, после того как вся работа завершена.
+func (m *Manager) OnClose(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closeHooks = append(m.closeHooks, hook{name: name, fn: fn})
+}
+
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	intakeHooks := m.intakeHooks
+	closeHooks := m.closeHooks
+	m.mu.Unlock()
+
+	var firstErr error
+	record := func(step string, err error) {
+		if err == nil {
+			return
+		}
+		m.log.Error("Shutdown step failed", "step", step, "error", err)
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s: %v", step, err)
+		}
+	}
+
+	m.log.Info("Stopping intake")
+	for _, h := range intakeHooks {
+		record(h.name, h.fn(ctx))
+	}
+
+	m.stopIntake()
+	record("intake loops", waitGroup(ctx, &m.loops))
+
+	m.log.Info("Draining in-flight work", "inFlight", m.InFlight())
+	if err := m.waitInFlight(ctx); err != nil {
+		m.log.Warn("Drain deadline exceeded, cancelling in-flight work", "inFlight", m.InFlight())
+		m.cancelWork()
+
+		graceCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		record("in-flight work", m.waitInFlight(graceCtx))
+		cancel()
+	}
+	m.cancelWork()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := len(closeHooks) - 1; i >= 0; i-- {
+		m.log.Info("Closing resource", "resource", closeHooks[i].name)
+		record(closeHooks[i].name, closeHooks[i].fn(closeCtx))
+	}
+
+	return firstErr
+}
+
+func (m *Manager) waitInFlight(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for m.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d tasks still running: %v", m.inFlight.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "courier_bot"
+
+var (
+	WebhookRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_requests_total",
+		Help:      "Order webhooks received, by event type and result.",
+	}, []string{"event", "result"})
+
+	OffersSent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "offers_sent_total",
+		Help:      "Order offers delivered to couriers.",
+	})
+
+	NotificationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_failures_total",
+		Help:      "Courier notifications that could not be sent, by kind.",
+	}, []string{"kind"})
+
+	CustomerNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "customer_notifications_total",
+		Help:      "Customer notifications, by channel and status: sent, failed, skipped.",
+	}, []string{"channel", "status"})
+
+	Reviews = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_total",
+		Help:      "Customer reviews received, by rating.",
+	}, []string{"rating"})
+
+	AssignmentOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "assignment_outcomes_total",
+		Help:      "Finished offers, by outcome: accepted, rejected, expired, cancelled.",
+	}, []string{"outcome"})
+
+	TimeToAccept = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "time_to_accept_seconds",
+		Help:      "Time from sending an offer to the courier accepting it.",
+		Buckets:   []float64{5, 15, 30, 60, 120, 300, 600, 900},
+	})
+
+	TimeToDeliver = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "time_to_deliver_seconds",
+		Help:      "Time from the accepted offer to the order being marked delivered.",
+		Buckets:   []float64{600, 1200, 1800, 2700, 3600, 5400, 7200, 10800, 21600},
+	})
+
+	TelegramRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "telegram_request_duration_seconds",
+		Help:      "Telegram Bot API request latency, by API method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	TelegramErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_errors_total",
+		Help:      "Failed Telegram Bot API requests, by API method.",
+	}, []string{"method"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Postgres query latency, by repository and SQL operation.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"repository", "operation"})
+
+	DBQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed Postgres queries, by repository and SQL operation.",
+	}, []string{"repository", "operation"})
+)
+
+// RegisterActiveCouriers регистрирует gauge, который при каждом scrape
+// спрашивает актуальное число активных курьеров у count.
+func RegisterActiveCouriers(count func() float64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_couriers",
+		Help:      "Couriers currently available for assignment.",
+	}, count)
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+	"path"
+	"time"
+)
+
+type telegramTransport struct {
+	next http.RoundTripper
+}
+
+// NewTelegramClient возвращает HTTP-клиент для tgbotapi, который меряет
+// задержку и ошибки каждого вызова Bot API. Метод берётся из последнего
+// сегмента пути, чтобы токен бота не попадал в метки.
+func NewTelegramClient() *http.Client {
+	return &http.Client{Transport: &telegramTransport{next: http.DefaultTransport}}
+}
+
+func (t *telegramTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+	started := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	TelegramRequestDuration.WithLabelValues(method).Observe(time.Since(started).Seconds())
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		TelegramErrors.WithLabelValues(method).Inc()
+	}
+
+	return resp, err
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type Scope string
+
+const (
+	ScopeRead          Scope = "read"
+	ScopeDispatcher    Scope = "dispatcher"
+	ScopeAdmin         Scope = "admin"
+	ScopeWebhookIngest Scope = "webhook-ingest"
+)
+
+// impliedScopes описывает иерархию: admin умеет всё, что dispatcher,
+// а dispatcher — всё, что read. webhook-ingest стоит отдельно.
+var impliedScopes = map[Scope][]Scope{
+	ScopeAdmin:      {ScopeDispatcher, ScopeRead},
+	ScopeDispatcher: {ScopeRead},
+}
+
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeRead, ScopeDispatcher, ScopeAdmin, ScopeWebhookIngest:
+		return true
+	default:
+		return false
+	}
+}
+
+type APIToken struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *APIToken) HasScope(required Scope) bool {
+	for _, scope := range t.Scopes {
+		if scope == required {
+			return true
+		}
+
+		for _, implied := range impliedScopes[scope] {
+			if implied == required {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type APIAuditEntry struct {
+	TokenID    *int
+	Method     string
+	Path       string
+	Status     int
+	RemoteAddr string
+	Duration   time.Duration
+}
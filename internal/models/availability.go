@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AvailabilityWindow — повторяющееся каждую неделю окно, когда курьер готов
+// работать. Время хранится в минутах от полуночи в часовом поясе сервиса.
+type AvailabilityWindow struct {
+	ID          int          `json:"id"`
+	CourierID   int          `json:"courier_id"`
+	Weekday     time.Weekday `json:"weekday"`
+	StartMinute int          `json:"start_minute"`
+	EndMinute   int          `json:"end_minute"`
+}
+
+func (w *AvailabilityWindow) Contains(minute int) bool {
+	return w.StartMinute <= minute && minute < w.EndMinute
+}
+
+func (w *AvailabilityWindow) String() string {
+	return FormatMinutes(w.StartMinute) + "–" + FormatMinutes(w.EndMinute)
+}
+
+// AvailabilityException меняет расписание на конкретную дату: выходной
+// (Available == false) или отдельное окно вместо недельных.
+type AvailabilityException struct {
+	ID          int       `json:"id"`
+	CourierID   int       `json:"courier_id"`
+	Day         time.Time `json:"day"`
+	Available   bool      `json:"available"`
+	StartMinute *int      `json:"start_minute,omitempty"`
+	EndMinute   *int      `json:"end_minute,omitempty"`
+}
+
+func (e *AvailabilityException) Contains(minute int) bool {
+	if !e.Available || e.StartMinute == nil || e.EndMinute == nil {
+		return false
+	}
+
+	return *e.StartMinute <= minute && minute < *e.EndMinute
+}
+
+type CourierAvailability struct {
+	Windows    []*AvailabilityWindow    `json:"windows"`
+	Exceptions []*AvailabilityException `json:"exceptions"`
+}
+
+// AvailableAt: если на дату t есть исключения, действуют только они,
+// иначе — недельные окна. t должно быть в часовом поясе расписания.
+func (a *CourierAvailability) AvailableAt(t time.Time) bool {
+	minute := MinuteOfDay(t)
+	day := t.Format(time.DateOnly)
+
+	overridden := false
+	for _, exception := range a.Exceptions {
+		if exception.Day.Format(time.DateOnly) != day {
+			continue
+		}
+
+		overridden = true
+		if exception.Contains(minute) {
+			return true
+		}
+	}
+
+	if overridden {
+		return false
+	}
+
+	for _, window := range a.Windows {
+		if window.Weekday == t.Weekday() && window.Contains(minute) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func MinuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func FormatMinutes(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// AvailabilityReminder — окно, которое уже началось, а курьер ещё не на
+// смене и не получал напоминания.
+type AvailabilityReminder struct {
+	WindowID    int
+	ExceptionID int
+	CourierID   int
+	ChatID      int64
+	StartMinute int
+	EndMinute   int
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type ContactDirection string
+
+const (
+	ContactToCustomer ContactDirection = "to_customer"
+	ContactToCourier  ContactDirection = "to_courier"
+)
+
+// ContactMessage — сообщение между курьером и клиентом, переданное через
+// бота. Template заполнен, если курьер выбрал готовую фразу.
+type ContactMessage struct {
+	ID        int              `json:"id"`
+	OrderID   int              `json:"order_id"`
+	CourierID *int             `json:"courier_id"`
+	Direction ContactDirection `json:"direction"`
+	Template  string           `json:"template,omitempty"`
+	Text      string           `json:"text"`
+	Delivered bool             `json:"delivered"`
+	CreatedAt time.Time        `json:"created_at"`
+}
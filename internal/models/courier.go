@@ -12,5 +12,30 @@ type Courier struct {
 	LastSeen       time.Time `json:"last_seen"`
 	CurrentOrderID *int      `json:"current_order_id"`
 	Rating         float64   `json:"rating"`
+	Language       string    `json:"language"`
+	MapApp         string    `json:"map_app"`
 	CreatedAt      time.Time `json:"created_at"`
+
+	Presence CourierPresence `json:"presence,omitempty"`
+}
+
+type CourierPresence string
+
+const (
+	PresenceOnline  CourierPresence = "online"
+	PresenceIdle    CourierPresence = "idle"
+	PresenceOffline CourierPresence = "offline"
+)
+
+// PresenceAt: offline — курьер не на смене, idle — на смене, но не
+// появлялся дольше idleThreshold, online — в остальных случаях.
+func (c *Courier) PresenceAt(now time.Time, idleThreshold time.Duration) CourierPresence {
+	switch {
+	case !c.IsActive:
+		return PresenceOffline
+	case now.Sub(c.LastSeen) > idleThreshold:
+		return PresenceIdle
+	default:
+		return PresenceOnline
+	}
 }
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+type StatsPeriod string
+
+const (
+	StatsPeriodToday StatsPeriod = "today"
+	StatsPeriodWeek  StatsPeriod = "week"
+	StatsPeriodMonth StatsPeriod = "month"
+)
+
+func (p StatsPeriod) IsValid() bool {
+	switch p {
+	case StatsPeriodToday, StatsPeriodWeek, StatsPeriodMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// Since возвращает начало периода: полночь сегодняшнего дня, понедельник
+// текущей недели или первое число месяца.
+func (p StatsPeriod) Since(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch p {
+	case StatsPeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case StatsPeriodMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	default:
+		return day
+	}
+}
+
+type CourierStats struct {
+	Period          StatsPeriod   `json:"period"`
+	Since           time.Time     `json:"since"`
+	Deliveries      int           `json:"deliveries"`
+	Earnings        int           `json:"earnings"`
+	Offers          int           `json:"offers"`
+	Accepted        int           `json:"accepted"`
+	AvgDeliveryTime time.Duration `json:"avg_delivery_time"`
+	WithDeadline    int           `json:"with_deadline"`
+	OnTime          int           `json:"on_time"`
+	Rating          float64       `json:"rating"`
+}
+
+func (s *CourierStats) AcceptanceRate() float64 {
+	if s.Offers == 0 {
+		return 0
+	}
+
+	return float64(s.Accepted) / float64(s.Offers) * 100
+}
+
+func (s *CourierStats) OnTimeRate() float64 {
+	if s.WithDeadline == 0 {
+		return 0
+	}
+
+	return float64(s.OnTime) / float64(s.WithDeadline) * 100
+}
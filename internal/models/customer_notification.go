@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CustomerEvent — момент доставки, о котором сообщают клиенту.
+type CustomerEvent string
+
+const (
+	CustomerEventAccepted    CustomerEvent = "accepted"
+	CustomerEventPickedUp    CustomerEvent = "picked_up"
+	CustomerEventApproaching CustomerEvent = "approaching"
+	CustomerEventDelivered   CustomerEvent = "delivered"
+)
+
+var CustomerEvents = []CustomerEvent{
+	CustomerEventAccepted,
+	CustomerEventPickedUp,
+	CustomerEventApproaching,
+	CustomerEventDelivered,
+}
+
+func (e CustomerEvent) IsValid() bool {
+	for _, event := range CustomerEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+type CustomerNotificationStatus string
+
+const (
+	CustomerNotificationPending CustomerNotificationStatus = "pending"
+	CustomerNotificationSent    CustomerNotificationStatus = "sent"
+	CustomerNotificationFailed  CustomerNotificationStatus = "failed"
+
+	// CustomerNotificationSkipped — у клиента нет адреса для этого канала.
+	CustomerNotificationSkipped CustomerNotificationStatus = "skipped"
+)
+
+// CustomerNotification — отправка уведомления клиенту по одному каналу.
+// На заказ приходится не больше одной записи на событие и канал.
+type CustomerNotification struct {
+	ID        int                        `json:"id"`
+	OrderID   int                        `json:"order_id"`
+	Event     CustomerEvent              `json:"event"`
+	Channel   string                     `json:"channel"`
+	Status    CustomerNotificationStatus `json:"status"`
+	Text      string                     `json:"text"`
+	Error     sql.NullString             `json:"error"`
+	CreatedAt time.Time                  `json:"created_at"`
+	SentAt    *time.Time                 `json:"sent_at"`
+}
+
+// CustomerContact — адреса клиента помимо телефона из заказа. Магазин
+// присылает их в webhook заказа.
+type CustomerContact struct {
+	OrderID        int    `json:"order_id"`
+	Email          string `json:"email"`
+	TelegramChatID *int64 `json:"telegram_chat_id"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type CourierFilter struct {
+	IsActive *bool
+	Query    string
+	Page
+}
+
+type AssignmentFilter struct {
+	Status    CourierResponseStatus
+	CourierID int
+	OrderID   int
+	From      *time.Time
+	To        *time.Time
+	Page
+}
+
+// CourierOrderTab — вкладка списка заказов курьера в боте.
+type CourierOrderTab string
+
+const (
+	CourierOrdersActive  CourierOrderTab = "active"
+	CourierOrdersToday   CourierOrderTab = "today"
+	CourierOrdersHistory CourierOrderTab = "history"
+)
+
+func (t CourierOrderTab) IsValid() bool {
+	switch t {
+	case CourierOrdersActive, CourierOrdersToday, CourierOrdersHistory:
+		return true
+	default:
+		return false
+	}
+}
+
+// CourierOrderFilter: Since — начало текущего дня, граница между вкладками
+// today и history.
+type CourierOrderFilter struct {
+	CourierID int
+	Tab       CourierOrderTab
+	Since     time.Time
+	Page
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type InboxStatus string
+
+const (
+	InboxStatusPending InboxStatus = "pending"
+	InboxStatusDone    InboxStatus = "done"
+	InboxStatusFailed  InboxStatus = "failed"
+)
+
+const (
+	EventOrderCreated   = "order.created"
+	EventOrderCancelled = "order.cancelled"
+	EventOrderUpdated   = "order.updated"
+	EventOrderAssembled = "order.assembled"
+)
+
+func IsKnownOrderEvent(eventType string) bool {
+	switch eventType {
+	case EventOrderCreated, EventOrderCancelled, EventOrderUpdated, EventOrderAssembled:
+		return true
+	default:
+		return false
+	}
+}
+
+type InboxEvent struct {
+	ID             int             `json:"id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	EventType      string          `json:"event_type"`
+	OrderID        int             `json:"order_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         InboxStatus     `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      sql.NullString  `json:"last_error"`
+	ReceivedAt     time.Time       `json:"received_at"`
+	ProcessedAt    *time.Time      `json:"processed_at"`
+	TraceParent    string          `json:"-"`
+}
@@ -0,0 +1,123 @@
+package models
+
+// NotificationKind — вид сообщения курьеру. От него зависит, можно ли
+// сообщение отключить и отправить без звука.
+type NotificationKind string
+
+const (
+	NotifyOffer NotificationKind = "offer"
+	// NotifyOrder — ответы на действия курьера и изменения его заказов:
+	// назначение, отмена, сообщения клиента. Отключить их нельзя.
+	NotifyOrder        NotificationKind = "order"
+	NotifyOrderReady   NotificationKind = "order_ready"
+	NotifyReminder     NotificationKind = "reminders"
+	NotifyShiftSummary NotificationKind = "shift_summary"
+	NotifyDailySummary NotificationKind = "daily_summary"
+)
+
+// NotificationSetting — переключатель на экране настроек уведомлений.
+type NotificationSetting string
+
+const (
+	SettingQuietMode    NotificationSetting = "quiet"
+	SettingSilentOffers NotificationSetting = "silent_offers"
+	SettingOrderReady   NotificationSetting = "order_ready"
+	SettingReminders    NotificationSetting = "reminders"
+	SettingShiftSummary NotificationSetting = "shift_summary"
+	SettingDailySummary NotificationSetting = "daily_summary"
+)
+
+// NotificationSettingsOrder — порядок переключателей на экране настроек.
+var NotificationSettingsOrder = []NotificationSetting{
+	SettingQuietMode,
+	SettingSilentOffers,
+	SettingOrderReady,
+	SettingReminders,
+	SettingShiftSummary,
+	SettingDailySummary,
+}
+
+// NotificationSettings: QuietMode отправляет всё без звука, SilentOffers —
+// только предложения заказов. Во время смены предложения приходят со
+// звуком при любых настройках.
+type NotificationSettings struct {
+	CourierID    int  `json:"courier_id"`
+	QuietMode    bool `json:"quiet_mode"`
+	SilentOffers bool `json:"silent_offers"`
+	OrderReady   bool `json:"order_ready"`
+	Reminders    bool `json:"reminders"`
+	ShiftSummary bool `json:"shift_summary"`
+	DailySummary bool `json:"daily_summary"`
+}
+
+func DefaultNotificationSettings(courierID int) *NotificationSettings {
+	return &NotificationSettings{
+		CourierID:    courierID,
+		OrderReady:   true,
+		Reminders:    true,
+		ShiftSummary: true,
+	}
+}
+
+// Receives сообщает, нужно ли вообще отправлять сообщение этого вида.
+func (s *NotificationSettings) Receives(kind NotificationKind) bool {
+	switch kind {
+	case NotifyOrderReady:
+		return s.OrderReady
+	case NotifyReminder:
+		return s.Reminders
+	case NotifyShiftSummary:
+		return s.ShiftSummary
+	case NotifyDailySummary:
+		return s.DailySummary
+	default:
+		return true
+	}
+}
+
+// Silent сообщает, отправлять ли сообщение без звука.
+func (s *NotificationSettings) Silent(kind NotificationKind, onShift bool) bool {
+	if kind == NotifyOffer {
+		return !onShift && (s.QuietMode || s.SilentOffers)
+	}
+
+	return s.QuietMode
+}
+
+func (s *NotificationSettings) Enabled(setting NotificationSetting) bool {
+	if field := s.field(setting); field != nil {
+		return *field
+	}
+
+	return false
+}
+
+// Toggle переключает настройку. false — настройка неизвестна.
+func (s *NotificationSettings) Toggle(setting NotificationSetting) bool {
+	field := s.field(setting)
+	if field == nil {
+		return false
+	}
+
+	*field = !*field
+	return true
+}
+
+func (s *NotificationSettings) field(setting NotificationSetting) *bool {
+	switch setting {
+	case SettingQuietMode:
+		return &s.QuietMode
+	case SettingSilentOffers:
+		return &s.SilentOffers
+	case SettingOrderReady:
+		return &s.OrderReady
+	case SettingReminders:
+		return &s.Reminders
+	case SettingShiftSummary:
+		return &s.ShiftSummary
+	case SettingDailySummary:
+		return &s.DailySummary
+	default:
+		return nil
+	}
+}
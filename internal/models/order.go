@@ -30,4 +30,31 @@ type Order struct {
 	IsReceived             bool           `json:"is_received"`
 	PaymentUrl             sql.NullString `json:"payment_url"`
 	CourierID              *int           `json:"courier_id"`
+	CancelledAt            *time.Time     `json:"cancelled_at"`
+	CancelledBy            sql.NullString `json:"cancelled_by"`
+	CancelReason           sql.NullString `json:"cancel_reason"`
+}
+
+type CancelSource string
+
+const (
+	CancelSourceShop       CancelSource = "shop"
+	CancelSourceDispatcher CancelSource = "dispatcher"
+	CancelSourceCourier    CancelSource = "courier"
+)
+
+func (o *Order) IsCancelled() bool {
+	return o.CancelledAt != nil
+}
+
+// FullAddress — город и адрес одной строкой, для карт и сообщений.
+func (o *Order) FullAddress() string {
+	switch {
+	case o.City == "":
+		return o.Address
+	case o.Address == "":
+		return o.City
+	default:
+		return o.City + ", " + o.Address
+	}
 }
@@ -5,15 +5,16 @@ import "time"
 type CourierResponseStatus string
 
 const (
-	ResponseStatusWaiting  CourierResponseStatus = "waiting"
-	ResponseStatusAccepted CourierResponseStatus = "accepted"
-	ResponseStatusRejected CourierResponseStatus = "rejected"
-	ResponsseStatusExpired CourierResponseStatus = "expired"
+	ResponseStatusWaiting   CourierResponseStatus = "waiting"
+	ResponseStatusAccepted  CourierResponseStatus = "accepted"
+	ResponseStatusRejected  CourierResponseStatus = "rejected"
+	ResponsseStatusExpired  CourierResponseStatus = "expired"
+	ResponseStatusCancelled CourierResponseStatus = "cancelled"
 )
 
 func (s CourierResponseStatus) IsValid() bool {
 	switch s {
-	case ResponseStatusWaiting, ResponseStatusAccepted, ResponseStatusRejected, ResponsseStatusExpired:
+	case ResponseStatusWaiting, ResponseStatusAccepted, ResponseStatusRejected, ResponsseStatusExpired, ResponseStatusCancelled:
 		return true
 	default:
 		return false
@@ -32,3 +33,10 @@ type OrderAssignment struct {
 	ExpiredAt             time.Time             `json:"expired_at"`
 	CourierResponseStatus CourierResponseStatus `json:"courier_response_status"`
 }
+
+// AssignmentHold — заказ на будущее время, который ждёт, пока курьер с окном
+// на время доставки выйдет на смену.
+type AssignmentHold struct {
+	OrderID   int       `json:"order_id"`
+	ReleaseAt time.Time `json:"release_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type OrderMessageKind string
+
+const (
+	OrderMessageOffer OrderMessageKind = "offer"
+
+	// OrderMessageDelivery — карточка заказа у курьера. Она одна на заказ и
+	// редактируется по мере доставки.
+	OrderMessageDelivery OrderMessageKind = "delivery"
+
+	// OrderMessageContact — сообщение с телефоном клиента. Его удаляют из
+	// чата, как только заказ перестаёт быть активным.
+	OrderMessageContact OrderMessageKind = "contact"
+)
+
+type OrderMessage struct {
+	ID        int              `json:"id"`
+	OrderID   int              `json:"order_id"`
+	ChatID    int64            `json:"chat_id"`
+	MessageID int              `json:"message_id"`
+	Kind      OrderMessageKind `json:"kind"`
+	CreatedAt time.Time        `json:"created_at"`
+}
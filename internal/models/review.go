@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+
+	MaxReviewComment = 1000
+)
+
+// Review — оценка клиента за доставку заказа. Скрытый отзыв не виден
+// курьеру и не входит в его рейтинг.
+type Review struct {
+	ID        int        `json:"id"`
+	OrderID   int        `json:"order_id"`
+	CourierID *int       `json:"courier_id"`
+	Rating    int        `json:"rating"`
+	Comment   string     `json:"comment"`
+	Hidden    bool       `json:"hidden"`
+	HiddenAt  *time.Time `json:"hidden_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ReviewFilter struct {
+	CourierID     int
+	IncludeHidden bool
+	Page
+}
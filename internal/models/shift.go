@@ -0,0 +1,50 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ShiftEndReason string
+
+const (
+	ShiftEndByCourier    ShiftEndReason = "courier"
+	ShiftEndByInactivity ShiftEndReason = "inactivity"
+	ShiftEndByAdmin      ShiftEndReason = "admin"
+)
+
+type Shift struct {
+	ID        int            `json:"id"`
+	CourierID int            `json:"courier_id"`
+	StartedAt time.Time      `json:"started_at"`
+	EndedAt   *time.Time     `json:"ended_at"`
+	EndReason sql.NullString `json:"end_reason"`
+}
+
+func (s *Shift) IsOpen() bool {
+	return s.EndedAt == nil
+}
+
+type ShiftBreak struct {
+	ID        int        `json:"id"`
+	ShiftID   int        `json:"shift_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+func (b *ShiftBreak) Duration(now time.Time) time.Duration {
+	if b.EndedAt != nil {
+		return b.EndedAt.Sub(b.StartedAt)
+	}
+
+	return now.Sub(b.StartedAt)
+}
+
+type ShiftSummary struct {
+	Shift      *Shift        `json:"shift"`
+	Breaks     int           `json:"breaks"`
+	OnBreak    time.Duration `json:"on_break"`
+	Worked     time.Duration `json:"worked"`
+	Deliveries int           `json:"deliveries"`
+	Earnings   int           `json:"earnings"`
+}
@@ -0,0 +1,54 @@
+// Package navigation строит ссылки, которые открывают маршрут до адреса в
+// картографическом приложении курьера. Ссылки https: Telegram принимает в
+// URL-кнопках только их, а приложения перехватывают такие ссылки сами.
+package navigation
+
+import (
+	"net/url"
+	"slices"
+)
+
+type App string
+
+const (
+	Yandex App = "yandex"
+	TwoGIS App = "2gis"
+	Google App = "google"
+	Apple  App = "apple"
+)
+
+// Apps — порядок приложений в настройках и в кнопках маршрута.
+var Apps = []App{Yandex, TwoGIS, Google, Apple}
+
+// Parse возвращает приложение по сохранённому значению. Пустая строка —
+// курьер ещё не выбрал приложение.
+func Parse(value string) (App, bool) {
+	app := App(value)
+	return app, slices.Contains(Apps, app)
+}
+
+// RouteURL возвращает ссылку на маршрут от текущего положения до address.
+func RouteURL(app App, address string) string {
+	switch app {
+	case TwoGIS:
+		// 2ГИС строит маршрут только по координатам, по адресу открывается
+		// карточка места с кнопкой «Проехать».
+		return "https://2gis.ru/search/" + url.PathEscape(address)
+	case Google:
+		return "https://www.google.com/maps/dir/?" + url.Values{
+			"api":         {"1"},
+			"destination": {address},
+			"travelmode":  {"driving"},
+		}.Encode()
+	case Apple:
+		return "https://maps.apple.com/?" + url.Values{
+			"daddr":  {address},
+			"dirflg": {"d"},
+		}.Encode()
+	default:
+		return "https://yandex.ru/maps/?" + url.Values{
+			"rtext": {"~" + address},
+			"rtt":   {"auto"},
+		}.Encode()
+	}
+}
@@ -0,0 +1,71 @@
+package navigation
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRouteURL(t *testing.T) {
+	const address = "Москва, ул. Тверская 7/2, кв. 5 & 6"
+
+	tests := []struct {
+		app  App
+		want string
+		// destination достаёт адрес обратно из ссылки.
+		destination func(u *url.URL) string
+	}{
+		{
+			app:  Yandex,
+			want: "https://yandex.ru/maps/?rtext=~%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0%2C+%D1%83%D0%BB.+%D0%A2%D0%B2%D0%B5%D1%80%D1%81%D0%BA%D0%B0%D1%8F+7%2F2%2C+%D0%BA%D0%B2.+5+%26+6&rtt=auto",
+			destination: func(u *url.URL) string {
+				return strings.TrimPrefix(u.Query().Get("rtext"), "~")
+			},
+		},
+		{
+			app:  TwoGIS,
+			want: "https://2gis.ru/search/%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0%2C%20%D1%83%D0%BB.%20%D0%A2%D0%B2%D0%B5%D1%80%D1%81%D0%BA%D0%B0%D1%8F%207%2F2%2C%20%D0%BA%D0%B2.%205%20&%206",
+			destination: func(u *url.URL) string {
+				segment, err := url.PathUnescape(strings.TrimPrefix(u.EscapedPath(), "/search/"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return segment
+			},
+		},
+		{
+			app:  Google,
+			want: "https://www.google.com/maps/dir/?api=1&destination=%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0%2C+%D1%83%D0%BB.+%D0%A2%D0%B2%D0%B5%D1%80%D1%81%D0%BA%D0%B0%D1%8F+7%2F2%2C+%D0%BA%D0%B2.+5+%26+6&travelmode=driving",
+			destination: func(u *url.URL) string {
+				return u.Query().Get("destination")
+			},
+		},
+		{
+			app:  Apple,
+			want: "https://maps.apple.com/?daddr=%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0%2C+%D1%83%D0%BB.+%D0%A2%D0%B2%D0%B5%D1%80%D1%81%D0%BA%D0%B0%D1%8F+7%2F2%2C+%D0%BA%D0%B2.+5+%26+6&dirflg=d",
+			destination: func(u *url.URL) string {
+				return u.Query().Get("daddr")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.app), func(t *testing.T) {
+			got := RouteURL(tt.app, address)
+			if got != tt.want {
+				t.Fatalf("RouteURL(%s) = %q, want %q", tt.app, got, tt.want)
+			}
+
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("RouteURL(%s) is not a valid URL: %v", tt.app, err)
+			}
+			if u.Scheme != "https" {
+				t.Fatalf("RouteURL(%s) scheme = %q, want https", tt.app, u.Scheme)
+			}
+			if destination := tt.destination(u); destination != address {
+				t.Fatalf("RouteURL(%s) destination = %q, want %q", tt.app, destination, address)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type APIToken interface {
+	Create(ctx context.Context, token *models.APIToken) error
+	GetByHash(ctx context.Context, hash string) (*models.APIToken, error)
+	List(ctx context.Context) ([]*models.APIToken, error)
+	Revoke(ctx context.Context, id int) error
+	TouchLastUsed(ctx context.Context, id int) error
+	RecordAudit(ctx context.Context, entry *models.APIAuditEntry) error
+}
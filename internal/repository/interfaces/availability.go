@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type Availability interface {
+	CreateWindow(ctx context.Context, window *models.AvailabilityWindow) error
+	DeleteWindow(ctx context.Context, courierID, id int) error
+	ListWindows(ctx context.Context, courierID int) ([]*models.AvailabilityWindow, error)
+	CreateException(ctx context.Context, exception *models.AvailabilityException) error
+	DeleteException(ctx context.Context, courierID, id int) error
+	ListExceptions(ctx context.Context, courierID int, from time.Time) ([]*models.AvailabilityException, error)
+	ListAvailableCouriers(ctx context.Context, at time.Time) ([]*models.Courier, error)
+	ListDueReminders(ctx context.Context, at time.Time, lookback time.Duration) ([]*models.AvailabilityReminder, error)
+	MarkReminded(ctx context.Context, reminder *models.AvailabilityReminder, at time.Time) (bool, error)
+}
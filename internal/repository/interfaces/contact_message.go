@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type ContactMessage interface {
+	Create(ctx context.Context, message *models.ContactMessage) error
+	ListByOrderID(ctx context.Context, orderID int) ([]*models.ContactMessage, error)
+}
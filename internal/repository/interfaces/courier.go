@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/CAATHARSIS/courier-bot/internal/models"
 )
@@ -12,9 +13,15 @@ type CourierRepository interface {
 	Update(ctx context.Context, couier *models.Courier) (*models.Courier, error)
 	DeleteByID(ctx context.Context, id int) error
 	List(ctx context.Context) ([]*models.Courier, error)
-	GetActiveCouriers(ctx context.Context) ([]*models.Courier, error)
+	GetActiveCouriers(ctx context.Context, seenSince time.Time) ([]*models.Courier, error)
 	GetByChatID(ctx context.Context, chatID int64) (*models.Courier, error)
 	CheckCourierByChatID(ctx context.Context, chatID int64) bool
-	UpdateCourierStatusIsActive(ctx context.Context, chatID int64, currStatus bool) error
+	TouchLastSeen(ctx context.Context, chatID int64) error
+	SetLanguage(ctx context.Context, chatID int64, language string) error
+	SetMapApp(ctx context.Context, chatID int64, app string) error
+	SetRating(ctx context.Context, id int, rating float64) error
 	UpdateCurrentOrderID(ctx context.Context, chatID int64, orderID int) error
+	ClearCurrentOrder(ctx context.Context, orderID int) error
+	ListFiltered(ctx context.Context, filter models.CourierFilter) ([]*models.Courier, int, error)
+	SetActive(ctx context.Context, id int, active bool) error
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type CourierStats interface {
+	GetByCourierID(ctx context.Context, courierID int, since time.Time) (*models.CourierStats, error)
+}
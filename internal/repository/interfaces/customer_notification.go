@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type CustomerNotification interface {
+	// Claim создаёт запись в статусе pending. false означает, что
+	// уведомление по этому событию и каналу уже отправлялось.
+	Claim(ctx context.Context, notification *models.CustomerNotification) (bool, error)
+	Finish(ctx context.Context, id int, status models.CustomerNotificationStatus, errorText string) error
+	ListByOrderID(ctx context.Context, orderID int) ([]*models.CustomerNotification, error)
+}
+
+type CustomerContact interface {
+	Save(ctx context.Context, contact *models.CustomerContact) error
+	GetByOrderID(ctx context.Context, orderID int) (*models.CustomerContact, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrConflict — запись есть, но условное изменение не прошло из-за её
+	// состояния.
+	ErrConflict = errors.New("conflict")
+)
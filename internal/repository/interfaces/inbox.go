@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type Inbox interface {
+	Insert(ctx context.Context, event *models.InboxEvent) (bool, error)
+	GetByKey(ctx context.Context, key string) (*models.InboxEvent, error)
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*models.InboxEvent, error)
+	MarkDone(ctx context.Context, id int) error
+	MarkRetry(ctx context.Context, id int, lastError string, retryAfter time.Duration) error
+	MarkFailed(ctx context.Context, id int, lastError string) error
+}
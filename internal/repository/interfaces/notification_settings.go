@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type NotificationSettings interface {
+	// GetByChatID возвращает настройки по умолчанию, если курьер их не менял.
+	GetByChatID(ctx context.Context, chatID int64) (*models.NotificationSettings, error)
+	Save(ctx context.Context, settings *models.NotificationSettings) error
+	// ListDailySummaryDue возвращает курьеров, включивших итоги дня, которым
+	// итоги за day ещё не отправлены. У курьеров заполнены ID, ChatID и Language.
+	ListDailySummaryDue(ctx context.Context, day time.Time) ([]*models.Courier, error)
+	MarkDailySummarySent(ctx context.Context, courierID int, day time.Time) (bool, error)
+}
@@ -8,7 +8,10 @@ import (
 
 type Order interface {
 	GetByID(ctx context.Context, id int) (*models.Order, error)
-	UpdateCourierID(ctx context.Context, id int, courierID int) error
+	UpdateCourierID(ctx context.Context, id int, courierID int) (bool, error)
 	GetActiveOrdersByCourier(ctx context.Context, courierID int) ([]models.Order, error)
+	ListByCourier(ctx context.Context, filter models.CourierOrderFilter) ([]models.Order, int, error)
 	UpdateStatusReceived(ctx context.Context, id int, received bool) error
+	Cancel(ctx context.Context, id int, source models.CancelSource, reason string) ([]*models.OrderAssignment, bool, error)
+	ClearCourierID(ctx context.Context, id int) error
 }
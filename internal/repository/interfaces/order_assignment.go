@@ -2,17 +2,26 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/CAATHARSIS/courier-bot/internal/models"
 )
 
 type OrderAssignment interface {
 	Create(ctx context.Context, orderAssignment *models.OrderAssignment) error
+	CreateOffer(ctx context.Context, orderAssignment *models.OrderAssignment) (bool, error)
 	GetByID(ctx context.Context, id int) (*models.OrderAssignment, error)
 	Update(ctx context.Context, orderAssignment *models.OrderAssignment) (*models.OrderAssignment, error)
 	DeleteByID(ctx context.Context, id int) error
 	List(ctx context.Context) ([]*models.OrderAssignment, error)
 	GetRejectedCouriers(ctx context.Context, id int) ([]int, error)
 	GetByOrderID(ctx context.Context, orderID int) (*models.OrderAssignment, error)
-	UpdateStatus(ctx context.Context, id int, status models.CourierResponseStatus) error
+	ListExpiredWaiting(ctx context.Context, now time.Time) ([]*models.OrderAssignment, error)
+	ExpireWaiting(ctx context.Context, id int) (bool, error)
+	ResolveWaiting(ctx context.Context, id int, status models.CourierResponseStatus) (bool, error)
+	CancelLive(ctx context.Context, orderID int) ([]*models.OrderAssignment, error)
+	ListFiltered(ctx context.Context, filter models.AssignmentFilter) ([]*models.OrderAssignment, int, error)
+	Hold(ctx context.Context, orderID int, releaseAt time.Time) error
+	ListHolds(ctx context.Context) ([]*models.AssignmentHold, error)
+	ReleaseHold(ctx context.Context, orderID int) (bool, error)
 }
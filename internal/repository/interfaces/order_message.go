@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type OrderMessage interface {
+	Save(ctx context.Context, message *models.OrderMessage) error
+	Get(ctx context.Context, orderID int, chatID int64, kind models.OrderMessageKind) (*models.OrderMessage, error)
+	ListByOrderID(ctx context.Context, orderID int) ([]*models.OrderMessage, error)
+	Delete(ctx context.Context, id int) error
+	DeleteByOrderID(ctx context.Context, orderID int) error
+}
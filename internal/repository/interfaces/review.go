@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type Review interface {
+	// Create сохраняет отзыв. false означает, что заказ уже оценён.
+	Create(ctx context.Context, review *models.Review) (bool, error)
+	GetByID(ctx context.Context, id int) (*models.Review, error)
+	GetByOrderID(ctx context.Context, orderID int) (*models.Review, error)
+	SetHidden(ctx context.Context, id int, hidden bool) (*models.Review, error)
+	// ListFiltered с нулевым Limit возвращает все отзывы курьера.
+	ListFiltered(ctx context.Context, filter models.ReviewFilter) ([]*models.Review, int, error)
+}
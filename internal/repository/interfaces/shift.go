@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+type Shift interface {
+	Start(ctx context.Context, courierID int) (*models.Shift, error)
+	GetOpen(ctx context.Context, courierID int) (*models.Shift, error)
+	EndWithoutOrders(ctx context.Context, id int, reason models.ShiftEndReason) (*models.Shift, error)
+	StartBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error)
+	EndBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error)
+	GetOpenBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error)
+	ListBreaks(ctx context.Context, shiftID int) ([]*models.ShiftBreak, error)
+	ListIdle(ctx context.Context, lastSeenBefore time.Time) ([]*models.Shift, error)
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookNonce interface {
+	Remember(ctx context.Context, keyID, nonce string, expiresAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
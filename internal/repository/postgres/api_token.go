@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/lib/pq"
+)
+
+type apiTokenRepository struct {
+	db *instrumentedDB
+}
+
+func NewAPITokenRepository(db *sql.DB) interfaces.APIToken {
+	return &apiTokenRepository{db: instrument(db, "api_token")}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	query := `
+		INSERT INTO
+			api_tokens (name, token_hash, scopes)
+		VALUES
+			($1, $2, $3)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		token.Name,
+		token.TokenHash,
+		scopesToArray(token.Scopes),
+	).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %v", err)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) GetByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			token_hash,
+			scopes,
+			created_at,
+			last_used_at,
+			revoked_at
+		FROM
+			api_tokens
+		WHERE
+			token_hash = $1
+	`
+
+	var token models.APIToken
+	var scopes pq.StringArray
+
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&token.ID,
+		&token.Name,
+		&token.TokenHash,
+		&scopes,
+		&token.CreatedAt,
+		&token.LastUsedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api token %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get api token: %v", err)
+	}
+
+	token.Scopes = arrayToScopes(scopes)
+
+	return &token, nil
+}
+
+func (r *apiTokenRepository) List(ctx context.Context) ([]*models.APIToken, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			token_hash,
+			scopes,
+			created_at,
+			last_used_at,
+			revoked_at
+		FROM
+			api_tokens
+		ORDER BY
+			id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %v", err)
+	}
+	defer rows.Close()
+
+	var tokens []*models.APIToken
+
+	for rows.Next() {
+		var token models.APIToken
+		var scopes pq.StringArray
+
+		err := rows.Scan(
+			&token.ID,
+			&token.Name,
+			&token.TokenHash,
+			&scopes,
+			&token.CreatedAt,
+			&token.LastUsedAt,
+			&token.RevokedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %v", err)
+		}
+
+		token.Scopes = arrayToScopes(scopes)
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, id int) error {
+	query := `
+		UPDATE api_tokens
+		SET
+			revoked_at = NOW()
+		WHERE
+			id = $1
+			AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token (id %d): %v", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("active api token %d %w", id, interfaces.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id int) error {
+	query := `
+		UPDATE api_tokens
+		SET
+			last_used_at = NOW()
+		WHERE
+			id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api token (id %d) last use: %v", id, err)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) RecordAudit(ctx context.Context, entry *models.APIAuditEntry) error {
+	query := `
+		INSERT INTO
+			api_audit_log (
+				token_id,
+				method,
+				path,
+				status,
+				remote_addr,
+				duration_ms
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		entry.TokenID,
+		entry.Method,
+		entry.Path,
+		entry.Status,
+		entry.RemoteAddr,
+		entry.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record api audit entry: %v", err)
+	}
+
+	return nil
+}
+
+func scopesToArray(scopes []models.Scope) pq.StringArray {
+	result := make(pq.StringArray, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+
+	return result
+}
+
+func arrayToScopes(values pq.StringArray) []models.Scope {
+	result := make([]models.Scope, 0, len(values))
+	for _, value := range values {
+		result = append(result, models.Scope(value))
+	}
+
+	return result
+}
@@ -0,0 +1,463 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+// Время в методах репозитория должно быть уже переведено в часовой пояс
+// расписания: день недели, дата и минуты берутся из него как есть.
+type availabilityRepository struct {
+	db *instrumentedDB
+}
+
+func NewAvailabilityRepository(db *sql.DB) interfaces.Availability {
+	return &availabilityRepository{db: instrument(db, "availability")}
+}
+
+func (r *availabilityRepository) CreateWindow(ctx context.Context, window *models.AvailabilityWindow) error {
+	query := `
+		INSERT INTO
+			courier_availability_windows (
+				courier_id,
+				weekday,
+				start_minute,
+				end_minute
+			)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING
+			id
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		window.CourierID,
+		int(window.Weekday),
+		window.StartMinute,
+		window.EndMinute,
+	).Scan(&window.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create availability window: %v", err)
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) DeleteWindow(ctx context.Context, courierID, id int) error {
+	query := `
+		DELETE FROM
+			courier_availability_windows
+		WHERE
+			id = $1
+			AND courier_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, courierID)
+	if err != nil {
+		return fmt.Errorf("failed to delete availability window: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("availability window %w", interfaces.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) ListWindows(ctx context.Context, courierID int) ([]*models.AvailabilityWindow, error) {
+	query := `
+		SELECT
+			id,
+			courier_id,
+			weekday,
+			start_minute,
+			end_minute
+		FROM
+			courier_availability_windows
+		WHERE
+			courier_id = $1
+		ORDER BY
+			(weekday + 6) % 7,
+			start_minute
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability windows: %v", err)
+	}
+	defer rows.Close()
+
+	var windows []*models.AvailabilityWindow
+	for rows.Next() {
+		var window models.AvailabilityWindow
+		var weekday int
+
+		err := rows.Scan(
+			&window.ID,
+			&window.CourierID,
+			&weekday,
+			&window.StartMinute,
+			&window.EndMinute,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan availability window: %v", err)
+		}
+
+		window.Weekday = time.Weekday(weekday)
+		windows = append(windows, &window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return windows, nil
+}
+
+func (r *availabilityRepository) CreateException(ctx context.Context, exception *models.AvailabilityException) error {
+	query := `
+		INSERT INTO
+			courier_availability_exceptions (
+				courier_id,
+				day,
+				available,
+				start_minute,
+				end_minute
+			)
+		VALUES
+			($1, $2::date, $3, $4, $5)
+		RETURNING
+			id
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		exception.CourierID,
+		exception.Day.Format(time.DateOnly),
+		exception.Available,
+		exception.StartMinute,
+		exception.EndMinute,
+	).Scan(&exception.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create availability exception: %v", err)
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) DeleteException(ctx context.Context, courierID, id int) error {
+	query := `
+		DELETE FROM
+			courier_availability_exceptions
+		WHERE
+			id = $1
+			AND courier_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, courierID)
+	if err != nil {
+		return fmt.Errorf("failed to delete availability exception: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("availability exception %w", interfaces.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) ListExceptions(ctx context.Context, courierID int, from time.Time) ([]*models.AvailabilityException, error) {
+	query := `
+		SELECT
+			id,
+			courier_id,
+			day,
+			available,
+			start_minute,
+			end_minute
+		FROM
+			courier_availability_exceptions
+		WHERE
+			courier_id = $1
+			AND day >= $2::date
+		ORDER BY
+			day,
+			start_minute
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, courierID, from.Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability exceptions: %v", err)
+	}
+	defer rows.Close()
+
+	var exceptions []*models.AvailabilityException
+	for rows.Next() {
+		var exception models.AvailabilityException
+
+		err := rows.Scan(
+			&exception.ID,
+			&exception.CourierID,
+			&exception.Day,
+			&exception.Available,
+			&exception.StartMinute,
+			&exception.EndMinute,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan availability exception: %v", err)
+		}
+
+		exceptions = append(exceptions, &exception)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return exceptions, nil
+}
+
+func (r *availabilityRepository) ListAvailableCouriers(ctx context.Context, at time.Time) ([]*models.Courier, error) {
+	query := `
+		SELECT
+			c.id,
+			c.telegram_id,
+			c.chat_id,
+			c.name,
+			c.phone,
+			c.is_active,
+			c.last_seen,
+			c.current_order_id,
+			c.rating,
+			c.language,
+			c.map_app,
+			c.created_at
+		FROM
+			couriers c
+		WHERE
+			CASE
+				WHEN EXISTS (
+					SELECT
+						1
+					FROM
+						courier_availability_exceptions e
+					WHERE
+						e.courier_id = c.id
+						AND e.day = $2::date
+				) THEN EXISTS (
+					SELECT
+						1
+					FROM
+						courier_availability_exceptions e
+					WHERE
+						e.courier_id = c.id
+						AND e.day = $2::date
+						AND e.available
+						AND e.start_minute <= $3
+						AND e.end_minute > $3
+				)
+				ELSE EXISTS (
+					SELECT
+						1
+					FROM
+						courier_availability_windows w
+					WHERE
+						w.courier_id = c.id
+						AND w.weekday = $1
+						AND w.start_minute <= $3
+						AND w.end_minute > $3
+				)
+			END
+		ORDER BY
+			c.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, int(at.Weekday()), at.Format(time.DateOnly), models.MinuteOfDay(at))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list available couriers: %v", err)
+	}
+	defer rows.Close()
+
+	var couriers []*models.Courier
+	for rows.Next() {
+		var courier models.Courier
+
+		err := rows.Scan(
+			&courier.ID,
+			&courier.TelegramID,
+			&courier.ChatID,
+			&courier.Name,
+			&courier.Phone,
+			&courier.IsActive,
+			&courier.LastSeen,
+			&courier.CurrentOrderID,
+			&courier.Rating,
+			&courier.Language,
+			&courier.MapApp,
+			&courier.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan courier: %v", err)
+		}
+
+		couriers = append(couriers, &courier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return couriers, nil
+}
+
+func (r *availabilityRepository) ListDueReminders(ctx context.Context, at time.Time, lookback time.Duration) ([]*models.AvailabilityReminder, error) {
+	query := `
+		SELECT
+			w.id AS window_id,
+			0 AS exception_id,
+			c.id,
+			c.chat_id,
+			w.start_minute,
+			w.end_minute
+		FROM
+			courier_availability_windows w
+			JOIN couriers c ON c.id = w.courier_id
+		WHERE
+			w.weekday = $1
+			AND w.start_minute <= $3
+			AND w.start_minute > $4
+			AND (w.reminded_on IS NULL OR w.reminded_on < $2::date)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					courier_availability_exceptions e
+				WHERE
+					e.courier_id = c.id
+					AND e.day = $2::date
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					courier_shifts s
+				WHERE
+					s.courier_id = c.id
+					AND s.ended_at IS NULL
+			)
+		UNION ALL
+		SELECT
+			0 AS window_id,
+			e.id AS exception_id,
+			c.id,
+			c.chat_id,
+			e.start_minute,
+			e.end_minute
+		FROM
+			courier_availability_exceptions e
+			JOIN couriers c ON c.id = e.courier_id
+		WHERE
+			e.day = $2::date
+			AND e.available
+			AND NOT e.reminded
+			AND e.start_minute <= $3
+			AND e.start_minute > $4
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					courier_shifts s
+				WHERE
+					s.courier_id = c.id
+					AND s.ended_at IS NULL
+			)
+	`
+
+	minute := models.MinuteOfDay(at)
+	lookbackMinute := minute - int(lookback/time.Minute)
+
+	rows, err := r.db.QueryContext(ctx, query, int(at.Weekday()), at.Format(time.DateOnly), minute, lookbackMinute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due availability reminders: %v", err)
+	}
+	defer rows.Close()
+
+	var reminders []*models.AvailabilityReminder
+	for rows.Next() {
+		var reminder models.AvailabilityReminder
+
+		err := rows.Scan(
+			&reminder.WindowID,
+			&reminder.ExceptionID,
+			&reminder.CourierID,
+			&reminder.ChatID,
+			&reminder.StartMinute,
+			&reminder.EndMinute,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan availability reminder: %v", err)
+		}
+
+		reminders = append(reminders, &reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return reminders, nil
+}
+
+func (r *availabilityRepository) MarkReminded(ctx context.Context, reminder *models.AvailabilityReminder, at time.Time) (bool, error) {
+	query := `
+		UPDATE courier_availability_windows
+		SET
+			reminded_on = $2::date
+		WHERE
+			id = $1
+			AND (reminded_on IS NULL OR reminded_on < $2::date)
+	`
+	args := []any{reminder.WindowID, at.Format(time.DateOnly)}
+
+	if reminder.ExceptionID != 0 {
+		query = `
+			UPDATE courier_availability_exceptions
+			SET
+				reminded = true
+			WHERE
+				id = $1
+				AND NOT reminded
+		`
+		args = []any{reminder.ExceptionID}
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark availability reminder: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type contactMessageRepository struct {
+	db *instrumentedDB
+}
+
+func NewContactMessageRepository(db *sql.DB) interfaces.ContactMessage {
+	return &contactMessageRepository{db: instrument(db, "contact_message")}
+}
+
+func (r *contactMessageRepository) Create(ctx context.Context, message *models.ContactMessage) error {
+	query := `
+		INSERT INTO
+			order_contact_messages (
+				order_id,
+				courier_id,
+				direction,
+				template,
+				text,
+				delivered
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		message.OrderID,
+		message.CourierID,
+		message.Direction,
+		message.Template,
+		message.Text,
+		message.Delivered,
+	).Scan(&message.ID, &message.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create contact message: %v", err)
+	}
+
+	return nil
+}
+
+func (r *contactMessageRepository) ListByOrderID(ctx context.Context, orderID int) ([]*models.ContactMessage, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			direction,
+			template,
+			text,
+			delivered,
+			created_at
+		FROM
+			order_contact_messages
+		WHERE
+			order_id = $1
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contact messages: %v", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.ContactMessage{}
+
+	for rows.Next() {
+		var message models.ContactMessage
+
+		err := rows.Scan(
+			&message.ID,
+			&message.OrderID,
+			&message.CourierID,
+			&message.Direction,
+			&message.Template,
+			&message.Text,
+			&message.Delivered,
+			&message.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contact message: %v", err)
+		}
+
+		messages = append(messages, &message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return messages, nil
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
@@ -12,11 +11,11 @@ import (
 )
 
 type courierRepository struct {
-	db *sql.DB
+	db *instrumentedDB
 }
 
 func NewCourierRepository(db *sql.DB) interfaces.CourierRepository {
-	return &courierRepository{db: db}
+	return &courierRepository{db: instrument(db, "courier")}
 }
 
 func (r *courierRepository) Create(ctx context.Context, courier *models.Courier) error {
@@ -31,10 +30,11 @@ func (r *courierRepository) Create(ctx context.Context, courier *models.Courier)
 				last_seen,
 				current_order_id,
 				rating,
+				language,
 				created_at
 			)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING
 			id
 	`
@@ -50,6 +50,7 @@ func (r *courierRepository) Create(ctx context.Context, courier *models.Courier)
 		courier.LastSeen,
 		courier.CurrentOrderID,
 		courier.Rating,
+		courier.Language,
 		time.Now(),
 	).Scan(&courier.ID)
 
@@ -71,6 +72,8 @@ func (r *courierRepository) GetByID(ctx context.Context, id int) (*models.Courie
 			last_seen,
 			current_order_id,
 			rating,
+			language,
+			map_app,
 			created_at
 		FROM
 			couriers
@@ -89,12 +92,14 @@ func (r *courierRepository) GetByID(ctx context.Context, id int) (*models.Courie
 		&courier.LastSeen,
 		&courier.CurrentOrderID,
 		&courier.Rating,
+		&courier.Language,
+		&courier.MapApp,
 		&courier.CreatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("courier not found")
+			return nil, fmt.Errorf("courier %w", interfaces.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get courier: %v", err)
 	}
@@ -127,6 +132,8 @@ func (r *courierRepository) Update(ctx context.Context, courier *models.Courier)
 			last_seen,
 			current_order,
 			rating,
+			language,
+			map_app,
 			created_at
 	`
 
@@ -183,6 +190,8 @@ func (r *courierRepository) Update(ctx context.Context, courier *models.Courier)
 		&updatedCourier.LastSeen,
 		&updatedCourier.CurrentOrderID,
 		&updatedCourier.Rating,
+		&updatedCourier.Language,
+		&updatedCourier.MapApp,
 		&updatedCourier.CreatedAt,
 	)
 
@@ -220,6 +229,8 @@ func (r *courierRepository) List(ctx context.Context) ([]*models.Courier, error)
 			last_seen,
 			current_order_id,
 			rating,
+			language,
+			map_app,
 			created_at
 		FROM
 			couriers
@@ -248,6 +259,8 @@ func (r *courierRepository) List(ctx context.Context) ([]*models.Courier, error)
 			&courier.LastSeen,
 			&courier.CurrentOrderID,
 			&courier.Rating,
+			&courier.Language,
+			&courier.MapApp,
 			&courier.CreatedAt,
 		)
 
@@ -265,7 +278,11 @@ func (r *courierRepository) List(ctx context.Context) ([]*models.Courier, error)
 	return couriers, nil
 }
 
-func (r *courierRepository) GetActiveCouriers(ctx context.Context) ([]*models.Courier, error) {
+// GetActiveCouriers возвращает курьеров на смене и не на перерыве, которые
+// появлялись в боте не раньше seenSince. Начало смены считается появлением:
+// курьер, которому смену открыл администратор, или новый курьер без
+// last_seen не выпадает из назначения сразу после старта.
+func (r *courierRepository) GetActiveCouriers(ctx context.Context, seenSince time.Time) ([]*models.Courier, error) {
 	query := `
 		SELECT
 			id,
@@ -277,14 +294,35 @@ func (r *courierRepository) GetActiveCouriers(ctx context.Context) ([]*models.Co
 			last_seen,
 			current_order_id,
 			rating,
+			language,
+			map_app,
 			created_at
 		FROM
-			couriers
+			couriers c
 		WHERE
-			is_active = true
+			c.is_active = true
+			AND EXISTS (
+				SELECT
+					1
+				FROM
+					courier_shifts s
+				WHERE
+					s.courier_id = c.id
+					AND s.ended_at IS NULL
+					AND GREATEST(c.last_seen, s.started_at) >= $1
+					AND NOT EXISTS (
+						SELECT
+							1
+						FROM
+							courier_shift_breaks b
+						WHERE
+							b.shift_id = s.id
+							AND b.ended_at IS NULL
+					)
+			)
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, seenSince)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list active couriers: %v", err)
 	}
@@ -305,6 +343,8 @@ func (r *courierRepository) GetActiveCouriers(ctx context.Context) ([]*models.Co
 			&activeCourier.LastSeen,
 			&activeCourier.CurrentOrderID,
 			&activeCourier.Rating,
+			&activeCourier.Language,
+			&activeCourier.MapApp,
 			&activeCourier.CreatedAt,
 		)
 		if err != nil {
@@ -333,6 +373,8 @@ func (r *courierRepository) GetByChatID(ctx context.Context, chatID int64) (*mod
 			last_seen,
 			current_order_id,
 			rating,
+			language,
+			map_app,
 			created_at
 		FROM
 			couriers
@@ -352,6 +394,8 @@ func (r *courierRepository) GetByChatID(ctx context.Context, chatID int64) (*mod
 		&courier.LastSeen,
 		&courier.CurrentOrderID,
 		&courier.Rating,
+		&courier.Language,
+		&courier.MapApp,
 		&courier.CreatedAt,
 	)
 	if err != nil {
@@ -378,18 +422,96 @@ func (r *courierRepository) CheckCourierByChatID(ctx context.Context, chatID int
 	return exists
 }
 
-func (r *courierRepository) UpdateCourierStatusIsActive(ctx context.Context, chatID int64, currStatus bool) error {
+func (r *courierRepository) TouchLastSeen(ctx context.Context, chatID int64) error {
 	query := `
 		UPDATE couriers
 		SET
-			is_active = $1
+			last_seen = NOW()
+		WHERE
+			chat_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to update courier last seen: %v", err)
+	}
+
+	return nil
+}
+
+func (r *courierRepository) SetLanguage(ctx context.Context, chatID int64, language string) error {
+	query := `
+		UPDATE couriers
+		SET
+			language = $1
 		WHERE
 			chat_id = $2
 	`
 
-	_, err := r.db.ExecContext(ctx, query, !currStatus, chatID)
+	result, err := r.db.ExecContext(ctx, query, language, chatID)
 	if err != nil {
-		return fmt.Errorf("Failed to update courier status (is_active): %v", err)
+		return fmt.Errorf("failed to update courier language: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("courier %w", interfaces.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *courierRepository) SetMapApp(ctx context.Context, chatID int64, app string) error {
+	query := `
+		UPDATE couriers
+		SET
+			map_app = $1
+		WHERE
+			chat_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, app, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to update courier map app: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("courier %w", interfaces.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *courierRepository) SetRating(ctx context.Context, id int, rating float64) error {
+	query := `
+		UPDATE couriers
+		SET
+			rating = $1
+		WHERE
+			id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, rating, id)
+	if err != nil {
+		return fmt.Errorf("failed to update courier rating: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("courier %w", interfaces.ErrNotFound)
 	}
 
 	return nil
@@ -411,3 +533,115 @@ func (r *courierRepository) UpdateCurrentOrderID(ctx context.Context, chatID int
 
 	return nil
 }
+
+func (r *courierRepository) ClearCurrentOrder(ctx context.Context, orderID int) error {
+	query := `
+		UPDATE couriers
+		SET
+			current_order_id = NULL
+		WHERE
+			current_order_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, orderID)
+	if err != nil {
+		return fmt.Errorf("Failed to clear current order ID (#%d): %v", orderID, err)
+	}
+
+	return nil
+}
+
+func (r *courierRepository) ListFiltered(ctx context.Context, filter models.CourierFilter) ([]*models.Courier, int, error) {
+	query := `
+		SELECT
+			id,
+			telegram_id,
+			chat_id,
+			name,
+			phone,
+			is_active,
+			last_seen,
+			current_order_id,
+			rating,
+			language,
+			map_app,
+			created_at,
+			COUNT(*) OVER () AS total
+		FROM
+			couriers
+		WHERE
+			($1::BOOLEAN IS NULL OR is_active = $1)
+			AND ($2 = '' OR name ILIKE '%' || $2 || '%' OR phone LIKE '%' || $2 || '%')
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $3
+		OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.IsActive, filter.Query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list couriers: %v", err)
+	}
+	defer rows.Close()
+
+	var couriers []*models.Courier
+	var total int
+
+	for rows.Next() {
+		var courier models.Courier
+
+		err := rows.Scan(
+			&courier.ID,
+			&courier.TelegramID,
+			&courier.ChatID,
+			&courier.Name,
+			&courier.Phone,
+			&courier.IsActive,
+			&courier.LastSeen,
+			&courier.CurrentOrderID,
+			&courier.Rating,
+			&courier.Language,
+			&courier.MapApp,
+			&courier.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan courier: %v", err)
+		}
+
+		couriers = append(couriers, &courier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return couriers, total, nil
+}
+
+func (r *courierRepository) SetActive(ctx context.Context, id int, active bool) error {
+	query := `
+		UPDATE couriers
+		SET
+			is_active = $1
+		WHERE
+			id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, active, id)
+	if err != nil {
+		return fmt.Errorf("failed to update courier (id %d) status: %v", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("courier %w", interfaces.ErrNotFound)
+	}
+
+	return nil
+}
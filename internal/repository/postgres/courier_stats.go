@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type courierStatsRepository struct {
+	db *instrumentedDB
+}
+
+func NewCourierStatsRepository(db *sql.DB) interfaces.CourierStats {
+	return &courierStatsRepository{db: instrument(db, "courier_stats")}
+}
+
+func (r *courierStatsRepository) GetByCourierID(ctx context.Context, courierID int, since time.Time) (*models.CourierStats, error) {
+	deliveriesQuery := `
+		SELECT
+			COUNT(*) AS deliveries,
+			COALESCE(SUM(o.delivery_price), 0) AS earnings,
+			COALESCE(EXTRACT(EPOCH FROM AVG(o.received_at - a.assigned_at)), 0) AS avg_delivery_seconds,
+			COUNT(o.delivery_date) AS with_deadline,
+			COUNT(*) FILTER (WHERE o.received_at <= o.delivery_date) AS on_time
+		FROM
+			orders o
+			LEFT JOIN LATERAL (
+				SELECT
+					assigned_at
+				FROM
+					order_assignments
+				WHERE
+					order_id = o.id
+					AND courier_id = o.courier_id
+					AND courier_response_status = 'accepted'
+				ORDER BY
+					assigned_at DESC
+				LIMIT 1
+			) a ON TRUE
+		WHERE
+			o.courier_id = $1
+			AND o.is_received
+			AND o.received_at >= $2
+			AND o.cancelled_at IS NULL
+	`
+
+	stats := &models.CourierStats{Since: since}
+	var avgSeconds float64
+
+	err := r.db.QueryRowContext(ctx, deliveriesQuery, courierID, since).Scan(
+		&stats.Deliveries,
+		&stats.Earnings,
+		&avgSeconds,
+		&stats.WithDeadline,
+		&stats.OnTime,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery stats: %v", err)
+	}
+
+	stats.AvgDeliveryTime = time.Duration(avgSeconds * float64(time.Second))
+
+	offersQuery := `
+		SELECT
+			COUNT(*) AS offers,
+			COUNT(*) FILTER (WHERE courier_response_status = 'accepted') AS accepted
+		FROM
+			order_assignments
+		WHERE
+			courier_id = $1
+			AND assigned_at >= $2
+			AND courier_response_status IN ('accepted', 'rejected', 'expired')
+	`
+
+	err = r.db.QueryRowContext(ctx, offersQuery, courierID, since).Scan(
+		&stats.Offers,
+		&stats.Accepted,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer stats: %v", err)
+	}
+
+	return stats, nil
+}
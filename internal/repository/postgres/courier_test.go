@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+)
+
+func TestGetActiveCouriersIdleCutoff(t *testing.T) {
+	db := dbtest.Open(t)
+	couriers := NewCourierRepository(db)
+	shifts := NewShiftRepository(db)
+
+	ctx := context.Background()
+
+	active := func(courierID int) bool {
+		t.Helper()
+
+		list, err := couriers.GetActiveCouriers(ctx, time.Now().Add(-15*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, courier := range list {
+			if courier.ID == courierID {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// Курьер, который давно не появлялся в боте, но смену которому только
+	// что открыли, остаётся в назначении.
+	freshID, _ := dbtest.InsertCourier(t, db)
+	if _, err := db.Exec(`UPDATE couriers SET last_seen = NOW() - INTERVAL '1 day' WHERE id = $1`, freshID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shifts.Start(ctx, freshID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !active(freshID) {
+		t.Fatal("courier with a fresh shift is not active")
+	}
+
+	// Смена открыта давно, и курьер с тех пор не появлялся: он выпадает.
+	idleID, idleChatID := dbtest.InsertCourier(t, db)
+
+	shift, err := shifts.Start(ctx, idleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`UPDATE courier_shifts SET started_at = NOW() - INTERVAL '2 hours' WHERE id = $1`, shift.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`UPDATE couriers SET last_seen = NOW() - INTERVAL '1 hour' WHERE id = $1`, idleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if active(idleID) {
+		t.Fatal("idle courier is still active")
+	}
+
+	if err := couriers.TouchLastSeen(ctx, idleChatID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !active(idleID) {
+		t.Fatal("courier is not active after being seen")
+	}
+}
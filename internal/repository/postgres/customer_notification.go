@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type customerNotificationRepository struct {
+	db *instrumentedDB
+}
+
+func NewCustomerNotificationRepository(db *sql.DB) interfaces.CustomerNotification {
+	return &customerNotificationRepository{db: instrument(db, "customer_notification")}
+}
+
+func (r *customerNotificationRepository) Claim(ctx context.Context, notification *models.CustomerNotification) (bool, error) {
+	query := `
+		INSERT INTO
+			customer_notifications (
+				order_id,
+				event,
+				channel,
+				text
+			)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (order_id, event, channel) DO NOTHING
+		RETURNING
+			id,
+			status,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		notification.OrderID,
+		notification.Event,
+		notification.Channel,
+		notification.Text,
+	).Scan(&notification.ID, &notification.Status, &notification.CreatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim customer notification: %v", err)
+	}
+
+	return true, nil
+}
+
+func (r *customerNotificationRepository) Finish(ctx context.Context, id int, status models.CustomerNotificationStatus, errorText string) error {
+	query := `
+		UPDATE customer_notifications
+		SET
+			status = $1,
+			error = NULLIF($2, ''),
+			sent_at = CASE WHEN $1 = 'sent' THEN NOW() END
+		WHERE
+			id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, status, errorText, id)
+	if err != nil {
+		return fmt.Errorf("failed to finish customer notification (id: %d): %v", id, err)
+	}
+
+	return nil
+}
+
+func (r *customerNotificationRepository) ListByOrderID(ctx context.Context, orderID int) ([]*models.CustomerNotification, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			event,
+			channel,
+			status,
+			text,
+			error,
+			created_at,
+			sent_at
+		FROM
+			customer_notifications
+		WHERE
+			order_id = $1
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer notifications: %v", err)
+	}
+	defer rows.Close()
+
+	notifications := []*models.CustomerNotification{}
+
+	for rows.Next() {
+		var notification models.CustomerNotification
+
+		err := rows.Scan(
+			&notification.ID,
+			&notification.OrderID,
+			&notification.Event,
+			&notification.Channel,
+			&notification.Status,
+			&notification.Text,
+			&notification.Error,
+			&notification.CreatedAt,
+			&notification.SentAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer notification: %v", err)
+		}
+
+		notifications = append(notifications, &notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return notifications, nil
+}
+
+type customerContactRepository struct {
+	db *instrumentedDB
+}
+
+func NewCustomerContactRepository(db *sql.DB) interfaces.CustomerContact {
+	return &customerContactRepository{db: instrument(db, "customer_contact")}
+}
+
+func (r *customerContactRepository) Save(ctx context.Context, contact *models.CustomerContact) error {
+	query := `
+		INSERT INTO
+			order_customer_contacts (
+				order_id,
+				email,
+				telegram_chat_id
+			)
+		VALUES
+			($1, $2, $3)
+		ON CONFLICT (order_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			telegram_chat_id = EXCLUDED.telegram_chat_id,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, contact.OrderID, contact.Email, contact.TelegramChatID)
+	if err != nil {
+		return fmt.Errorf("failed to save customer contact: %v", err)
+	}
+
+	return nil
+}
+
+func (r *customerContactRepository) GetByOrderID(ctx context.Context, orderID int) (*models.CustomerContact, error) {
+	query := `
+		SELECT
+			order_id,
+			email,
+			telegram_chat_id
+		FROM
+			order_customer_contacts
+		WHERE
+			order_id = $1
+	`
+
+	var contact models.CustomerContact
+	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
+		&contact.OrderID,
+		&contact.Email,
+		&contact.TelegramChatID,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("customer contact %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get customer contact: %v", err)
+	}
+
+	return &contact, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// querier — общее у *sql.DB и *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// instrumentedDB оборачивает *sql.DB: на каждый запрос открывает спан и пишет
+// длительность и ошибки в метрики с меткой репозитория и SQL-операции.
+type instrumentedDB struct {
+	db   querier
+	repo string
+}
+
+func instrument(db *sql.DB, repo string) *instrumentedDB {
+	return &instrumentedDB{db: db, repo: repo}
+}
+
+// inTx выполняет fn в транзакции: запросы fn инструментируются так же, а
+// при ошибке транзакция откатывается.
+func (d *instrumentedDB) inTx(ctx context.Context, fn func(tx *instrumentedDB) error) error {
+	db, ok := d.db.(*sql.DB)
+	if !ok {
+		return fn(d)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	if err := fn(&instrumentedDB{db: tx, repo: d.repo}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func (d *instrumentedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := d.startSpan(ctx, query)
+	started := time.Now()
+	result, err := d.db.ExecContext(ctx, query, args...)
+	d.observe(query, started, err)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (d *instrumentedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := d.startSpan(ctx, query)
+	started := time.Now()
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	d.observe(query, started, err)
+	tracing.End(span, err)
+
+	return rows, err
+}
+
+func (d *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := d.startSpan(ctx, query)
+	started := time.Now()
+	row := d.db.QueryRowContext(ctx, query, args...)
+
+	err := row.Err()
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	d.observe(query, started, err)
+	tracing.End(span, err)
+
+	return row
+}
+
+func (d *instrumentedDB) startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := sqlOperation(query)
+
+	return tracing.Start(ctx, "postgres."+d.repo+"."+operation,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation.name", operation),
+	)
+}
+
+func (d *instrumentedDB) observe(query string, started time.Time, err error) {
+	operation := sqlOperation(query)
+
+	metrics.DBQueryDuration.WithLabelValues(d.repo, operation).Observe(time.Since(started).Seconds())
+	if err != nil {
+		metrics.DBQueryErrors.WithLabelValues(d.repo, operation).Inc()
+	}
+}
+
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	return strings.ToLower(fields[0])
+}
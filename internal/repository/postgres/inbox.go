@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type inboxRepository struct {
+	db *instrumentedDB
+}
+
+func NewInboxRepository(db *sql.DB) interfaces.Inbox {
+	return &inboxRepository{db: instrument(db, "inbox")}
+}
+
+func (r *inboxRepository) Insert(ctx context.Context, event *models.InboxEvent) (bool, error) {
+	query := `
+		INSERT INTO
+			webhook_inbox (
+				idempotency_key,
+				event_type,
+				order_id,
+				payload,
+				status,
+				trace_parent
+			)
+		VALUES
+			($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING
+			id,
+			received_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		event.IdempotencyKey,
+		event.EventType,
+		event.OrderID,
+		event.Payload,
+		models.InboxStatusPending,
+		event.TraceParent,
+	).Scan(&event.ID, &event.ReceivedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to insert inbox event: %v", err)
+	}
+
+	event.Status = models.InboxStatusPending
+
+	return true, nil
+}
+
+func (r *inboxRepository) GetByKey(ctx context.Context, key string) (*models.InboxEvent, error) {
+	query := `
+		SELECT
+			id,
+			idempotency_key,
+			event_type,
+			order_id,
+			payload,
+			status,
+			attempts,
+			last_error,
+			received_at,
+			processed_at,
+			COALESCE(trace_parent, '')
+		FROM
+			webhook_inbox
+		WHERE
+			idempotency_key = $1
+	`
+
+	var event models.InboxEvent
+
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&event.ID,
+		&event.IdempotencyKey,
+		&event.EventType,
+		&event.OrderID,
+		&event.Payload,
+		&event.Status,
+		&event.Attempts,
+		&event.LastError,
+		&event.ReceivedAt,
+		&event.ProcessedAt,
+		&event.TraceParent,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("inbox event not found")
+		}
+		return nil, fmt.Errorf("failed to get inbox event by key: %v", err)
+	}
+
+	return &event, nil
+}
+
+func (r *inboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*models.InboxEvent, error) {
+	query := `
+		UPDATE webhook_inbox
+		SET
+			locked_until = NOW() + $2 * INTERVAL '1 millisecond',
+			attempts = attempts + 1
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					webhook_inbox
+				WHERE
+					status = 'pending'
+					AND (locked_until IS NULL OR locked_until < NOW())
+				ORDER BY
+					id ASC
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			id,
+			idempotency_key,
+			event_type,
+			order_id,
+			payload,
+			status,
+			attempts,
+			last_error,
+			received_at,
+			processed_at,
+			COALESCE(trace_parent, '')
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim inbox events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []*models.InboxEvent
+
+	for rows.Next() {
+		var event models.InboxEvent
+
+		err := rows.Scan(
+			&event.ID,
+			&event.IdempotencyKey,
+			&event.EventType,
+			&event.OrderID,
+			&event.Payload,
+			&event.Status,
+			&event.Attempts,
+			&event.LastError,
+			&event.ReceivedAt,
+			&event.ProcessedAt,
+			&event.TraceParent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inbox event: %v", err)
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return events, nil
+}
+
+func (r *inboxRepository) MarkDone(ctx context.Context, id int) error {
+	query := `
+		UPDATE webhook_inbox
+		SET
+			status = 'done',
+			last_error = NULL,
+			locked_until = NULL,
+			processed_at = NOW()
+		WHERE
+			id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark inbox event (id %d) as done: %v", id, err)
+	}
+
+	return nil
+}
+
+func (r *inboxRepository) MarkRetry(ctx context.Context, id int, lastError string, retryAfter time.Duration) error {
+	query := `
+		UPDATE webhook_inbox
+		SET
+			last_error = $1,
+			locked_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE
+			id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lastError, retryAfter.Milliseconds(), id)
+	if err != nil {
+		return fmt.Errorf("failed to schedule retry for inbox event (id %d): %v", id, err)
+	}
+
+	return nil
+}
+
+func (r *inboxRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	query := `
+		UPDATE webhook_inbox
+		SET
+			status = 'failed',
+			last_error = $1,
+			locked_until = NULL,
+			processed_at = NOW()
+		WHERE
+			id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lastError, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark inbox event (id %d) as failed: %v", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type notificationSettingsRepository struct {
+	db *instrumentedDB
+}
+
+func NewNotificationSettingsRepository(db *sql.DB) interfaces.NotificationSettings {
+	return &notificationSettingsRepository{db: instrument(db, "notification_settings")}
+}
+
+func (r *notificationSettingsRepository) GetByChatID(ctx context.Context, chatID int64) (*models.NotificationSettings, error) {
+	query := `
+		SELECT
+			c.id,
+			s.courier_id IS NOT NULL,
+			COALESCE(s.quiet_mode, false),
+			COALESCE(s.silent_offers, false),
+			COALESCE(s.order_ready, false),
+			COALESCE(s.reminders, false),
+			COALESCE(s.shift_summary, false),
+			COALESCE(s.daily_summary, false)
+		FROM
+			couriers c
+			LEFT JOIN courier_notification_settings s ON s.courier_id = c.id
+		WHERE
+			c.chat_id = $1
+	`
+
+	var (
+		courierID int
+		saved     bool
+		settings  models.NotificationSettings
+	)
+
+	err := r.db.QueryRowContext(ctx, query, chatID).Scan(
+		&courierID,
+		&saved,
+		&settings.QuietMode,
+		&settings.SilentOffers,
+		&settings.OrderReady,
+		&settings.Reminders,
+		&settings.ShiftSummary,
+		&settings.DailySummary,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("courier %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get notification settings (chatID: %d): %v", chatID, err)
+	}
+
+	if !saved {
+		return models.DefaultNotificationSettings(courierID), nil
+	}
+
+	settings.CourierID = courierID
+
+	return &settings, nil
+}
+
+func (r *notificationSettingsRepository) Save(ctx context.Context, settings *models.NotificationSettings) error {
+	query := `
+		INSERT INTO
+			courier_notification_settings (
+				courier_id,
+				quiet_mode,
+				silent_offers,
+				order_ready,
+				reminders,
+				shift_summary,
+				daily_summary
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (courier_id) DO UPDATE
+		SET
+			quiet_mode = EXCLUDED.quiet_mode,
+			silent_offers = EXCLUDED.silent_offers,
+			order_ready = EXCLUDED.order_ready,
+			reminders = EXCLUDED.reminders,
+			shift_summary = EXCLUDED.shift_summary,
+			daily_summary = EXCLUDED.daily_summary,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		settings.CourierID,
+		settings.QuietMode,
+		settings.SilentOffers,
+		settings.OrderReady,
+		settings.Reminders,
+		settings.ShiftSummary,
+		settings.DailySummary,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings (courierID: %d): %v", settings.CourierID, err)
+	}
+
+	return nil
+}
+
+func (r *notificationSettingsRepository) ListDailySummaryDue(ctx context.Context, day time.Time) ([]*models.Courier, error) {
+	query := `
+		SELECT
+			c.id,
+			c.chat_id,
+			c.language
+		FROM
+			courier_notification_settings s
+			JOIN couriers c ON c.id = s.courier_id
+		WHERE
+			s.daily_summary
+			AND (s.daily_summary_sent_on IS NULL OR s.daily_summary_sent_on < $1::date)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, day.Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily summary recipients: %v", err)
+	}
+	defer rows.Close()
+
+	var couriers []*models.Courier
+
+	for rows.Next() {
+		var courier models.Courier
+
+		if err := rows.Scan(&courier.ID, &courier.ChatID, &courier.Language); err != nil {
+			return nil, fmt.Errorf("failed to scan daily summary recipient: %v", err)
+		}
+
+		couriers = append(couriers, &courier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return couriers, nil
+}
+
+func (r *notificationSettingsRepository) MarkDailySummarySent(ctx context.Context, courierID int, day time.Time) (bool, error) {
+	query := `
+		UPDATE courier_notification_settings
+		SET
+			daily_summary_sent_on = $2::date
+		WHERE
+			courier_id = $1
+			AND (daily_summary_sent_on IS NULL OR daily_summary_sent_on < $2::date)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, courierID, day.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("failed to mark daily summary: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
@@ -3,18 +3,18 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 
 	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
 )
 
 type orderRepository struct {
-	db *sql.DB
+	db *instrumentedDB
 }
 
 func NewOrderRepository(db *sql.DB) *orderRepository {
-	return &orderRepository{db: db}
+	return &orderRepository{db: instrument(db, "order")}
 }
 
 func (r *orderRepository) GetByID(ctx context.Context, id int) (*models.Order, error) {
@@ -43,7 +43,10 @@ func (r *orderRepository) GetByID(ctx context.Context, id int) (*models.Order, e
 			is_assembled,
 			is_received,
 			payment_url,
-			courier_id
+			courier_id,
+			cancelled_at,
+			cancelled_by,
+			cancel_reason
 		FROM
 			orders
 		WHERE
@@ -76,10 +79,13 @@ func (r *orderRepository) GetByID(ctx context.Context, id int) (*models.Order, e
 		&order.IsReceived,
 		&order.PaymentUrl,
 		&order.CourierID,
+		&order.CancelledAt,
+		&order.CancelledBy,
+		&order.CancelReason,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("order not found")
+			return nil, fmt.Errorf("order %w", interfaces.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get order by id: %v", err)
 	}
@@ -87,21 +93,29 @@ func (r *orderRepository) GetByID(ctx context.Context, id int) (*models.Order, e
 	return &order, nil
 }
 
-func (r *orderRepository) UpdateCourierID(ctx context.Context, id int, courierID int) error {
+// UpdateCourierID закрепляет заказ за курьером, если заказ не отменён.
+// false значит, что отмена успела раньше и назначение устарело.
+func (r *orderRepository) UpdateCourierID(ctx context.Context, id int, courierID int) (bool, error) {
 	query := `
 		UPDATE orders
 		SET
 			courier_id = $1
 		WHERE
 			id = $2
+			AND cancelled_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, query, courierID, id)
+	result, err := r.db.ExecContext(ctx, query, courierID, id)
 	if err != nil {
-		return fmt.Errorf("failed to update courier_id field of order (id: %d) table: %v", id, err)
+		return false, fmt.Errorf("failed to update courier_id field of order (id: %d) table: %v", id, err)
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
 }
 
 func (r *orderRepository) GetActiveOrdersByCourier(ctx context.Context, courierID int) ([]models.Order, error) {
@@ -130,7 +144,10 @@ func (r *orderRepository) GetActiveOrdersByCourier(ctx context.Context, courierI
 			is_assembled,
 			is_received,
 			payment_url,
-			courier_id
+			courier_id,
+			cancelled_at,
+			cancelled_by,
+			cancel_reason
 		FROM
 			orders
 		WHERE
@@ -138,6 +155,7 @@ func (r *orderRepository) GetActiveOrdersByCourier(ctx context.Context, courierI
 			AND is_paid = true
 			AND is_assembled = true
 			AND is_received = false
+			AND cancelled_at IS NULL
 		ORDER BY
 			CASE
 				WHEN delivery_date <= NOW() THEN 1
@@ -182,6 +200,9 @@ func (r *orderRepository) GetActiveOrdersByCourier(ctx context.Context, courierI
 			&order.IsReceived,
 			&order.PaymentUrl,
 			&order.CourierID,
+			&order.CancelledAt,
+			&order.CancelledBy,
+			&order.CancelReason,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan order: %v", err)
@@ -197,11 +218,141 @@ func (r *orderRepository) GetActiveOrdersByCourier(ctx context.Context, courierI
 	return orders, nil
 }
 
+// ListByCourier возвращает страницу заказов курьера на вкладке и общее число
+// заказов на ней. Отменённые заказы в список не попадают.
+func (r *orderRepository) ListByCourier(ctx context.Context, filter models.CourierOrderFilter) ([]models.Order, int, error) {
+	var condition, order string
+	args := []any{filter.CourierID, filter.Limit, filter.Offset}
+
+	switch filter.Tab {
+	case models.CourierOrdersActive:
+		condition = "is_paid = true AND is_assembled = true AND is_received = false"
+		order = `
+			CASE
+				WHEN delivery_date <= NOW() THEN 1
+				WHEN DATE(delivery_date) = CURRENT_DATE THEN 2
+				ELSE 3
+			END,
+			delivery_date ASC,
+			id ASC`
+	case models.CourierOrdersToday:
+		condition = "is_received = true AND received_at >= $4"
+		order = "received_at DESC, id DESC"
+		args = append(args, filter.Since)
+	case models.CourierOrdersHistory:
+		condition = "is_received = true AND received_at < $4"
+		order = "received_at DESC, id DESC"
+		args = append(args, filter.Since)
+	default:
+		return nil, 0, fmt.Errorf("unknown order tab %q", filter.Tab)
+	}
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			name,
+			phone_number,
+			city,
+			address,
+			flat,
+			entrance,
+			delivery_price,
+			first_price,
+			final_price,
+			paid_price,
+			bonus_accrual_percentage,
+			received_bonuses,
+			lost_bonuses,
+			created_at,
+			delivery_date,
+			received_at,
+			is_paid,
+			is_delivery,
+			is_assembled,
+			is_received,
+			payment_url,
+			courier_id,
+			cancelled_at,
+			cancelled_by,
+			cancel_reason,
+			COUNT(*) OVER () AS total
+		FROM
+			orders
+		WHERE
+			courier_id = $1
+			AND cancelled_at IS NULL
+			AND ` + condition + `
+		ORDER BY ` + order + `
+		LIMIT $2
+		OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders by courier: %v", err)
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	var total int
+
+	for rows.Next() {
+		var order models.Order
+
+		err := rows.Scan(
+			&order.ID,
+			&order.UserID,
+			&order.Name,
+			&order.PhoneNumber,
+			&order.City,
+			&order.Address,
+			&order.Flat,
+			&order.Entrance,
+			&order.DeliveryPrice,
+			&order.FirstPrice,
+			&order.FinalPrice,
+			&order.PaidPrice,
+			&order.BonusAccrualPercentage,
+			&order.RecievedBonuses,
+			&order.LostBonuses,
+			&order.CreatedAt,
+			&order.DeliveryDate,
+			&order.RecievedAt,
+			&order.IsPaid,
+			&order.IsDelivery,
+			&order.IsAssembled,
+			&order.IsReceived,
+			&order.PaymentUrl,
+			&order.CourierID,
+			&order.CancelledAt,
+			&order.CancelledBy,
+			&order.CancelReason,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan order: %v", err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return orders, total, nil
+}
+
 func (r *orderRepository) UpdateStatusReceived(ctx context.Context, id int, received bool) error {
 	query := `
 		UPDATE orders
 		SET
-			is_received = $1
+			is_received = $1,
+			received_at = CASE
+				WHEN $1 THEN COALESCE(received_at, NOW())
+				ELSE NULL
+			END
 		WHERE
 			id = $2
 	`
@@ -213,3 +364,70 @@ func (r *orderRepository) UpdateStatusReceived(ctx context.Context, id int, rece
 
 	return nil
 }
+
+// Cancel отменяет заказ и отзывает его живые предложения одной транзакцией:
+// принятие после отмены уже не найдёт ожидающего предложения, а принятое
+// до неё отзывается вместе с заказом. Возвращает отозванные назначения;
+// false значит, что заказ уже отменён или доставлен.
+func (r *orderRepository) Cancel(ctx context.Context, id int, source models.CancelSource, reason string) ([]*models.OrderAssignment, bool, error) {
+	query := `
+		UPDATE orders
+		SET
+			cancelled_at = NOW(),
+			cancelled_by = $1,
+			cancel_reason = NULLIF($2, ''),
+			courier_id = NULL
+		WHERE
+			id = $3
+			AND cancelled_at IS NULL
+			AND is_received = false
+	`
+
+	var (
+		live      []*models.OrderAssignment
+		cancelled bool
+	)
+
+	err := r.db.inTx(ctx, func(tx *instrumentedDB) error {
+		result, err := tx.ExecContext(ctx, query, source, reason, id)
+		if err != nil {
+			return fmt.Errorf("failed to cancel order (id: %d): %v", id, err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %v", err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		cancelled = true
+
+		live, err = cancelLiveAssignments(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return live, cancelled, nil
+}
+
+func (r *orderRepository) ClearCourierID(ctx context.Context, id int) error {
+	query := `
+		UPDATE orders
+		SET
+			courier_id = NULL
+		WHERE
+			id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to clear courier_id field of order (id: %d): %v", id, err)
+	}
+
+	return nil
+}
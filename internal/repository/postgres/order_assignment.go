@@ -5,17 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/CAATHARSIS/courier-bot/internal/models"
 	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
 )
 
 type orderAssignmentRepository struct {
-	db *sql.DB
+	db *instrumentedDB
 }
 
 func NewOrderAssignmentRepository(db *sql.DB) interfaces.OrderAssignment {
-	return &orderAssignmentRepository{db: db}
+	return &orderAssignmentRepository{db: instrument(db, "order_assignment")}
 }
 
 func (r *orderAssignmentRepository) Create(ctx context.Context, orderAssignment *models.OrderAssignment) error {
@@ -51,6 +52,65 @@ func (r *orderAssignmentRepository) Create(ctx context.Context, orderAssignment
 	return nil
 }
 
+// CreateOffer создаёт предложение, только если курьер на линии и смена
+// открыта. Строка курьера берётся FOR SHARE: закрытие смены
+// (Shift.EndWithoutOrders) ждёт, пока предложение запишется, и увидит его,
+// а предложение, ждавшее закрытия, после него не создастся.
+func (r *orderAssignmentRepository) CreateOffer(ctx context.Context, orderAssignment *models.OrderAssignment) (bool, error) {
+	query := `
+		INSERT INTO
+			order_assignments (
+				order_id,
+				courier_id,
+				assigned_at,
+				expired_at,
+				courier_response_status
+			)
+		SELECT
+			$1,
+			c.id,
+			$3,
+			$4,
+			$5
+		FROM
+			couriers c
+		WHERE
+			c.id = $2
+			AND c.is_active = true
+			AND EXISTS (
+				SELECT
+					1
+				FROM
+					courier_shifts s
+				WHERE
+					s.courier_id = c.id
+					AND s.ended_at IS NULL
+			)
+		FOR SHARE OF c
+		RETURNING
+			id
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		orderAssignment.OrderID,
+		orderAssignment.CourierID,
+		orderAssignment.AssignedAt,
+		orderAssignment.ExpiredAt,
+		orderAssignment.CourierResponseStatus,
+	).Scan(&orderAssignment.ID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create order offer: %v", err)
+	}
+
+	return true, nil
+}
+
 func (r *orderAssignmentRepository) GetByID(ctx context.Context, id int) (*models.OrderAssignment, error) {
 	query := `
 		SELECT
@@ -79,7 +139,7 @@ func (r *orderAssignmentRepository) GetByID(ctx context.Context, id int) (*model
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("order assignment not found")
+			return nil, fmt.Errorf("order assignment %w", interfaces.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get order assignment: %v", err)
 	}
@@ -220,6 +280,8 @@ func (r *orderAssignmentRepository) List(ctx context.Context) ([]*models.OrderAs
 	return orderAssignments, nil
 }
 
+// GetRejectedCouriers возвращает курьеров, которым заказ больше не
+// предлагается: отказавшихся, не ответивших вовремя и снятых диспетчером.
 func (r *orderAssignmentRepository) GetRejectedCouriers(ctx context.Context, orderID int) ([]int, error) {
 	query := `
 		SELECT
@@ -227,7 +289,7 @@ func (r *orderAssignmentRepository) GetRejectedCouriers(ctx context.Context, ord
 		FROM
 			order_assignments
 		WHERE
-			order_id = $1 AND courier_response_status IN ('rejected', 'expired')
+			order_id = $1 AND courier_response_status IN ('rejected', 'expired', 'cancelled')
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, orderID)
@@ -264,6 +326,10 @@ func (r *orderAssignmentRepository) GetByOrderID(ctx context.Context, orderID in
 			order_assignments
 		WHERE
 			order_id = $1
+		ORDER BY
+			assigned_at DESC,
+			id DESC
+		LIMIT 1
 	`
 
 	var orderAssignment models.OrderAssignment
@@ -277,25 +343,293 @@ func (r *orderAssignmentRepository) GetByOrderID(ctx context.Context, orderID in
 		&orderAssignment.CourierResponseStatus,
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order assignment %w", interfaces.ErrNotFound)
+		}
 		return nil, fmt.Errorf("failed to get order assignment with order id (%d): %v", orderID, err)
 	}
 
 	return &orderAssignment, nil
 }
 
-func (r *orderAssignmentRepository) UpdateStatus(ctx context.Context, orderID int, newStatus models.CourierResponseStatus) error {
+func (r *orderAssignmentRepository) ListExpiredWaiting(ctx context.Context, now time.Time) ([]*models.OrderAssignment, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			assigned_at,
+			expired_at,
+			courier_response_status
+		FROM
+			order_assignments
+		WHERE
+			courier_response_status = 'waiting'
+			AND expired_at <= $1
+		ORDER BY
+			expired_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired order assignments: %v", err)
+	}
+	defer rows.Close()
+
+	var orderAssignments []*models.OrderAssignment
+
+	for rows.Next() {
+		var orderAssignment models.OrderAssignment
+
+		err := rows.Scan(
+			&orderAssignment.ID,
+			&orderAssignment.OrderID,
+			&orderAssignment.CourierID,
+			&orderAssignment.AssignedAt,
+			&orderAssignment.ExpiredAt,
+			&orderAssignment.CourierResponseStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expired order assignment: %v", err)
+		}
+
+		orderAssignments = append(orderAssignments, &orderAssignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return orderAssignments, nil
+}
+
+func (r *orderAssignmentRepository) ExpireWaiting(ctx context.Context, id int) (bool, error) {
+	return r.ResolveWaiting(ctx, id, models.ResponsseStatusExpired)
+}
+
+// ResolveWaiting записывает ответ на предложение, только если оно ещё ждёт
+// ответа. false — предложение уже принято, отклонено, истекло или отменено.
+func (r *orderAssignmentRepository) ResolveWaiting(ctx context.Context, id int, status models.CourierResponseStatus) (bool, error) {
 	query := `
 		UPDATE order_assignments
 		SET
-			courier_response_status = $1
+			courier_response_status = $2
 		WHERE
-			order_id = $2 
+			id = $1
+			AND courier_response_status = 'waiting'
 	`
 
-	_, err := r.db.ExecContext(ctx, query, newStatus, orderID)
+	result, err := r.db.ExecContext(ctx, query, id, status)
 	if err != nil {
-		return fmt.Errorf("failed to update order assignment (with order id %d) status: %v", orderID, err)
+		return false, fmt.Errorf("failed to update order assignment (id %d) status: %v", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *orderAssignmentRepository) CancelLive(ctx context.Context, orderID int) ([]*models.OrderAssignment, error) {
+	return cancelLiveAssignments(ctx, r.db, orderID)
+}
+
+// cancelLiveAssignments отзывает ожидающие и принятые предложения заказа.
+// Order.Cancel вызывает её в своей транзакции.
+func cancelLiveAssignments(ctx context.Context, db *instrumentedDB, orderID int) ([]*models.OrderAssignment, error) {
+	query := `
+		UPDATE order_assignments
+		SET
+			courier_response_status = 'cancelled'
+		WHERE
+			order_id = $1
+			AND courier_response_status IN ('waiting', 'accepted')
+		RETURNING
+			id,
+			order_id,
+			courier_id,
+			assigned_at,
+			expired_at,
+			courier_response_status
+	`
+
+	rows, err := db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel live assignments for order %d: %v", orderID, err)
+	}
+	defer rows.Close()
+
+	var orderAssignments []*models.OrderAssignment
+
+	for rows.Next() {
+		var orderAssignment models.OrderAssignment
+
+		err := rows.Scan(
+			&orderAssignment.ID,
+			&orderAssignment.OrderID,
+			&orderAssignment.CourierID,
+			&orderAssignment.AssignedAt,
+			&orderAssignment.ExpiredAt,
+			&orderAssignment.CourierResponseStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cancelled order assignment: %v", err)
+		}
+
+		orderAssignments = append(orderAssignments, &orderAssignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return orderAssignments, nil
+}
+
+func (r *orderAssignmentRepository) ListFiltered(ctx context.Context, filter models.AssignmentFilter) ([]*models.OrderAssignment, int, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			assigned_at,
+			expired_at,
+			courier_response_status,
+			COUNT(*) OVER () AS total
+		FROM
+			order_assignments
+		WHERE
+			($1 = '' OR courier_response_status::TEXT = $1)
+			AND ($2 = 0 OR courier_id = $2)
+			AND ($3 = 0 OR order_id = $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR assigned_at >= $4)
+			AND ($5::TIMESTAMPTZ IS NULL OR assigned_at < $5)
+		ORDER BY
+			assigned_at DESC,
+			id DESC
+		LIMIT $6
+		OFFSET $7
+	`
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		filter.Status,
+		filter.CourierID,
+		filter.OrderID,
+		filter.From,
+		filter.To,
+		filter.Limit,
+		filter.Offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list order assignments: %v", err)
+	}
+	defer rows.Close()
+
+	var orderAssignments []*models.OrderAssignment
+	var total int
+
+	for rows.Next() {
+		var orderAssignment models.OrderAssignment
+
+		err := rows.Scan(
+			&orderAssignment.ID,
+			&orderAssignment.OrderID,
+			&orderAssignment.CourierID,
+			&orderAssignment.AssignedAt,
+			&orderAssignment.ExpiredAt,
+			&orderAssignment.CourierResponseStatus,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan order assignment: %v", err)
+		}
+
+		orderAssignments = append(orderAssignments, &orderAssignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return orderAssignments, total, nil
+}
+
+// Hold откладывает подбор курьера для заказа до releaseAt. Повторная
+// отсрочка того же заказа переносит время.
+func (r *orderAssignmentRepository) Hold(ctx context.Context, orderID int, releaseAt time.Time) error {
+	query := `
+		INSERT INTO order_assignment_holds (
+			order_id,
+			release_at
+		) VALUES ($1, $2)
+		ON CONFLICT (order_id) DO UPDATE SET
+			release_at = EXCLUDED.release_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, orderID, releaseAt); err != nil {
+		return fmt.Errorf("failed to hold order %d: %v", orderID, err)
 	}
 
 	return nil
 }
+
+func (r *orderAssignmentRepository) ListHolds(ctx context.Context) ([]*models.AssignmentHold, error) {
+	query := `
+		SELECT
+			order_id,
+			release_at
+		FROM
+			order_assignment_holds
+		ORDER BY
+			release_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order assignment holds: %v", err)
+	}
+	defer rows.Close()
+
+	var holds []*models.AssignmentHold
+
+	for rows.Next() {
+		var hold models.AssignmentHold
+
+		if err := rows.Scan(&hold.OrderID, &hold.ReleaseAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order assignment hold: %v", err)
+		}
+
+		holds = append(holds, &hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return holds, nil
+}
+
+// ReleaseHold снимает отсрочку. false — её уже снял другой инстанс.
+func (r *orderAssignmentRepository) ReleaseHold(ctx context.Context, orderID int) (bool, error) {
+	query := `
+		DELETE FROM order_assignment_holds
+		WHERE
+			order_id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, orderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to release order %d hold: %v", orderID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
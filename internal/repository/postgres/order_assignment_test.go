@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+)
+
+// Два инстанса с отдельными пулами истекают одно предложение одновременно:
+// переназначать заказ должен ровно один из них.
+func TestExpireWaitingClaimsOnceAcrossInstances(t *testing.T) {
+	first := NewOrderAssignmentRepository(dbtest.Open(t))
+	second := NewOrderAssignmentRepository(dbtest.Open(t))
+
+	db := dbtest.Open(t)
+	courierID, _ := dbtest.InsertCourier(t, db)
+	orderID := dbtest.InsertOrder(t, db)
+
+	ctx := context.Background()
+
+	assignment := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now().Add(-time.Hour),
+		ExpiredAt:             time.Now().Add(-time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+	if err := first.Create(ctx, assignment); err != nil {
+		t.Fatal(err)
+	}
+
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+
+	for _, repo := range []interface {
+		ExpireWaiting(ctx context.Context, id int) (bool, error)
+	}{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			updated, err := repo.ExpireWaiting(ctx, assignment.ID)
+			if err != nil {
+				t.Errorf("ExpireWaiting: %v", err)
+				return
+			}
+
+			if updated {
+				claimed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := claimed.Load(); got != 1 {
+		t.Fatalf("assignment claimed %d times, want 1", got)
+	}
+
+	stored, err := second.GetByID(ctx, assignment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.CourierResponseStatus != models.ResponsseStatusExpired {
+		t.Fatalf("status = %q, want %q", stored.CourierResponseStatus, models.ResponsseStatusExpired)
+	}
+}
+
+// Принятие, отказ и истечение одного предложения: записывается только
+// первый ответ, остальные видят, что предложение уже не ждёт.
+func TestResolveWaitingFirstResponseWins(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewOrderAssignmentRepository(db)
+
+	courierID, _ := dbtest.InsertCourier(t, db)
+	orderID := dbtest.InsertOrder(t, db)
+
+	ctx := context.Background()
+
+	assignment := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now(),
+		ExpiredAt:             time.Now().Add(time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+	if err := repo.Create(ctx, assignment); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := []models.CourierResponseStatus{
+		models.ResponseStatusAccepted,
+		models.ResponseStatusRejected,
+		models.ResponsseStatusExpired,
+	}
+
+	var winners atomic.Int32
+	var wg sync.WaitGroup
+
+	for _, status := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			updated, err := repo.ResolveWaiting(ctx, assignment.ID, status)
+			if err != nil {
+				t.Errorf("ResolveWaiting(%s): %v", status, err)
+				return
+			}
+
+			if updated {
+				winners.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := winners.Load(); got != 1 {
+		t.Fatalf("%d responses recorded, want 1", got)
+	}
+
+	// Ответ пишется в конкретное предложение, а не в последнее по заказу.
+	next := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now().Add(time.Second),
+		ExpiredAt:             time.Now().Add(time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+	if err := repo.Create(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+
+	if updated, err := repo.ResolveWaiting(ctx, assignment.ID, models.ResponseStatusRejected); err != nil || updated {
+		t.Fatalf("ResolveWaiting on a resolved offer = %v, %v; want false, nil", updated, err)
+	}
+
+	stored, err := repo.GetByID(ctx, next.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.CourierResponseStatus != models.ResponseStatusWaiting {
+		t.Fatalf("new offer status = %q, want waiting", stored.CourierResponseStatus)
+	}
+}
+
+func TestHoldRelease(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewOrderAssignmentRepository(db)
+
+	orderID := dbtest.InsertOrder(t, db)
+
+	ctx := context.Background()
+
+	releaseAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := repo.Hold(ctx, orderID, releaseAt.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Повторная отсрочка переносит время, а не дублирует запись.
+	if err := repo.Hold(ctx, orderID, releaseAt); err != nil {
+		t.Fatal(err)
+	}
+
+	holds, err := repo.ListHolds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found int
+	for _, hold := range holds {
+		if hold.OrderID != orderID {
+			continue
+		}
+
+		found++
+		if !hold.ReleaseAt.Equal(releaseAt) {
+			t.Fatalf("release_at = %v, want %v", hold.ReleaseAt, releaseAt)
+		}
+	}
+	if found != 1 {
+		t.Fatalf("order held %d times, want 1", found)
+	}
+
+	if released, err := repo.ReleaseHold(ctx, orderID); err != nil || !released {
+		t.Fatalf("ReleaseHold = %v, %v; want true, nil", released, err)
+	}
+
+	if released, err := repo.ReleaseHold(ctx, orderID); err != nil || released {
+		t.Fatalf("second ReleaseHold = %v, %v; want false, nil", released, err)
+	}
+}
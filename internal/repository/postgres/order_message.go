@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type orderMessageRepository struct {
+	db *instrumentedDB
+}
+
+func NewOrderMessageRepository(db *sql.DB) interfaces.OrderMessage {
+	return &orderMessageRepository{db: instrument(db, "order_message")}
+}
+
+// Save запоминает сообщение заказа. У заказа в чате одно сообщение каждого
+// вида, поэтому новое сообщение заменяет прежнее.
+func (r *orderMessageRepository) Save(ctx context.Context, message *models.OrderMessage) error {
+	query := `
+		INSERT INTO
+			order_messages (
+				order_id,
+				chat_id,
+				message_id,
+				kind
+			)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (order_id, chat_id, kind) DO UPDATE SET
+			message_id = EXCLUDED.message_id,
+			created_at = NOW()
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		message.OrderID,
+		message.ChatID,
+		message.MessageID,
+		message.Kind,
+	).Scan(&message.ID, &message.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save order message: %v", err)
+	}
+
+	return nil
+}
+
+func (r *orderMessageRepository) Get(ctx context.Context, orderID int, chatID int64, kind models.OrderMessageKind) (*models.OrderMessage, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			chat_id,
+			message_id,
+			kind,
+			created_at
+		FROM
+			order_messages
+		WHERE
+			order_id = $1
+			AND chat_id = $2
+			AND kind = $3
+	`
+
+	var message models.OrderMessage
+	err := r.db.QueryRowContext(ctx, query, orderID, chatID, kind).Scan(
+		&message.ID,
+		&message.OrderID,
+		&message.ChatID,
+		&message.MessageID,
+		&message.Kind,
+		&message.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order message %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get order message: %v", err)
+	}
+
+	return &message, nil
+}
+
+func (r *orderMessageRepository) ListByOrderID(ctx context.Context, orderID int) ([]*models.OrderMessage, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			chat_id,
+			message_id,
+			kind,
+			created_at
+		FROM
+			order_messages
+		WHERE
+			order_id = $1
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order messages: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []*models.OrderMessage
+
+	for rows.Next() {
+		var message models.OrderMessage
+
+		err := rows.Scan(
+			&message.ID,
+			&message.OrderID,
+			&message.ChatID,
+			&message.MessageID,
+			&message.Kind,
+			&message.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order message: %v", err)
+		}
+
+		messages = append(messages, &message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return messages, nil
+}
+
+func (r *orderMessageRepository) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM order_messages
+		WHERE
+			id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete order message: %v", err)
+	}
+
+	return nil
+}
+
+func (r *orderMessageRepository) DeleteByOrderID(ctx context.Context, orderID int) error {
+	query := `
+		DELETE FROM order_messages
+		WHERE
+			order_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to delete order messages: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+)
+
+// Каждая вкладка «Мои заказы» выполняется на настоящей схеме и отдаёт
+// только свои заказы курьера.
+func TestListByCourierTabs(t *testing.T) {
+	db := dbtest.Open(t)
+	orders := NewOrderRepository(db)
+
+	ctx := context.Background()
+
+	courierID, _ := dbtest.InsertCourier(t, db)
+	otherID, _ := dbtest.InsertCourier(t, db)
+
+	assign := func(courierID int) int {
+		orderID := dbtest.InsertOrder(t, db)
+		if _, err := orders.UpdateCourierID(ctx, orderID, courierID); err != nil {
+			t.Fatal(err)
+		}
+		return orderID
+	}
+
+	deliver := func(orderID int, at time.Time) {
+		if err := orders.UpdateStatusReceived(ctx, orderID, true); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`UPDATE orders SET received_at = $1 WHERE id = $2`, at, orderID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Hour)
+
+	active := assign(courierID)
+
+	today := assign(courierID)
+	deliver(today, now.Add(-time.Minute))
+
+	earlier := assign(courierID)
+	deliver(earlier, now.Add(-48*time.Hour))
+
+	cancelled := assign(courierID)
+	if _, err := db.Exec(`UPDATE orders SET cancelled_at = NOW() WHERE id = $1`, cancelled); err != nil {
+		t.Fatal(err)
+	}
+
+	assign(otherID)
+
+	tests := []struct {
+		tab  models.CourierOrderTab
+		want []int
+	}{
+		{models.CourierOrdersActive, []int{active}},
+		{models.CourierOrdersToday, []int{today}},
+		{models.CourierOrdersHistory, []int{earlier}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tab), func(t *testing.T) {
+			list, total, err := orders.ListByCourier(ctx, models.CourierOrderFilter{
+				CourierID: courierID,
+				Tab:       tt.tab,
+				Since:     since,
+				Page:      models.Page{Limit: 10},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			for _, order := range list {
+				got = append(got, order.ID)
+			}
+
+			if !slices.Equal(got, tt.want) || total != len(tt.want) {
+				t.Fatalf("orders = %v (total %d), want %v", got, total, tt.want)
+			}
+		})
+	}
+}
+
+// Отмена отзывает предложение в той же транзакции: принятие после неё не
+// находит ожидающего предложения и не может закрепить заказ за курьером.
+func TestCancelRevokesLiveAssignments(t *testing.T) {
+	db := dbtest.Open(t)
+	orders := NewOrderRepository(db)
+	assignments := NewOrderAssignmentRepository(db)
+
+	ctx := context.Background()
+
+	courierID, _ := dbtest.InsertCourier(t, db)
+	orderID := dbtest.InsertOrder(t, db)
+
+	offer := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now(),
+		ExpiredAt:             time.Now().Add(time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+	if err := assignments.Create(ctx, offer); err != nil {
+		t.Fatal(err)
+	}
+
+	live, cancelled, err := orders.Cancel(ctx, orderID, models.CancelSourceShop, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cancelled || len(live) != 1 || live[0].ID != offer.ID {
+		t.Fatalf("Cancel = %v, %v; want the waiting offer revoked", live, cancelled)
+	}
+
+	accepted, err := assignments.ResolveWaiting(ctx, offer.ID, models.ResponseStatusAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accepted {
+		t.Fatal("offer accepted after the order was cancelled")
+	}
+
+	assigned, err := orders.UpdateCourierID(ctx, orderID, courierID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assigned {
+		t.Fatal("cancelled order assigned to a courier")
+	}
+
+	if _, cancelled, err := orders.Cancel(ctx, orderID, models.CancelSourceShop, ""); err != nil || cancelled {
+		t.Fatalf("second Cancel = %v, %v; want false, nil", cancelled, err)
+	}
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type reviewRepository struct {
+	db *instrumentedDB
+}
+
+func NewReviewRepository(db *sql.DB) interfaces.Review {
+	return &reviewRepository{db: instrument(db, "review")}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *models.Review) (bool, error) {
+	query := `
+		INSERT INTO
+			order_reviews (
+				order_id,
+				courier_id,
+				rating,
+				comment
+			)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (order_id) DO NOTHING
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		review.OrderID,
+		review.CourierID,
+		review.Rating,
+		review.Comment,
+	).Scan(&review.ID, &review.CreatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create review: %v", err)
+	}
+
+	return true, nil
+}
+
+func (r *reviewRepository) GetByID(ctx context.Context, id int) (*models.Review, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			rating,
+			comment,
+			hidden,
+			hidden_at,
+			created_at
+		FROM
+			order_reviews
+		WHERE
+			id = $1
+	`
+
+	var review models.Review
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&review.ID,
+		&review.OrderID,
+		&review.CourierID,
+		&review.Rating,
+		&review.Comment,
+		&review.Hidden,
+		&review.HiddenAt,
+		&review.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("review %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get review (id: %d): %v", id, err)
+	}
+
+	return &review, nil
+}
+
+func (r *reviewRepository) GetByOrderID(ctx context.Context, orderID int) (*models.Review, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			rating,
+			comment,
+			hidden,
+			hidden_at,
+			created_at
+		FROM
+			order_reviews
+		WHERE
+			order_id = $1
+	`
+
+	var review models.Review
+
+	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
+		&review.ID,
+		&review.OrderID,
+		&review.CourierID,
+		&review.Rating,
+		&review.Comment,
+		&review.Hidden,
+		&review.HiddenAt,
+		&review.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("review %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get review (orderID: %d): %v", orderID, err)
+	}
+
+	return &review, nil
+}
+
+func (r *reviewRepository) SetHidden(ctx context.Context, id int, hidden bool) (*models.Review, error) {
+	query := `
+		UPDATE order_reviews
+		SET
+			hidden = $1,
+			hidden_at = CASE WHEN $1 THEN COALESCE(hidden_at, NOW()) END
+		WHERE
+			id = $2
+		RETURNING
+			id,
+			order_id,
+			courier_id,
+			rating,
+			comment,
+			hidden,
+			hidden_at,
+			created_at
+	`
+
+	var review models.Review
+
+	err := r.db.QueryRowContext(ctx, query, hidden, id).Scan(
+		&review.ID,
+		&review.OrderID,
+		&review.CourierID,
+		&review.Rating,
+		&review.Comment,
+		&review.Hidden,
+		&review.HiddenAt,
+		&review.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("review %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update review (id: %d): %v", id, err)
+	}
+
+	return &review, nil
+}
+
+func (r *reviewRepository) ListFiltered(ctx context.Context, filter models.ReviewFilter) ([]*models.Review, int, error) {
+	query := `
+		SELECT
+			id,
+			order_id,
+			courier_id,
+			rating,
+			comment,
+			hidden,
+			hidden_at,
+			created_at,
+			COUNT(*) OVER () AS total
+		FROM
+			order_reviews
+		WHERE
+			courier_id = $1
+			AND ($2 OR NOT hidden)
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT NULLIF($3, 0)
+		OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.CourierID, filter.IncludeHidden, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list reviews: %v", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.Review
+	var total int
+
+	for rows.Next() {
+		var review models.Review
+
+		err := rows.Scan(
+			&review.ID,
+			&review.OrderID,
+			&review.CourierID,
+			&review.Rating,
+			&review.Comment,
+			&review.Hidden,
+			&review.HiddenAt,
+			&review.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review: %v", err)
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return reviews, total, nil
+}
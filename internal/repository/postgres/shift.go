@@ -0,0 +1,430 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type shiftRepository struct {
+	db *instrumentedDB
+}
+
+func NewShiftRepository(db *sql.DB) interfaces.Shift {
+	return &shiftRepository{db: instrument(db, "shift")}
+}
+
+func (r *shiftRepository) Start(ctx context.Context, courierID int) (*models.Shift, error) {
+	query := `
+		INSERT INTO
+			courier_shifts (courier_id)
+		VALUES
+			($1)
+		RETURNING
+			id,
+			courier_id,
+			started_at,
+			ended_at,
+			end_reason
+	`
+
+	var shift models.Shift
+
+	err := r.db.QueryRowContext(ctx, query, courierID).Scan(
+		&shift.ID,
+		&shift.CourierID,
+		&shift.StartedAt,
+		&shift.EndedAt,
+		&shift.EndReason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start shift: %v", err)
+	}
+
+	return &shift, nil
+}
+
+func (r *shiftRepository) GetOpen(ctx context.Context, courierID int) (*models.Shift, error) {
+	query := `
+		SELECT
+			id,
+			courier_id,
+			started_at,
+			ended_at,
+			end_reason
+		FROM
+			courier_shifts
+		WHERE
+			courier_id = $1
+			AND ended_at IS NULL
+	`
+
+	var shift models.Shift
+
+	err := r.db.QueryRowContext(ctx, query, courierID).Scan(
+		&shift.ID,
+		&shift.CourierID,
+		&shift.StartedAt,
+		&shift.EndedAt,
+		&shift.EndReason,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("open shift %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get open shift: %v", err)
+	}
+
+	return &shift, nil
+}
+
+// EndWithoutOrders закрывает смену и снимает курьера с линии одной
+// транзакцией, если у него нет недоставленных заказов и предложений без
+// ответа. Строка курьера блокируется: пока смена закрывается, новое
+// предложение ему не создаётся (см. OrderAssignment.CreateOffer).
+func (r *shiftRepository) EndWithoutOrders(ctx context.Context, id int, reason models.ShiftEndReason) (*models.Shift, error) {
+	lockQuery := `
+		SELECT
+			c.id
+		FROM
+			couriers c
+			JOIN courier_shifts s ON s.courier_id = c.id
+		WHERE
+			s.id = $1
+		FOR UPDATE OF c
+	`
+
+	endQuery := `
+		UPDATE courier_shifts s
+		SET
+			ended_at = NOW(),
+			end_reason = $1
+		WHERE
+			s.id = $2
+			AND s.ended_at IS NULL
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					orders o
+				WHERE
+					o.is_received = false
+					AND o.cancelled_at IS NULL
+					AND (
+						o.courier_id = s.courier_id
+						OR EXISTS (
+							SELECT
+								1
+							FROM
+								order_assignments a
+							WHERE
+								a.order_id = o.id
+								AND a.courier_id = s.courier_id
+								AND a.courier_response_status = 'accepted'
+						)
+					)
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					order_assignments a
+				WHERE
+					a.courier_id = s.courier_id
+					AND a.courier_response_status = 'waiting'
+			)
+		RETURNING
+			s.id,
+			s.courier_id,
+			s.started_at,
+			s.ended_at,
+			s.end_reason
+	`
+
+	openQuery := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					courier_shifts
+				WHERE
+					id = $1
+					AND ended_at IS NULL
+			)
+	`
+
+	breakQuery := `
+		UPDATE courier_shift_breaks
+		SET
+			ended_at = NOW()
+		WHERE
+			shift_id = $1
+			AND ended_at IS NULL
+	`
+
+	courierQuery := `
+		UPDATE couriers
+		SET
+			is_active = false
+		WHERE
+			id = $1
+	`
+
+	var shift models.Shift
+
+	err := r.db.inTx(ctx, func(tx *instrumentedDB) error {
+		var courierID int
+		if err := tx.QueryRowContext(ctx, lockQuery, id).Scan(&courierID); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("shift %d %w", id, interfaces.ErrNotFound)
+			}
+			return fmt.Errorf("failed to lock courier of shift %d: %v", id, err)
+		}
+
+		err := tx.QueryRowContext(ctx, endQuery, string(reason), id).Scan(
+			&shift.ID,
+			&shift.CourierID,
+			&shift.StartedAt,
+			&shift.EndedAt,
+			&shift.EndReason,
+		)
+		if err == sql.ErrNoRows {
+			var open bool
+			if err := tx.QueryRowContext(ctx, openQuery, id).Scan(&open); err != nil {
+				return fmt.Errorf("failed to check shift %d: %v", id, err)
+			}
+
+			if open {
+				return fmt.Errorf("shift %d has undelivered orders %w", id, interfaces.ErrConflict)
+			}
+			return fmt.Errorf("open shift %d %w", id, interfaces.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to end shift: %v", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, breakQuery, id); err != nil {
+			return fmt.Errorf("failed to close shift break: %v", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, courierQuery, courierID); err != nil {
+			return fmt.Errorf("failed to update courier (id %d) status: %v", courierID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &shift, nil
+}
+
+func (r *shiftRepository) StartBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error) {
+	query := `
+		INSERT INTO
+			courier_shift_breaks (shift_id)
+		VALUES
+			($1)
+		RETURNING
+			id,
+			shift_id,
+			started_at,
+			ended_at
+	`
+
+	var shiftBreak models.ShiftBreak
+
+	err := r.db.QueryRowContext(ctx, query, shiftID).Scan(
+		&shiftBreak.ID,
+		&shiftBreak.ShiftID,
+		&shiftBreak.StartedAt,
+		&shiftBreak.EndedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start shift break: %v", err)
+	}
+
+	return &shiftBreak, nil
+}
+
+func (r *shiftRepository) EndBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error) {
+	query := `
+		UPDATE courier_shift_breaks
+		SET
+			ended_at = NOW()
+		WHERE
+			shift_id = $1
+			AND ended_at IS NULL
+		RETURNING
+			id,
+			shift_id,
+			started_at,
+			ended_at
+	`
+
+	var shiftBreak models.ShiftBreak
+
+	err := r.db.QueryRowContext(ctx, query, shiftID).Scan(
+		&shiftBreak.ID,
+		&shiftBreak.ShiftID,
+		&shiftBreak.StartedAt,
+		&shiftBreak.EndedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("open shift break %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to end shift break: %v", err)
+	}
+
+	return &shiftBreak, nil
+}
+
+func (r *shiftRepository) GetOpenBreak(ctx context.Context, shiftID int) (*models.ShiftBreak, error) {
+	query := `
+		SELECT
+			id,
+			shift_id,
+			started_at,
+			ended_at
+		FROM
+			courier_shift_breaks
+		WHERE
+			shift_id = $1
+			AND ended_at IS NULL
+	`
+
+	var shiftBreak models.ShiftBreak
+
+	err := r.db.QueryRowContext(ctx, query, shiftID).Scan(
+		&shiftBreak.ID,
+		&shiftBreak.ShiftID,
+		&shiftBreak.StartedAt,
+		&shiftBreak.EndedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("open shift break %w", interfaces.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get open shift break: %v", err)
+	}
+
+	return &shiftBreak, nil
+}
+
+func (r *shiftRepository) ListBreaks(ctx context.Context, shiftID int) ([]*models.ShiftBreak, error) {
+	query := `
+		SELECT
+			id,
+			shift_id,
+			started_at,
+			ended_at
+		FROM
+			courier_shift_breaks
+		WHERE
+			shift_id = $1
+		ORDER BY
+			started_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, shiftID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift breaks: %v", err)
+	}
+	defer rows.Close()
+
+	var breaks []*models.ShiftBreak
+	for rows.Next() {
+		var shiftBreak models.ShiftBreak
+
+		err := rows.Scan(
+			&shiftBreak.ID,
+			&shiftBreak.ShiftID,
+			&shiftBreak.StartedAt,
+			&shiftBreak.EndedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shift break: %v", err)
+		}
+
+		breaks = append(breaks, &shiftBreak)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return breaks, nil
+}
+
+func (r *shiftRepository) ListIdle(ctx context.Context, lastSeenBefore time.Time) ([]*models.Shift, error) {
+	query := `
+		SELECT
+			s.id,
+			s.courier_id,
+			s.started_at,
+			s.ended_at,
+			s.end_reason
+		FROM
+			courier_shifts s
+			JOIN couriers c ON c.id = s.courier_id
+		WHERE
+			s.ended_at IS NULL
+			AND GREATEST(c.last_seen, s.started_at) < $1
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					orders o
+				WHERE
+					o.courier_id = c.id
+					AND o.is_received = false
+					AND o.cancelled_at IS NULL
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					order_assignments a
+				WHERE
+					a.courier_id = c.id
+					AND a.courier_response_status = 'waiting'
+			)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, lastSeenBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list idle shifts: %v", err)
+	}
+	defer rows.Close()
+
+	var shifts []*models.Shift
+	for rows.Next() {
+		var shift models.Shift
+
+		err := rows.Scan(
+			&shift.ID,
+			&shift.CourierID,
+			&shift.StartedAt,
+			&shift.EndedAt,
+			&shift.EndReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shift: %v", err)
+		}
+
+		shifts = append(shifts, &shift)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return shifts, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/pkg/database/dbtest"
+)
+
+func TestEndWithoutOrders(t *testing.T) {
+	db := dbtest.Open(t)
+	shifts := NewShiftRepository(db)
+	assignments := NewOrderAssignmentRepository(db)
+	couriers := NewCourierRepository(db)
+	orders := NewOrderRepository(db)
+
+	ctx := context.Background()
+
+	courierID, _ := dbtest.InsertCourier(t, db)
+	orderID := dbtest.InsertOrder(t, db)
+
+	shift, err := shifts.Start(ctx, courierID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offer := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            time.Now(),
+		ExpiredAt:             time.Now().Add(time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+
+	created, err := assignments.CreateOffer(ctx, offer)
+	if err != nil || !created {
+		t.Fatalf("CreateOffer on shift = %v, %v; want true, nil", created, err)
+	}
+
+	// Предложение без ответа не даёт закрыть смену.
+	if _, err := shifts.EndWithoutOrders(ctx, shift.ID, models.ShiftEndByCourier); !errors.Is(err, interfaces.ErrConflict) {
+		t.Fatalf("EndWithoutOrders with a waiting offer: err = %v, want ErrConflict", err)
+	}
+
+	if _, err := assignments.ResolveWaiting(ctx, offer.ID, models.ResponseStatusAccepted); err != nil {
+		t.Fatal(err)
+	}
+
+	// Принятый заказ, даже до записи courier_id в заказ, тоже.
+	if _, err := shifts.EndWithoutOrders(ctx, shift.ID, models.ShiftEndByCourier); !errors.Is(err, interfaces.ErrConflict) {
+		t.Fatalf("EndWithoutOrders with an accepted order: err = %v, want ErrConflict", err)
+	}
+
+	if _, err := orders.UpdateCourierID(ctx, orderID, courierID); err != nil {
+		t.Fatal(err)
+	}
+	if err := orders.UpdateStatusReceived(ctx, orderID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	ended, err := shifts.EndWithoutOrders(ctx, shift.ID, models.ShiftEndByCourier)
+	if err != nil {
+		t.Fatalf("EndWithoutOrders after delivery: %v", err)
+	}
+	if ended.EndedAt == nil {
+		t.Fatal("shift is not ended")
+	}
+
+	courier, err := couriers.GetByID(ctx, courierID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if courier.IsActive {
+		t.Fatal("courier is still active after the shift ended")
+	}
+
+	if _, err := shifts.EndWithoutOrders(ctx, shift.ID, models.ShiftEndByCourier); !errors.Is(err, interfaces.ErrNotFound) {
+		t.Fatalf("EndWithoutOrders on a closed shift: err = %v, want ErrNotFound", err)
+	}
+
+	// После закрытия смены предложение курьеру не создаётся.
+	next := &models.OrderAssignment{
+		OrderID:               dbtest.InsertOrder(t, db),
+		CourierID:             courierID,
+		AssignedAt:            time.Now(),
+		ExpiredAt:             time.Now().Add(time.Minute),
+		CourierResponseStatus: models.ResponseStatusWaiting,
+	}
+
+	created, err = assignments.CreateOffer(ctx, next)
+	if err != nil || created {
+		t.Fatalf("CreateOffer off shift = %v, %v; want false, nil", created, err)
+	}
+}
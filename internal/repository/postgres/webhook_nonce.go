@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+type webhookNonceRepository struct {
+	db *instrumentedDB
+}
+
+func NewWebhookNonceRepository(db *sql.DB) interfaces.WebhookNonce {
+	return &webhookNonceRepository{db: instrument(db, "webhook_nonce")}
+}
+
+func (r *webhookNonceRepository) Remember(ctx context.Context, keyID, nonce string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO
+			webhook_nonces (key_id, nonce, expires_at)
+		VALUES
+			($1, $2, $3)
+		ON CONFLICT (key_id, nonce) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, keyID, nonce, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to remember webhook nonce: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *webhookNonceRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM webhook_nonces
+		WHERE
+			expires_at < $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired webhook nonces: %v", err)
+	}
+
+	return result.RowsAffected()
+}
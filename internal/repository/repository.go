@@ -8,15 +8,39 @@ import (
 )
 
 type Repository struct {
-	Courier         interfaces.CourierRepository
-	OrderAssignment interfaces.OrderAssignment
-	Order           interfaces.Order
+	Courier              interfaces.CourierRepository
+	OrderAssignment      interfaces.OrderAssignment
+	Order                interfaces.Order
+	Inbox                interfaces.Inbox
+	WebhookNonce         interfaces.WebhookNonce
+	OrderMessage         interfaces.OrderMessage
+	APIToken             interfaces.APIToken
+	CourierStats         interfaces.CourierStats
+	Shift                interfaces.Shift
+	Availability         interfaces.Availability
+	ContactMessage       interfaces.ContactMessage
+	CustomerNotification interfaces.CustomerNotification
+	CustomerContact      interfaces.CustomerContact
+	Review               interfaces.Review
+	NotificationSettings interfaces.NotificationSettings
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Courier:         postgres.NewCourierRepository(db),
-		OrderAssignment: postgres.NewOrderAssignmentRepository(db),
-		Order:           postgres.NewOrderRepository(db),
+		Courier:              postgres.NewCourierRepository(db),
+		OrderAssignment:      postgres.NewOrderAssignmentRepository(db),
+		Order:                postgres.NewOrderRepository(db),
+		Inbox:                postgres.NewInboxRepository(db),
+		WebhookNonce:         postgres.NewWebhookNonceRepository(db),
+		OrderMessage:         postgres.NewOrderMessageRepository(db),
+		APIToken:             postgres.NewAPITokenRepository(db),
+		CourierStats:         postgres.NewCourierStatsRepository(db),
+		Shift:                postgres.NewShiftRepository(db),
+		Availability:         postgres.NewAvailabilityRepository(db),
+		ContactMessage:       postgres.NewContactMessageRepository(db),
+		CustomerNotification: postgres.NewCustomerNotificationRepository(db),
+		CustomerContact:      postgres.NewCustomerContactRepository(db),
+		Review:               postgres.NewReviewRepository(db),
+		NotificationSettings: postgres.NewNotificationSettingsRepository(db),
 	}
 }
@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var (
+	ErrCourierHasHistory = errors.New("courier has orders or assignments")
+	ErrCourierHasOrders  = errors.New("courier has undelivered orders or pending offers")
+)
+
+type OrderDetails struct {
+	Order       *models.Order             `json:"order"`
+	Assignments []*models.OrderAssignment `json:"assignments"`
+}
+
+type Service struct {
+	repo          repository.Repository
+	idleThreshold time.Duration
+	location      *time.Location
+	log           *slog.Logger
+}
+
+func NewService(repo repository.Repository, idleThreshold time.Duration, location *time.Location, log *slog.Logger) *Service {
+	return &Service{
+		repo:          repo,
+		idleThreshold: idleThreshold,
+		location:      location,
+		log:           log,
+	}
+}
+
+func (s *Service) ListCouriers(ctx context.Context, filter models.CourierFilter) ([]*models.Courier, int, error) {
+	filter.Page = normalizePage(filter.Page)
+
+	couriers, total, err := s.repo.Courier.ListFiltered(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	for _, courier := range couriers {
+		courier.Presence = courier.PresenceAt(now, s.idleThreshold)
+	}
+
+	return couriers, total, nil
+}
+
+func (s *Service) GetCourier(ctx context.Context, id int) (*models.Courier, error) {
+	courier, err := s.repo.Courier.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	courier.Presence = courier.PresenceAt(time.Now(), s.idleThreshold)
+
+	return courier, nil
+}
+
+func (s *Service) SetCourierActive(ctx context.Context, id int, active bool) (*models.Courier, error) {
+	if _, err := s.repo.Courier.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Смена закрывается до снятия флага: курьер с заказами остаётся на линии.
+	if err := s.syncShift(ctx, id, active); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Courier.SetActive(ctx, id, active); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Courier status changed by admin", "courierID", id, "active", active)
+
+	return s.GetCourier(ctx, id)
+}
+
+func (s *Service) GetCourierAvailability(ctx context.Context, id int) (*models.CourierAvailability, error) {
+	if _, err := s.repo.Courier.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	windows, err := s.repo.Availability.ListWindows(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := s.repo.Availability.ListExceptions(ctx, id, time.Now().In(s.location))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CourierAvailability{Windows: windows, Exceptions: exceptions}, nil
+}
+
+// ListCourierReviews показывает диспетчеру все отзывы курьера, включая
+// скрытые, если их запросили.
+func (s *Service) ListCourierReviews(ctx context.Context, filter models.ReviewFilter) ([]*models.Review, int, error) {
+	if _, err := s.repo.Courier.GetByID(ctx, filter.CourierID); err != nil {
+		return nil, 0, err
+	}
+
+	filter.Page = normalizePage(filter.Page)
+	return s.repo.Review.ListFiltered(ctx, filter)
+}
+
+func (s *Service) ListAvailableCouriers(ctx context.Context, at time.Time) ([]*models.Courier, error) {
+	couriers, err := s.repo.Availability.ListAvailableCouriers(ctx, at.In(s.location))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, courier := range couriers {
+		courier.Presence = courier.PresenceAt(now, s.idleThreshold)
+	}
+
+	return couriers, nil
+}
+
+// syncShift открывает или закрывает смену, чтобы ручная смена статуса
+// администратором совпадала с тем, как курьеров отбирает назначение.
+// Смену с недоставленными заказами или предложениями без ответа закрыть
+// нельзя, как и самому курьеру.
+func (s *Service) syncShift(ctx context.Context, courierID int, active bool) error {
+	shift, err := s.repo.Shift.GetOpen(ctx, courierID)
+	if err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+		return err
+	}
+
+	switch {
+	case active && shift == nil:
+		_, err = s.repo.Shift.Start(ctx, courierID)
+	case !active && shift != nil:
+		_, err = s.repo.Shift.EndWithoutOrders(ctx, shift.ID, models.ShiftEndByAdmin)
+		if errors.Is(err, interfaces.ErrConflict) {
+			return ErrCourierHasOrders
+		}
+	}
+
+	return err
+}
+
+func (s *Service) DeleteCourier(ctx context.Context, id int) error {
+	courier, err := s.repo.Courier.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if courier.CurrentOrderID != nil {
+		return ErrCourierHasHistory
+	}
+
+	_, total, err := s.repo.OrderAssignment.ListFiltered(ctx, models.AssignmentFilter{
+		CourierID: id,
+		Page:      models.Page{Limit: 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check courier assignments: %v", err)
+	}
+
+	if total > 0 {
+		return ErrCourierHasHistory
+	}
+
+	if err := s.repo.Courier.DeleteByID(ctx, id); err != nil {
+		return err
+	}
+
+	s.log.Info("Courier deleted by admin", "courierID", id)
+
+	return nil
+}
+
+func (s *Service) GetOrderDetails(ctx context.Context, id int) (*OrderDetails, error) {
+	order, err := s.repo.Order.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, _, err := s.repo.OrderAssignment.ListFiltered(ctx, models.AssignmentFilter{
+		OrderID: id,
+		Page:    models.Page{Limit: MaxPageLimit},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order assignments: %v", err)
+	}
+
+	return &OrderDetails{
+		Order:       order,
+		Assignments: assignments,
+	}, nil
+}
+
+func (s *Service) ListAssignments(ctx context.Context, filter models.AssignmentFilter) ([]*models.OrderAssignment, int, error) {
+	filter.Page = normalizePage(filter.Page)
+	return s.repo.OrderAssignment.ListFiltered(ctx, filter)
+}
+
+func normalizePage(page models.Page) models.Page {
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	return page
+}
@@ -0,0 +1,263 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const availabilityReminderLookback = 15 * time.Minute
+
+var ErrInvalidAvailability = errors.New("invalid availability window")
+
+func (s *Service) UpdateLocation(location *time.Location) {
+	s.location = location
+}
+
+// UpdateHoldLead задаёт, за сколько до доставки заказ перестаёт ждать
+// курьера с окном и уходит любому на смене.
+func (s *Service) UpdateHoldLead(lead time.Duration) {
+	s.holdLead = lead
+}
+
+// Now — текущее время в часовом поясе расписания курьеров.
+func (s *Service) Now() time.Time {
+	return time.Now().In(s.location)
+}
+
+func (s *Service) GetAvailability(ctx context.Context, chatID int64) (*models.CourierAvailability, error) {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	windows, err := s.repo.Availability.ListWindows(ctx, courier.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := s.repo.Availability.ListExceptions(ctx, courier.ID, s.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CourierAvailability{Windows: windows, Exceptions: exceptions}, nil
+}
+
+func (s *Service) AddAvailabilityWindow(ctx context.Context, chatID int64, weekday time.Weekday, startMinute, endMinute int) error {
+	if weekday < time.Sunday || weekday > time.Saturday || !validMinuteRange(startMinute, endMinute) {
+		return ErrInvalidAvailability
+	}
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	window := &models.AvailabilityWindow{
+		CourierID:   courier.ID,
+		Weekday:     weekday,
+		StartMinute: startMinute,
+		EndMinute:   endMinute,
+	}
+
+	if err := s.repo.Availability.CreateWindow(ctx, window); err != nil {
+		return err
+	}
+
+	s.log.Info("Availability window added", "courierID", courier.ID, "weekday", weekday, "window", window.String())
+
+	return nil
+}
+
+func (s *Service) RemoveAvailabilityWindow(ctx context.Context, chatID int64, id int) error {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	return s.repo.Availability.DeleteWindow(ctx, courier.ID, id)
+}
+
+// AddAvailabilityException: available == false — выходной на весь день,
+// иначе на эту дату действует окно startMinute–endMinute вместо недельных.
+func (s *Service) AddAvailabilityException(ctx context.Context, chatID int64, day time.Time, available bool, startMinute, endMinute int) error {
+	if available && !validMinuteRange(startMinute, endMinute) {
+		return ErrInvalidAvailability
+	}
+
+	today := s.Now().Format(time.DateOnly)
+	if day.Format(time.DateOnly) < today {
+		return ErrInvalidAvailability
+	}
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	exception := &models.AvailabilityException{
+		CourierID: courier.ID,
+		Day:       day,
+		Available: available,
+	}
+	if available {
+		exception.StartMinute = &startMinute
+		exception.EndMinute = &endMinute
+	}
+
+	if err := s.repo.Availability.CreateException(ctx, exception); err != nil {
+		return err
+	}
+
+	s.log.Info("Availability exception added", "courierID", courier.ID, "day", day.Format(time.DateOnly), "available", available)
+
+	return nil
+}
+
+func (s *Service) RemoveAvailabilityException(ctx context.Context, chatID int64, id int) error {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	return s.repo.Availability.DeleteException(ctx, courier.ID, id)
+}
+
+func validMinuteRange(startMinute, endMinute int) bool {
+	return startMinute >= 0 && endMinute <= 24*60 && startMinute < endMinute
+}
+
+// scheduledCandidates выбирает кандидатов для заказа на будущее время.
+// Курьеры с окном на время доставки идут первыми, остальные на смене —
+// за ними: расписание заполнено не у всех. Если окно есть только у тех,
+// кто ещё не на смене, и до доставки больше holdLead, возвращается время,
+// до которого подбор откладывается.
+func (s *Service) scheduledCandidates(ctx context.Context, order *models.Order, onShift []*models.Courier) ([]*models.Courier, *time.Time) {
+	if order.DeliveryDate == nil || !order.DeliveryDate.After(time.Now()) {
+		return onShift, nil
+	}
+
+	available, err := s.repo.Availability.ListAvailableCouriers(ctx, order.DeliveryDate.In(s.location))
+	if err != nil {
+		s.log.Warn("Failed to list couriers available for delivery date", "orderID", order.ID, "error", err)
+		return onShift, nil
+	}
+
+	if len(available) == 0 {
+		return onShift, nil
+	}
+
+	scheduled := make(map[int]bool, len(available))
+	for _, courier := range available {
+		scheduled[courier.ID] = true
+	}
+
+	var first, rest []*models.Courier
+	for _, courier := range onShift {
+		if scheduled[courier.ID] {
+			first = append(first, courier)
+		} else {
+			rest = append(rest, courier)
+		}
+	}
+
+	if len(first) > 0 {
+		return append(first, rest...), nil
+	}
+
+	releaseAt := order.DeliveryDate.Add(-s.holdLead)
+	if time.Now().Before(releaseAt) {
+		return nil, &releaseAt
+	}
+
+	return onShift, nil
+}
+
+// RunHeldAssignments повторяет подбор для отложенных заказов: как только
+// курьер с окном выходит на смену или подходит время, заказ уходит в работу.
+func (s *Service) RunHeldAssignments(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTracked(ctx, "held-assignments", s.retryHeldAssignments)
+		}
+	}
+}
+
+func (s *Service) retryHeldAssignments(ctx context.Context) {
+	holds, err := s.repo.OrderAssignment.ListHolds(ctx)
+	if err != nil {
+		s.log.Error("Failed to list held orders", "error", err)
+		return
+	}
+
+	for _, hold := range holds {
+		ctx, span := tracing.Start(ctx, "assignment.retryHeldAssignment", tracing.OrderID(hold.OrderID))
+
+		_, err := s.findAndAssignCourier(ctx, hold.OrderID)
+		tracing.End(span, err)
+
+		if err != nil {
+			s.log.Error("Failed to assign held order", "orderID", hold.OrderID, "error", err)
+		}
+	}
+}
+
+// RunAvailabilityReminders напоминает начать смену, когда у курьера
+// началось окно по расписанию, а он ещё не на смене.
+func (s *Service) RunAvailabilityReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTracked(ctx, "availability-reminders", s.sendAvailabilityReminders)
+		}
+	}
+}
+
+func (s *Service) sendAvailabilityReminders(ctx context.Context) {
+	now := s.Now()
+
+	reminders, err := s.repo.Availability.ListDueReminders(ctx, now, availabilityReminderLookback)
+	if err != nil {
+		s.log.Error("Failed to list availability reminders", "error", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		claimed, err := s.repo.Availability.MarkReminded(ctx, reminder, now)
+		if err != nil {
+			s.log.Error("Failed to mark availability reminder", "courierID", reminder.CourierID, "error", err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		ctx, span := tracing.Start(ctx, "assignment.sendAvailabilityReminder", attribute.Int("courier.id", reminder.CourierID))
+
+		message := s.localizer(ctx, reminder.ChatID).T(
+			"availability.reminder",
+			models.FormatMinutes(reminder.StartMinute),
+			models.FormatMinutes(reminder.EndMinute),
+		)
+		err = s.sendSimpleNotification(ctx, reminder.ChatID, models.NotifyReminder, message)
+		tracing.End(span, err)
+	}
+}
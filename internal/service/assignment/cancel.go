@@ -0,0 +1,169 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrOrderAlreadyCancelled = errors.New("order already cancelled")
+	ErrOrderAlreadyDelivered = errors.New("order already delivered")
+	ErrOrderNotAssignedToYou = errors.New("order is not assigned to this courier")
+)
+
+func (s *Service) CancelOrder(ctx context.Context, orderID int, source models.CancelSource, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.CancelOrder", tracing.OrderID(orderID), attribute.String("order.cancel_source", string(source)))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Cancelling order", "orderID", orderID, "source", source, "reason", reason)
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order %d: %v", orderID, err)
+	}
+
+	if order.IsCancelled() {
+		return ErrOrderAlreadyCancelled
+	}
+
+	if order.IsReceived {
+		return ErrOrderAlreadyDelivered
+	}
+
+	live, cancelled, err := s.repo.Order.Cancel(ctx, orderID, source, reason)
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %v", err)
+	}
+
+	if !cancelled {
+		return ErrOrderAlreadyCancelled
+	}
+
+	metrics.AssignmentOutcomes.WithLabelValues(string(models.ResponseStatusCancelled)).Add(float64(len(live)))
+
+	if err := s.repo.Courier.ClearCurrentOrder(ctx, orderID); err != nil {
+		s.log.Error("Failed to clear courier current order", "orderID", orderID, "error", err)
+	}
+
+	s.removeOrderKeyboards(ctx, orderID)
+
+	for _, assignment := range live {
+		courier, err := s.repo.Courier.GetByID(ctx, assignment.CourierID)
+		if err != nil {
+			s.log.Error("Failed to get courier for cancelled assignment", "courierID", assignment.CourierID, "error", err)
+			continue
+		}
+
+		isAssignedCourier := order.CourierID != nil && *order.CourierID == courier.ID
+		tr := courierLocalizer(courier)
+
+		switch {
+		case isAssignedCourier && source == models.CancelSourceCourier:
+			s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, tr.T("cancel.thanks", orderID))
+		case isAssignedCourier:
+			message := tr.T("cancel.notice", orderID, tr.T("cancel.source."+string(source)))
+			if reason != "" {
+				message += tr.T("cancel.reason", reason)
+			}
+			s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, message)
+		default:
+			s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, tr.T("cancel.offer_revoked", orderID))
+		}
+	}
+
+	s.log.Info("Order cancelled", "orderID", orderID, "source", source, "liveAssignments", len(live))
+
+	return nil
+}
+
+func (s *Service) CancelOrderByCourier(ctx context.Context, chatID int64, orderID int, reason string) error {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order %d: %v", orderID, err)
+	}
+
+	if order.CourierID == nil || *order.CourierID != courier.ID {
+		return ErrOrderNotAssignedToYou
+	}
+
+	return s.CancelOrder(ctx, orderID, models.CancelSourceCourier, reason)
+}
+
+// TrackOrderMessage запоминает сообщение заказа. Если у заказа в этом чате
+// уже было сообщение того же вида, с него снимается клавиатура, чтобы в чате
+// оставалась одна рабочая карточка. Прежнее сообщение с телефоном клиента
+// удаляется целиком.
+func (s *Service) TrackOrderMessage(ctx context.Context, orderID int, chatID int64, messageID int, kind models.OrderMessageKind) {
+	previous, err := s.repo.OrderMessage.Get(ctx, orderID, chatID, kind)
+	if err != nil && !errors.Is(err, interfaces.ErrNotFound) {
+		s.log.Error("Failed to get previous order message", "orderID", orderID, "chatID", chatID, "error", err)
+	}
+
+	message := &models.OrderMessage{
+		OrderID:   orderID,
+		ChatID:    chatID,
+		MessageID: messageID,
+		Kind:      kind,
+	}
+
+	if err := s.repo.OrderMessage.Save(ctx, message); err != nil {
+		s.log.Error("Failed to track order message", "orderID", orderID, "chatID", chatID, "error", err)
+		return
+	}
+
+	if previous != nil && previous.MessageID != messageID {
+		s.closeOrderMessage(ctx, previous)
+	}
+}
+
+// GetOrderCard возвращает текущую карточку заказа в чате курьера.
+func (s *Service) GetOrderCard(ctx context.Context, orderID int, chatID int64) (*models.OrderMessage, error) {
+	return s.repo.OrderMessage.Get(ctx, orderID, chatID, models.OrderMessageDelivery)
+}
+
+func (s *Service) removeOrderKeyboards(ctx context.Context, orderID int) {
+	messages, err := s.repo.OrderMessage.ListByOrderID(ctx, orderID)
+	if err != nil {
+		s.log.Error("Failed to list order messages", "orderID", orderID, "error", err)
+		return
+	}
+
+	for _, message := range messages {
+		s.closeOrderMessage(ctx, message)
+	}
+
+	if err := s.repo.OrderMessage.DeleteByOrderID(ctx, orderID); err != nil {
+		s.log.Error("Failed to delete order messages", "orderID", orderID, "error", err)
+	}
+}
+
+func (s *Service) closeOrderMessage(ctx context.Context, message *models.OrderMessage) {
+	if message.Kind == models.OrderMessageContact {
+		s.deleteMessage(ctx, message)
+		return
+	}
+
+	s.removeKeyboard(ctx, message)
+}
+
+func (s *Service) removeKeyboard(ctx context.Context, message *models.OrderMessage) {
+	emptyKeyboard := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(message.ChatID, message.MessageID, emptyKeyboard)
+	if _, err := s.send(ctx, "editMessageReplyMarkup", message.ChatID, edit); err != nil {
+		s.log.Warn("Failed to remove order keyboard", "orderID", message.OrderID, "messageID", message.MessageID, "error", err)
+	}
+}
@@ -0,0 +1,174 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrNoCustomerPhone = errors.New("order has no customer phone")
+
+// SendCustomerMessage передаёт клиенту сообщение курьера и сохраняет его в
+// заказе. Сообщение, которое канал не доставил, тоже сохраняется.
+func (s *Service) SendCustomerMessage(ctx context.Context, chatID int64, orderID int, template, text string) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.SendCustomerMessage", tracing.OrderID(orderID), tracing.ChatID(chatID), attribute.String("contact.template", template))
+	defer func() { tracing.End(span, err) }()
+
+	courier, order, err := s.contactableOrder(ctx, chatID, orderID)
+	if err != nil {
+		return err
+	}
+
+	sendErr := s.customers.Send(ctx, order, text)
+
+	message := &models.ContactMessage{
+		OrderID:   orderID,
+		CourierID: &courier.ID,
+		Direction: models.ContactToCustomer,
+		Template:  template,
+		Text:      text,
+		Delivered: sendErr == nil,
+	}
+
+	if err := s.repo.ContactMessage.Create(ctx, message); err != nil {
+		s.log.Error("Failed to store contact message", "orderID", orderID, "error", err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to send message to customer: %v", sendErr)
+	}
+
+	s.log.Info("Courier message relayed to customer", "orderID", orderID, "courierID", courier.ID, "template", template)
+	return nil
+}
+
+// RelayCustomerMessage передаёт курьеру ответ клиента и сохраняет его в
+// заказе. Delivered в результате показывает, дошло ли сообщение до курьера.
+func (s *Service) RelayCustomerMessage(ctx context.Context, orderID int, text string) (message *models.ContactMessage, err error) {
+	ctx, span := tracing.Start(ctx, "assignment.RelayCustomerMessage", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case order.IsCancelled():
+		return nil, ErrOrderAlreadyCancelled
+	case order.IsReceived:
+		return nil, ErrOrderAlreadyDelivered
+	case order.CourierID == nil:
+		return nil, ErrOrderNotAssigned
+	}
+
+	courier, err := s.repo.Courier.GetByID(ctx, *order.CourierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	tr := courierLocalizer(courier)
+	sendErr := s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, tr.T("contact.incoming", orderID, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text)))
+
+	message = &models.ContactMessage{
+		OrderID:   orderID,
+		CourierID: &courier.ID,
+		Direction: models.ContactToCourier,
+		Text:      text,
+		Delivered: sendErr == nil,
+	}
+
+	if err := s.repo.ContactMessage.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to store contact message: %v", err)
+	}
+
+	return message, nil
+}
+
+func (s *Service) ListContactMessages(ctx context.Context, orderID int) ([]*models.ContactMessage, error) {
+	if _, err := s.repo.Order.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ContactMessage.ListByOrderID(ctx, orderID)
+}
+
+// RevealCustomerPhone отдаёт курьеру телефон клиента, пока заказ у него в
+// работе. Сообщение с номером бот регистрирует как OrderMessageContact, и
+// оно удаляется, когда заказ закрывают.
+func (s *Service) RevealCustomerPhone(ctx context.Context, chatID int64, orderID int) (string, error) {
+	_, order, err := s.contactableOrder(ctx, chatID, orderID)
+	if err != nil {
+		return "", err
+	}
+
+	if order.PhoneNumber == "" {
+		return "", ErrNoCustomerPhone
+	}
+
+	s.log.Info("Customer phone revealed to courier", "orderID", orderID, "chatID", chatID)
+	return order.PhoneNumber, nil
+}
+
+// contactableOrder проверяет, что заказ назначен курьеру из чата и ещё не
+// закрыт: только тогда курьер может связаться с клиентом.
+func (s *Service) contactableOrder(ctx context.Context, chatID int64, orderID int) (*models.Courier, *models.Order, error) {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get order %d: %v", orderID, err)
+	}
+
+	switch {
+	case order.IsCancelled():
+		return nil, nil, ErrOrderAlreadyCancelled
+	case order.IsReceived:
+		return nil, nil, ErrOrderAlreadyDelivered
+	case order.CourierID == nil || *order.CourierID != courier.ID:
+		return nil, nil, ErrOrderNotAssignedToYou
+	}
+
+	return courier, order, nil
+}
+
+// hideCustomerPhone удаляет из чатов сообщения с телефоном клиента.
+func (s *Service) hideCustomerPhone(ctx context.Context, orderID int) {
+	messages, err := s.repo.OrderMessage.ListByOrderID(ctx, orderID)
+	if err != nil {
+		s.log.Error("Failed to list order messages", "orderID", orderID, "error", err)
+		return
+	}
+
+	for _, message := range messages {
+		if message.Kind != models.OrderMessageContact {
+			continue
+		}
+
+		s.deleteMessage(ctx, message)
+
+		if err := s.repo.OrderMessage.Delete(ctx, message.ID); err != nil {
+			s.log.Error("Failed to delete order message", "orderID", orderID, "messageID", message.MessageID, "error", err)
+		}
+	}
+}
+
+// deleteMessage идёт через Request: на deleteMessage Telegram отвечает true,
+// а не сообщением, и Send не смог бы разобрать ответ.
+func (s *Service) deleteMessage(ctx context.Context, message *models.OrderMessage) {
+	_, span := tracing.Start(ctx, "telegram.deleteMessage", tracing.ChatID(message.ChatID))
+	_, err := s.botAPI.Request(tgbotapi.NewDeleteMessage(message.ChatID, message.MessageID))
+	tracing.End(span, err)
+
+	if err != nil {
+		s.log.Warn("Failed to delete order message", "orderID", message.OrderID, "messageID", message.MessageID, "error", err)
+	}
+}
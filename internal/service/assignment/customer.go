@@ -0,0 +1,136 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+func (s *Service) UpdateCustomerNotifications(notifications CustomerNotifications) {
+	s.customerNotifications = notifications
+}
+
+// SaveCustomerContact запоминает адреса клиента из webhook магазина.
+func (s *Service) SaveCustomerContact(ctx context.Context, contact *models.CustomerContact) error {
+	return s.repo.CustomerContact.Save(ctx, contact)
+}
+
+// ReportOrderProgress сообщает клиенту о шаге доставки, который отметил
+// курьер. Повторное нажатие той же кнопки второе уведомление не шлёт.
+func (s *Service) ReportOrderProgress(ctx context.Context, orderID int, event models.CustomerEvent) {
+	s.notifyCustomer(ctx, orderID, event)
+}
+
+func (s *Service) ListCustomerNotifications(ctx context.Context, orderID int) ([]*models.CustomerNotification, error) {
+	if _, err := s.repo.Order.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CustomerNotification.ListByOrderID(ctx, orderID)
+}
+
+// notifyCustomer отправляет уведомление в фоне, чтобы медленный шлюз не
+// задерживал ответ курьеру.
+func (s *Service) notifyCustomer(ctx context.Context, orderID int, event models.CustomerEvent) {
+	notifications := s.customerNotifications
+	if len(notifications.Notifiers) == 0 || !notifications.Events[event] {
+		return
+	}
+
+	s.spawner.Go(ctx, "notify-customer", func(ctx context.Context) {
+		s.sendCustomerNotification(ctx, notifications, orderID, event)
+	})
+}
+
+func (s *Service) sendCustomerNotification(ctx context.Context, notifications CustomerNotifications, orderID int, event models.CustomerEvent) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		s.log.Error("Failed to get order for customer notification", "orderID", orderID, "error", err)
+		return
+	}
+
+	data := customer.TemplateData{
+		OrderID:      order.ID,
+		CustomerName: order.Name,
+		Address:      order.FullAddress(),
+	}
+
+	if event == models.CustomerEventDelivered && s.reviews.Links != nil {
+		data.ReviewURL = s.reviews.Links.URL(order.ID)
+	}
+
+	if order.CourierID != nil {
+		courier, err := s.repo.Courier.GetByID(ctx, *order.CourierID)
+		if err != nil {
+			s.log.Warn("Failed to get courier for customer notification", "orderID", orderID, "error", err)
+		} else {
+			data.CourierName = courier.Name
+		}
+	}
+
+	message, err := notifications.Templates.Render(event, data)
+	if err != nil {
+		s.log.Error("Failed to render customer notification", "orderID", orderID, "event", event, "error", err)
+		return
+	}
+
+	recipient := customer.Recipient{
+		OrderID: order.ID,
+		Name:    order.Name,
+		Phone:   order.PhoneNumber,
+	}
+
+	contact, err := s.repo.CustomerContact.GetByOrderID(ctx, orderID)
+	switch {
+	case err == nil:
+		recipient.Email = contact.Email
+		if contact.TelegramChatID != nil {
+			recipient.TelegramChatID = *contact.TelegramChatID
+		}
+	case !errors.Is(err, interfaces.ErrNotFound):
+		s.log.Warn("Failed to get customer contact", "orderID", orderID, "error", err)
+	}
+
+	for _, notifier := range notifications.Notifiers {
+		record := &models.CustomerNotification{
+			OrderID: orderID,
+			Event:   event,
+			Channel: notifier.Channel(),
+			Text:    message.Text,
+		}
+
+		claimed, err := s.repo.CustomerNotification.Claim(ctx, record)
+		if err != nil {
+			s.log.Error("Failed to record customer notification", "orderID", orderID, "event", event, "channel", record.Channel, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		status := models.CustomerNotificationSent
+		errorText := ""
+
+		if err := notifier.Notify(ctx, recipient, message); err != nil {
+			status = models.CustomerNotificationFailed
+			if errors.Is(err, customer.ErrNoContact) {
+				status = models.CustomerNotificationSkipped
+			}
+			errorText = err.Error()
+		}
+
+		metrics.CustomerNotifications.WithLabelValues(record.Channel, string(status)).Inc()
+
+		if status == models.CustomerNotificationFailed {
+			s.log.Warn("Failed to notify customer", "orderID", orderID, "event", event, "channel", record.Channel, "error", errorText)
+		}
+
+		if err := s.repo.CustomerNotification.Finish(ctx, record.ID, status, errorText); err != nil {
+			s.log.Error("Failed to update customer notification", "orderID", orderID, "event", event, "channel", record.Channel, "error", err)
+		}
+	}
+}
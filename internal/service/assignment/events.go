@@ -0,0 +1,136 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+)
+
+func (s *Service) HandleOrderCancelled(ctx context.Context, orderID int, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.HandleOrderCancelled", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Handling order cancellation from shop", "orderID", orderID, "reason", reason)
+
+	err = s.CancelOrder(ctx, orderID, models.CancelSourceShop, reason)
+	if errors.Is(err, ErrOrderAlreadyCancelled) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Service) HandleOrderUpdated(ctx context.Context, orderID int, changes []string) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.HandleOrderUpdated", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Handling order update from shop", "orderID", orderID, "changes", changes)
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order %d: %v", orderID, err)
+	}
+
+	if order.IsCancelled() {
+		s.log.Debug("Skipping update for cancelled order", "orderID", orderID)
+		return nil
+	}
+
+	if order.CourierID != nil {
+		if order.IsReceived {
+			return nil
+		}
+
+		courier, err := s.repo.Courier.GetByID(ctx, *order.CourierID)
+		if err != nil {
+			return fmt.Errorf("failed to get assigned courier: %v", err)
+		}
+
+		tr := courierLocalizer(courier)
+
+		message := s.formatDeliveryMessage(tr, order, s.formatOrderChangedTitle(tr, orderID, changes))
+		message.WriteString(tr.T("delivery.controls"))
+
+		return s.sendNotificationWithDeliveryKeyboard(ctx, tr, courier.ChatID, message.String(), orderID, order)
+	}
+
+	assignment, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
+	if err != nil || assignment.CourierResponseStatus != models.ResponseStatusWaiting {
+		return nil
+	}
+
+	courier, err := s.repo.Courier.GetByID(ctx, assignment.CourierID)
+	if err != nil {
+		return fmt.Errorf("failed to get offered courier: %v", err)
+	}
+
+	tr := courierLocalizer(courier)
+
+	message := s.formatDeliveryMessage(tr, order, s.formatOrderChangedTitle(tr, orderID, changes))
+	message.WriteString(tr.T("offer.prompt"))
+
+	return s.sendNotificationWithKeyboard(ctx, tr, courier.ChatID, orderID, message.String())
+}
+
+func (s *Service) HandleOrderAssembled(ctx context.Context, orderID int) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.HandleOrderAssembled", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Handling order assembled event", "orderID", orderID)
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order %d: %v", orderID, err)
+	}
+
+	if order.IsCancelled() {
+		s.log.Debug("Skipping assembled event for cancelled order", "orderID", orderID)
+		return nil
+	}
+
+	if order.CourierID != nil {
+		courier, err := s.repo.Courier.GetByID(ctx, *order.CourierID)
+		if err != nil {
+			return fmt.Errorf("failed to get assigned courier: %v", err)
+		}
+
+		return s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrderReady, courierLocalizer(courier).T("order.assembled", orderID))
+	}
+
+	assignment, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
+	if err == nil && assignment.CourierResponseStatus == models.ResponseStatusWaiting {
+		s.log.Debug("Order already has a pending offer", "orderID", orderID)
+		return nil
+	}
+
+	return s.ProcessNewOrder(ctx, orderID)
+}
+
+// orderChanges — поля заказа, о которых магазин сообщает в событии
+// изменения. Неизвестные поля в заголовок не попадают.
+var orderChanges = map[string]bool{
+	"address":       true,
+	"delivery_date": true,
+	"contacts":      true,
+}
+
+func (s *Service) formatOrderChangedTitle(tr i18n.Localizer, orderID int, changes []string) string {
+	labels := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if orderChanges[change] {
+			labels = append(labels, tr.T("order_change."+change))
+		}
+	}
+
+	title := tr.T("order.changed", orderID)
+	if len(labels) > 0 {
+		title += ": " + strings.Join(labels, ", ")
+	}
+
+	return title
+}
@@ -1,5 +1,12 @@
 package assignment
 
+import (
+	"context"
+
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
 type Button struct {
 	Text string
 	Data string
@@ -10,3 +17,18 @@ type AssignmentResult struct {
 	CourierID    int
 	ErrorMessage string
 }
+
+// CustomerNotifier доставляет уведомление клиенту по одному каналу. Если у
+// клиента нет адреса для этого канала, Notify возвращает customer.ErrNoContact.
+type CustomerNotifier interface {
+	Channel() string
+	Notify(ctx context.Context, recipient customer.Recipient, message customer.Message) error
+}
+
+// CustomerNotifications — настройки уведомлений клиента для развёртывания.
+// Без каналов или без включённых событий клиенту ничего не отправляется.
+type CustomerNotifications struct {
+	Notifiers []CustomerNotifier
+	Templates *customer.Templates
+	Events    map[models.CustomerEvent]bool
+}
@@ -0,0 +1,161 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrOrderAlreadyAssigned = errors.New("order already assigned to a courier")
+	ErrOrderNotAssigned     = errors.New("order is not assigned to a courier")
+)
+
+func (s *Service) AssignManually(ctx context.Context, orderID, courierID int) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.AssignManually", tracing.OrderID(orderID), attribute.Int("courier.id", courierID))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Manual assignment of order", "orderID", orderID, "courierID", courierID)
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case order.IsCancelled():
+		return ErrOrderAlreadyCancelled
+	case order.IsReceived:
+		return ErrOrderAlreadyDelivered
+	case order.CourierID != nil:
+		return ErrOrderAlreadyAssigned
+	}
+
+	courier, err := s.repo.Courier.GetByID(ctx, courierID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.OrderAssignment.CancelLive(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke pending offers: %v", err)
+	}
+	metrics.AssignmentOutcomes.WithLabelValues(string(models.ResponseStatusCancelled)).Add(float64(len(revoked)))
+
+	s.removeOrderKeyboards(ctx, orderID)
+
+	for _, offer := range revoked {
+		if offer.CourierID == courierID {
+			continue
+		}
+
+		offered, err := s.repo.Courier.GetByID(ctx, offer.CourierID)
+		if err != nil {
+			s.log.Error("Failed to get courier for revoked offer", "courierID", offer.CourierID, "error", err)
+			continue
+		}
+
+		s.sendSimpleNotification(ctx, offered.ChatID, models.NotifyOrder, courierLocalizer(offered).T("manual.offer_revoked", orderID))
+	}
+
+	now := time.Now()
+	assignment := &models.OrderAssignment{
+		OrderID:               orderID,
+		CourierID:             courierID,
+		AssignedAt:            now,
+		ExpiredAt:             now,
+		CourierResponseStatus: models.ResponseStatusAccepted,
+	}
+
+	if err := s.repo.OrderAssignment.Create(ctx, assignment); err != nil {
+		return fmt.Errorf("failed to create assignment: %v", err)
+	}
+
+	assigned, err := s.repo.Order.UpdateCourierID(ctx, orderID, courierID)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %v", err)
+	}
+
+	// Заказ отменили, пока назначение создавалось: отзываем и его.
+	if !assigned {
+		if _, err := s.repo.OrderAssignment.CancelLive(ctx, orderID); err != nil {
+			s.log.Error("Failed to revoke assignment of cancelled order", "orderID", orderID, "error", err)
+		}
+		return ErrOrderAlreadyCancelled
+	}
+
+	if err := s.repo.Courier.UpdateCurrentOrderID(ctx, courier.ChatID, orderID); err != nil {
+		s.log.Error("Failed to update courier current order", "courierID", courierID, "error", err)
+	}
+
+	s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, courierLocalizer(courier).T("manual.assigned", orderID))
+	s.sendDeliveryDetails(ctx, courier.ChatID, orderID)
+	s.notifyCustomer(ctx, orderID, models.CustomerEventAccepted)
+
+	return nil
+}
+
+func (s *Service) Unassign(ctx context.Context, orderID int, reassign bool) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.Unassign", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
+	s.log.Info("Manual unassignment of order", "orderID", orderID, "reassign", reassign)
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case order.IsCancelled():
+		return ErrOrderAlreadyCancelled
+	case order.IsReceived:
+		return ErrOrderAlreadyDelivered
+	case order.CourierID == nil:
+		return ErrOrderNotAssigned
+	}
+
+	if err := s.repo.Order.ClearCourierID(ctx, orderID); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.OrderAssignment.CancelLive(ctx, orderID)
+	if err != nil {
+		s.log.Error("Failed to cancel live assignments", "orderID", orderID, "error", err)
+	}
+	metrics.AssignmentOutcomes.WithLabelValues(string(models.ResponseStatusCancelled)).Add(float64(len(revoked)))
+
+	if err := s.repo.Courier.ClearCurrentOrder(ctx, orderID); err != nil {
+		s.log.Error("Failed to clear courier current order", "orderID", orderID, "error", err)
+	}
+
+	s.removeOrderKeyboards(ctx, orderID)
+
+	courier, err := s.repo.Courier.GetByID(ctx, *order.CourierID)
+	if err != nil {
+		s.log.Error("Failed to get unassigned courier", "courierID", *order.CourierID, "error", err)
+	} else {
+		s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyOrder, courierLocalizer(courier).T("manual.unassigned", orderID))
+	}
+
+	if !reassign {
+		return nil
+	}
+
+	result, err := s.findAndAssignCourier(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to reassign order: %v", err)
+	}
+
+	if !result.Success {
+		s.log.Warn("No courier found for order", "orderID", orderID, "errorMessage", result.ErrorMessage)
+	}
+
+	return nil
+}
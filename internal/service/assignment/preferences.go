@@ -0,0 +1,158 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrUnknownNotificationSetting = errors.New("unknown notification setting")
+
+func (s *Service) GetNotificationSettings(ctx context.Context, chatID int64) (*models.NotificationSettings, error) {
+	return s.repo.NotificationSettings.GetByChatID(ctx, chatID)
+}
+
+func (s *Service) ToggleNotificationSetting(ctx context.Context, chatID int64, setting models.NotificationSetting) (*models.NotificationSettings, error) {
+	settings, err := s.repo.NotificationSettings.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.Toggle(setting) {
+		return nil, ErrUnknownNotificationSetting
+	}
+
+	if err := s.repo.NotificationSettings.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Notification setting changed", "chatID", chatID, "setting", setting, "enabled", settings.Enabled(setting))
+
+	return settings, nil
+}
+
+// notificationSettings возвращает настройки получателя. Если прочитать их
+// не удалось, действуют настройки по умолчанию: сбой базы не должен
+// глушить уведомления.
+func (s *Service) notificationSettings(ctx context.Context, chatID int64) *models.NotificationSettings {
+	settings, err := s.repo.NotificationSettings.GetByChatID(ctx, chatID)
+	if err != nil {
+		s.log.Warn("Failed to get notification settings", "chatID", chatID, "error", err)
+		return models.DefaultNotificationSettings(0)
+	}
+
+	return settings
+}
+
+// silentOffer решает, слать ли предложение заказа без звука. Смена
+// проверяется только когда курьер просил тишину; если проверить не
+// удалось, предложение уходит со звуком.
+func (s *Service) silentOffer(ctx context.Context, chatID int64) bool {
+	settings := s.notificationSettings(ctx, chatID)
+	if !settings.Silent(models.NotifyOffer, false) {
+		return false
+	}
+
+	_, err := s.repo.Shift.GetOpen(ctx, settings.CourierID)
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, interfaces.ErrNotFound):
+		return true
+	default:
+		s.log.Warn("Failed to check shift for offer sound", "chatID", chatID, "error", err)
+		return false
+	}
+}
+
+func (s *Service) UpdateDailySummaryTime(at time.Duration) {
+	s.dailySummaryAt = at
+}
+
+// RunDailySummaries раз в день, начиная с dailySummaryAt по местному
+// времени, присылает итоги дня курьерам, которые их включили.
+func (s *Service) RunDailySummaries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTracked(ctx, "daily-summaries", s.sendDailySummaries)
+		}
+	}
+}
+
+func (s *Service) sendDailySummaries(ctx context.Context) {
+	now := s.Now()
+	day := models.StatsPeriodToday.Since(now)
+	if now.Before(day.Add(s.dailySummaryAt)) {
+		return
+	}
+
+	couriers, err := s.repo.NotificationSettings.ListDailySummaryDue(ctx, day)
+	if err != nil {
+		s.log.Error("Failed to list daily summary recipients", "error", err)
+		return
+	}
+
+	for _, courier := range couriers {
+		claimed, err := s.repo.NotificationSettings.MarkDailySummarySent(ctx, courier.ID, day)
+		if err != nil {
+			s.log.Error("Failed to mark daily summary", "courierID", courier.ID, "error", err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		ctx, span := tracing.Start(ctx, "assignment.sendDailySummary", attribute.Int("courier.id", courier.ID))
+
+		stats, err := s.repo.CourierStats.GetByCourierID(ctx, courier.ID, day)
+		if err != nil {
+			s.log.Error("Failed to get daily stats", "courierID", courier.ID, "error", err)
+			tracing.End(span, err)
+			continue
+		}
+
+		// В день без работы итоги не нужны.
+		if stats.Deliveries == 0 && stats.Offers == 0 {
+			tracing.End(span, nil)
+			continue
+		}
+
+		message := s.formatDailySummary(courierLocalizer(courier), day, stats)
+		err = s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyDailySummary, message)
+		tracing.End(span, err)
+	}
+}
+
+func (s *Service) formatDailySummary(tr i18n.Localizer, day time.Time, stats *models.CourierStats) string {
+	var builder strings.Builder
+
+	builder.WriteString(tr.T("daily_summary.title", day.Format("02.01.2006")))
+	builder.WriteString(tr.N("stats.deliveries", stats.Deliveries))
+	builder.WriteString(tr.T("stats.earnings", stats.Earnings))
+
+	if stats.Offers > 0 {
+		builder.WriteString(tr.T("stats.acceptance", stats.Accepted, stats.Offers, stats.AcceptanceRate()))
+	}
+
+	if stats.WithDeadline > 0 {
+		builder.WriteString(tr.T("stats.on_time", stats.OnTimeRate()))
+	}
+
+	builder.WriteString(tr.T("daily_summary.footer"))
+
+	return builder.String()
+}
@@ -0,0 +1,201 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+var (
+	ErrReviewClosed    = errors.New("order is not open for review")
+	ErrAlreadyReviewed = errors.New("order already reviewed")
+	ErrInvalidReview   = errors.New("invalid review")
+	ErrReviewsDisabled = errors.New("reviews are disabled")
+)
+
+// Reviews — настройки отзывов клиентов. Без Links ссылка на оценку в
+// уведомления не попадает. Window — сколько после доставки принимается
+// отзыв, HalfLife — за сколько вес оценки в рейтинге падает вдвое.
+type Reviews struct {
+	Links    *customer.ReviewLinks
+	Window   time.Duration
+	HalfLife time.Duration
+}
+
+// CourierFeedback — рейтинг курьера и последние видимые отзывы о нём.
+type CourierFeedback struct {
+	Rating  float64
+	Total   int
+	Reviews []*models.Review
+}
+
+func (s *Service) UpdateReviews(reviews Reviews) {
+	s.reviews = reviews
+}
+
+// ReviewableOrder возвращает заказ, если клиент ещё может его оценить:
+// заказ доставлен курьером не раньше Window назад и отзыва по нему нет.
+func (s *Service) ReviewableOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	if s.reviews.Links == nil {
+		return nil, ErrReviewsDisabled
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.IsCancelled() || !order.IsReceived || order.RecievedAt == nil || order.CourierID == nil {
+		return nil, ErrReviewClosed
+	}
+
+	if s.reviews.Window > 0 && time.Since(*order.RecievedAt) > s.reviews.Window {
+		return nil, ErrReviewClosed
+	}
+
+	_, err = s.repo.Review.GetByOrderID(ctx, orderID)
+	switch {
+	case err == nil:
+		return nil, ErrAlreadyReviewed
+	case !errors.Is(err, interfaces.ErrNotFound):
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *Service) SubmitReview(ctx context.Context, orderID int, rating int, comment string) (*models.Review, error) {
+	comment = strings.TrimSpace(comment)
+
+	if rating < models.MinReviewRating || rating > models.MaxReviewRating {
+		return nil, fmt.Errorf("%w: rating must be from %d to %d", ErrInvalidReview, models.MinReviewRating, models.MaxReviewRating)
+	}
+
+	if utf8.RuneCountInString(comment) > models.MaxReviewComment {
+		return nil, fmt.Errorf("%w: comment is longer than %d characters", ErrInvalidReview, models.MaxReviewComment)
+	}
+
+	order, err := s.ReviewableOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &models.Review{
+		OrderID:   order.ID,
+		CourierID: order.CourierID,
+		Rating:    rating,
+		Comment:   comment,
+	}
+
+	created, err := s.repo.Review.Create(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyReviewed
+	}
+
+	metrics.Reviews.WithLabelValues(strconv.Itoa(rating)).Inc()
+	s.log.Info("Customer review received", "orderID", orderID, "courierID", *order.CourierID, "rating", rating)
+
+	// Отзыв уже сохранён, поэтому ошибка пересчёта клиенту не возвращается:
+	// рейтинг поправится при следующем отзыве.
+	if err := s.recalculateRating(ctx, *order.CourierID); err != nil {
+		s.log.Error("Failed to recalculate courier rating", "courierID", *order.CourierID, "error", err)
+	}
+
+	return review, nil
+}
+
+// SetReviewHidden скрывает отзыв от курьера и из рейтинга или возвращает
+// его обратно.
+func (s *Service) SetReviewHidden(ctx context.Context, reviewID int, hidden bool) (*models.Review, error) {
+	review, err := s.repo.Review.SetHidden(ctx, reviewID, hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Review visibility changed", "reviewID", reviewID, "orderID", review.OrderID, "hidden", hidden)
+
+	if review.CourierID == nil {
+		return review, nil
+	}
+
+	if err := s.recalculateRating(ctx, *review.CourierID); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *Service) GetCourierFeedback(ctx context.Context, chatID int64, limit int) (*CourierFeedback, error) {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	reviews, total, err := s.repo.Review.ListFiltered(ctx, models.ReviewFilter{
+		CourierID: courier.ID,
+		Page:      models.Page{Limit: limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CourierFeedback{
+		Rating:  courier.Rating,
+		Total:   total,
+		Reviews: reviews,
+	}, nil
+}
+
+// recalculateRating пересчитывает рейтинг курьера по всем видимым отзывам.
+func (s *Service) recalculateRating(ctx context.Context, courierID int) error {
+	reviews, _, err := s.repo.Review.ListFiltered(ctx, models.ReviewFilter{CourierID: courierID})
+	if err != nil {
+		return err
+	}
+
+	rating := decayedRating(reviews, time.Now(), s.reviews.HalfLife)
+	if err := s.repo.Courier.SetRating(ctx, courierID, rating); err != nil {
+		return err
+	}
+
+	s.log.Debug("Courier rating recalculated", "courierID", courierID, "rating", rating, "reviews", len(reviews))
+
+	return nil
+}
+
+// decayedRating — среднее оценок, взвешенное по давности: вес отзыва
+// возрастом halfLife вдвое меньше свежего. Так рейтинг отражает, как
+// курьер работает сейчас, а не год назад. Без отзывов рейтинг 0.
+func decayedRating(reviews []*models.Review, now time.Time, halfLife time.Duration) float64 {
+	var sum, weights float64
+
+	for _, review := range reviews {
+		weight := 1.0
+		if halfLife > 0 {
+			age := max(now.Sub(review.CreatedAt), 0)
+			weight = math.Exp2(-age.Hours() / halfLife.Hours())
+		}
+
+		sum += weight * float64(review.Rating)
+		weights += weight
+	}
+
+	if weights == 0 {
+		return 0
+	}
+
+	return math.Round(sum/weights*100) / 100
+}
@@ -0,0 +1,73 @@
+package assignment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+func TestDecayedRating(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	halfLife := 30 * 24 * time.Hour
+
+	review := func(rating int, age time.Duration) *models.Review {
+		return &models.Review{Rating: rating, CreatedAt: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name     string
+		reviews  []*models.Review
+		halfLife time.Duration
+		want     float64
+	}{
+		{
+			name: "no reviews",
+			want: 0,
+		},
+		{
+			name:     "single review",
+			reviews:  []*models.Review{review(4, 90*24*time.Hour)},
+			halfLife: halfLife,
+			want:     4,
+		},
+		{
+			name:     "same age is a plain average",
+			reviews:  []*models.Review{review(5, 0), review(4, 0), review(4, 0)},
+			halfLife: halfLife,
+			want:     4.33,
+		},
+		{
+			// Вес отзыва возрастом halfLife — 1/2: (5 + 1·0.5) / 1.5.
+			name:     "old review weighs half",
+			reviews:  []*models.Review{review(5, 0), review(1, halfLife)},
+			halfLife: halfLife,
+			want:     3.67,
+		},
+		{
+			name:     "two half-lives weigh a quarter",
+			reviews:  []*models.Review{review(5, 0), review(1, 2*halfLife)},
+			halfLife: halfLife,
+			want:     4.2,
+		},
+		{
+			name:     "future review counts as fresh",
+			reviews:  []*models.Review{review(5, -time.Hour), review(1, 0)},
+			halfLife: halfLife,
+			want:     3,
+		},
+		{
+			name:    "no decay without half-life",
+			reviews: []*models.Review{review(5, 0), review(1, 365*24*time.Hour)},
+			want:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decayedRating(tt.reviews, now, tt.halfLife); got != tt.want {
+				t.Fatalf("decayedRating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
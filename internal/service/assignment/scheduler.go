@@ -0,0 +1,86 @@
+package assignment
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+)
+
+func (s *Service) RunExpiryScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.lastExpiryRun.Store(time.Now().UnixNano())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTracked(ctx, "assignment-expiry", s.expireAssignments)
+			s.lastExpiryRun.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// runTracked выполняет проход через spawner и дожидается его: новый проход
+// не начнётся при остановке, а начатый завершится, а не оборвётся на полпути.
+func (s *Service) runTracked(ctx context.Context, name string, fn func(ctx context.Context)) {
+	done := make(chan struct{})
+
+	s.spawner.Go(ctx, name, func(ctx context.Context) {
+		defer close(done)
+		fn(ctx)
+	})
+
+	<-done
+}
+
+// LastExpiryRun — время последнего прохода планировщика истечения
+// предложений, по нему readiness считает отставание планировщика.
+func (s *Service) LastExpiryRun() time.Time {
+	if nanos := s.lastExpiryRun.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+
+	return time.Time{}
+}
+
+func (s *Service) expireAssignments(ctx context.Context) {
+	expired, err := s.repo.OrderAssignment.ListExpiredWaiting(ctx, time.Now())
+	if err != nil {
+		s.log.Error("Failed to list expired assignments", "error", err)
+		return
+	}
+
+	for _, assignment := range expired {
+		updated, err := s.repo.OrderAssignment.ExpireWaiting(ctx, assignment.ID)
+		if err != nil {
+			s.log.Error("Failed to update assignment status to expired", "assignmentID", assignment.ID, "error", err)
+			continue
+		}
+
+		if !updated {
+			continue
+		}
+
+		s.log.Info("Assignment timeout for order", "orderID", assignment.OrderID, "courierID", assignment.CourierID)
+		metrics.AssignmentOutcomes.WithLabelValues(string(models.ResponsseStatusExpired)).Inc()
+
+		s.reassignExpired(ctx, assignment)
+	}
+}
+
+func (s *Service) reassignExpired(ctx context.Context, assignment *models.OrderAssignment) {
+	ctx, span := tracing.Start(ctx, "assignment.reassignExpired", tracing.OrderID(assignment.OrderID))
+
+	_, err := s.findAndAssignCourier(ctx, assignment.OrderID)
+	tracing.End(span, err)
+
+	if err != nil {
+		s.log.Error("Failed to reassign expired order", "orderID", assignment.OrderID, "error", err)
+	}
+}
@@ -6,32 +6,66 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/CAATHARSIS/courier-bot/internal/callback"
+	"github.com/CAATHARSIS/courier-bot/internal/customer"
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/lifecycle"
+	"github.com/CAATHARSIS/courier-bot/internal/metrics"
 	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/navigation"
 	"github.com/CAATHARSIS/courier-bot/internal/repository"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// errCourierOffShift — курьер закрыл смену между выбором и записью
+// предложения; заказ уходит следующему кандидату.
+var errCourierOffShift = errors.New("courier went off shift")
+
 type Service struct {
-	repo              repository.Repository
-	log               *slog.Logger
-	botAPI            *tgbotapi.BotAPI
-	assignmentTimeout time.Duration
+	repo                  repository.Repository
+	log                   *slog.Logger
+	botAPI                *tgbotapi.BotAPI
+	spawner               lifecycle.Spawner
+	codec                 *callback.Codec
+	customers             customer.Channel
+	customerNotifications CustomerNotifications
+	reviews               Reviews
+	dailySummaryAt        time.Duration
+	assignmentTimeout     time.Duration
+	idleThreshold         time.Duration
+	holdLead              time.Duration
+	location              *time.Location
+	lastExpiryRun         atomic.Int64
 }
 
-func NewService(repo repository.Repository, botAPI *tgbotapi.BotAPI, log *slog.Logger) *Service {
+func NewService(repo repository.Repository, botAPI *tgbotapi.BotAPI, spawner lifecycle.Spawner, codec *callback.Codec, customers customer.Channel, log *slog.Logger) *Service {
 	service := &Service{
 		repo:              repo,
 		log:               log,
 		botAPI:            botAPI,
+		spawner:           spawner,
+		codec:             codec,
+		customers:         customers,
 		assignmentTimeout: 10 * time.Minute,
+		idleThreshold:     15 * time.Minute,
+		holdLead:          time.Hour,
+		location:          time.Local,
+		dailySummaryAt:    21 * time.Hour,
 	}
 
 	return service
 }
 
-func (s *Service) ProcessNewOrder(ctx context.Context, orderID int) error {
+func (s *Service) ProcessNewOrder(ctx context.Context, orderID int) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.ProcessNewOrder", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
 	s.log.Info("Proccessing new order", "orderID", orderID)
 
 	order, err := s.repo.Order.GetByID(ctx, orderID)
@@ -39,6 +73,22 @@ func (s *Service) ProcessNewOrder(ctx context.Context, orderID int) error {
 		return fmt.Errorf("failed to get order %d: %v", orderID, err)
 	}
 
+	// Событие из inbox может прийти повторно, если обработка упала уже после
+	// отправки предложения. Второе предложение тому же заказу не нужно.
+	if order.CourierID != nil {
+		s.log.Info("Order already has a courier, skipping", "orderID", orderID, "courierID", *order.CourierID)
+		return nil
+	}
+
+	live, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
+	switch {
+	case err == nil && live.CourierResponseStatus == models.ResponseStatusWaiting:
+		s.log.Info("Order already has a live offer, skipping", "orderID", orderID, "assignmentID", live.ID)
+		return nil
+	case err != nil && !errors.Is(err, interfaces.ErrNotFound):
+		return fmt.Errorf("failed to get order assignment: %v", err)
+	}
+
 	if err := s.validateOrderForAssignment(order); err != nil {
 		return fmt.Errorf("order validation failed: %v", err)
 	}
@@ -55,7 +105,10 @@ func (s *Service) ProcessNewOrder(ctx context.Context, orderID int) error {
 	return nil
 }
 
-func (s *Service) HandleCourierResponse(ctx context.Context, chatID int64, orderID int, accepted bool) error {
+func (s *Service) HandleCourierResponse(ctx context.Context, chatID int64, orderID int, accepted bool) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.HandleCourierResponse", tracing.OrderID(orderID), tracing.ChatID(chatID), attribute.Bool("assignment.accepted", accepted))
+	defer func() { tracing.End(span, err) }()
+
 	s.log.Info("Processing courier responsse", "orderID", orderID, "accepted", accepted)
 
 	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
@@ -72,43 +125,99 @@ func (s *Service) HandleCourierResponse(ctx context.Context, chatID int64, order
 		return errors.New("courier mismatch: assignment belongs to another courier")
 	}
 
+	tr := courierLocalizer(courier)
+
+	if assignment.CourierResponseStatus != models.ResponseStatusWaiting {
+		s.sendSimpleNotification(ctx, chatID, models.NotifyOrder, tr.T("offer.stale", orderID))
+		return nil
+	}
+
 	if time.Now().After(assignment.ExpiredAt) {
-		s.sendSimpleNotification(chatID, "⏰ Время для принятия заказа истекло")
-		return s.repo.OrderAssignment.UpdateStatus(ctx, orderID, models.ResponsseStatusExpired)
+		s.sendSimpleNotification(ctx, chatID, models.NotifyOrder, tr.T("offer.expired"))
+
+		// Предложение истекло, а планировщик до него ещё не дошёл: истекаем
+		// сами и переназначаем заказ, иначе он останется без курьера.
+		updated, err := s.repo.OrderAssignment.ExpireWaiting(ctx, assignment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to expire assignment: %v", err)
+		}
+
+		if updated {
+			metrics.AssignmentOutcomes.WithLabelValues(string(models.ResponsseStatusExpired)).Inc()
+			s.spawner.Go(ctx, "reassign-expired-order", func(ctx context.Context) {
+				s.reassignExpired(ctx, assignment)
+			})
+		}
+
+		return nil
 	}
 
 	status := models.ResponseStatusRejected
 	if accepted {
 		status = models.ResponseStatusAccepted
-		if err := s.repo.Order.UpdateCourierID(ctx, orderID, courier.ID); err != nil {
+	}
+
+	// Ответ пишется условно, до всех побочных эффектов: из принятия,
+	// отказа, истечения и отмены срабатывает только первое.
+	updated, err := s.repo.OrderAssignment.ResolveWaiting(ctx, assignment.ID, status)
+	if err != nil {
+		return fmt.Errorf("failed to update assignment status: %v", err)
+	}
+
+	if !updated {
+		s.sendSimpleNotification(ctx, chatID, models.NotifyOrder, tr.T("offer.stale", orderID))
+		return nil
+	}
+
+	metrics.AssignmentOutcomes.WithLabelValues(string(status)).Inc()
+
+	if accepted {
+		assigned, err := s.repo.Order.UpdateCourierID(ctx, orderID, courier.ID)
+		if err != nil {
 			return fmt.Errorf("failed to update order: %v", err)
 		}
+
+		// Заказ отменили между принятием и этой записью: отмена уже отозвала
+		// предложение и сообщила об этом курьеру.
+		if !assigned {
+			s.log.Info("Accepted order was cancelled meanwhile", "orderID", orderID, "courierID", courier.ID)
+			return nil
+		}
 		s.log.Info("Order ACCEPTED by courier", "orderID", orderID, "courierID", courier.ID)
+		metrics.TimeToAccept.Observe(time.Since(assignment.AssignedAt).Seconds())
 
 		s.repo.Courier.UpdateCurrentOrderID(ctx, chatID, orderID)
 
-		go s.sendDeliveryDetails(ctx, chatID, orderID)
+		s.spawner.Go(ctx, "send-delivery-details", func(ctx context.Context) {
+			s.sendDeliveryDetails(ctx, chatID, orderID)
+		})
+		s.notifyCustomer(ctx, orderID, models.CustomerEventAccepted)
 	} else {
 		s.log.Info("Order REJECTED by courier", "orderID", orderID, "courierID", courier.ID)
 
-		go s.findAndAssignCourier(ctx, orderID)
+		s.spawner.Go(ctx, "reassign-rejected-order", func(ctx context.Context) {
+			if _, err := s.findAndAssignCourier(ctx, orderID); err != nil {
+				s.log.Error("Failed to reassign rejected order", "orderID", orderID, "error", err)
+			}
+		})
 	}
 
-	var responseMessage string
+	responseMessage := tr.T("offer.rejected", orderID)
 	if accepted {
-		responseMessage = fmt.Sprintf("✅ Заказ #%d принят! Ожидайте детали доставки.", orderID)
-	} else {
-		responseMessage = fmt.Sprintf("❌ Вы отказались от заказа #%d.", orderID)
+		responseMessage = tr.T("offer.accepted", orderID)
 	}
 
-	if err := s.sendSimpleNotification(chatID, responseMessage); err != nil {
+	if err := s.sendSimpleNotification(ctx, chatID, models.NotifyOrder, responseMessage); err != nil {
 		s.log.Error("Failed to send response message", "error", err)
 	}
 
-	return s.repo.OrderAssignment.UpdateStatus(ctx, orderID, status)
+	return nil
 }
 
-func (s *Service) assignOrderToCourier(ctx context.Context, orderID, courierID int) (*AssignmentResult, error) {
+func (s *Service) assignOrderToCourier(ctx context.Context, orderID, courierID int) (result *AssignmentResult, err error) {
+	ctx, span := tracing.Start(ctx, "assignment.assignOrderToCourier", tracing.OrderID(orderID), attribute.Int("courier.id", courierID))
+	defer func() { tracing.End(span, err) }()
+
 	s.log.Info("Assinging order to courier", "orderID", orderID, "courierID", courierID)
 
 	order, err := s.repo.Order.GetByID(ctx, orderID)
@@ -116,6 +225,13 @@ func (s *Service) assignOrderToCourier(ctx context.Context, orderID, courierID i
 		return nil, fmt.Errorf("failed to get order: %v", err)
 	}
 
+	if order.IsCancelled() {
+		return &AssignmentResult{
+			Success:      false,
+			ErrorMessage: "Order is cancelled",
+		}, nil
+	}
+
 	if order.CourierID != nil {
 		return &AssignmentResult{
 			Success:      false,
@@ -136,21 +252,25 @@ func (s *Service) assignOrderToCourier(ctx context.Context, orderID, courierID i
 		CourierResponseStatus: models.ResponseStatusWaiting,
 	}
 
-	err = s.repo.OrderAssignment.Create(ctx, assignment)
+	created, err := s.repo.OrderAssignment.CreateOffer(ctx, assignment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create assignment: %v", err)
 	}
 
-	message := s.formatDeliveryMessage(order)
-	message.WriteString("⏰ *У вас 10 минут, чтобы принять решение*\n\n")
-	message.WriteString("Примите или отколните заказ:")
+	if !created {
+		return nil, errCourierOffShift
+	}
+
+	tr := courierLocalizer(courier)
+
+	message := s.formatDeliveryMessage(tr, order, tr.T("offer.title"))
+	message.WriteString(tr.N("offer.deadline", int(s.assignmentTimeout.Minutes())))
+	message.WriteString(tr.T("offer.prompt"))
 
-	if err := s.sendNotificationWithKeyboard(courier.ChatID, orderID, message.String()); err != nil {
+	if err := s.sendNotificationWithKeyboard(ctx, tr, courier.ChatID, orderID, message.String()); err != nil {
 		s.log.Error("Failed to send notification to courier", "courierID", courier.ID, "error", err)
 	}
 
-	go s.startAssignmentTimer(ctx, orderID, assignment.ExpiredAt)
-
 	s.log.Info("Order assigned to courier", "orderID", orderID, "courierID", courierID)
 
 	return &AssignmentResult{
@@ -159,14 +279,41 @@ func (s *Service) assignOrderToCourier(ctx context.Context, orderID, courierID i
 	}, nil
 }
 
-func (s *Service) findAndAssignCourier(ctx context.Context, orderID int) (*AssignmentResult, error) {
+func (s *Service) findAndAssignCourier(ctx context.Context, orderID int) (result *AssignmentResult, err error) {
+	ctx, span := tracing.Start(ctx, "assignment.findAndAssignCourier", tracing.OrderID(orderID))
+	defer func() { tracing.End(span, err) }()
+
 	s.log.Debug("Searching for available courier for order", "orderID", orderID)
 
-	couriers, err := s.repo.Courier.GetActiveCouriers(ctx)
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %v", err)
+	}
+
+	couriers, err := s.repo.Courier.GetActiveCouriers(ctx, time.Now().Add(-s.idleThreshold))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active couriers: %v", err)
 	}
 
+	if !order.IsCancelled() && order.CourierID == nil {
+		var releaseAt *time.Time
+		if couriers, releaseAt = s.scheduledCandidates(ctx, order, couriers); releaseAt != nil {
+			if err := s.repo.OrderAssignment.Hold(ctx, orderID, *releaseAt); err != nil {
+				return nil, err
+			}
+
+			s.log.Info("Order held until a scheduled courier is on shift", "orderID", orderID, "releaseAt", *releaseAt)
+			return &AssignmentResult{
+				Success:      false,
+				ErrorMessage: "Waiting for a scheduled courier",
+			}, nil
+		}
+	}
+
+	if _, err := s.repo.OrderAssignment.ReleaseHold(ctx, orderID); err != nil {
+		return nil, err
+	}
+
 	if len(couriers) == 0 {
 		return &AssignmentResult{
 			Success:      false,
@@ -185,9 +332,17 @@ func (s *Service) findAndAssignCourier(ctx context.Context, orderID int) (*Assig
 	}
 
 	for _, courier := range couriers {
-		if !rejectedMap[courier.ID] {
-			return s.assignOrderToCourier(ctx, orderID, courier.ID)
+		if rejectedMap[courier.ID] {
+			continue
 		}
+
+		result, err := s.assignOrderToCourier(ctx, orderID, courier.ID)
+		if errors.Is(err, errCourierOffShift) {
+			s.log.Info("Courier went off shift before the offer", "orderID", orderID, "courierID", courier.ID)
+			continue
+		}
+
+		return result, err
 	}
 
 	s.log.Warn("All acitve couriers rejected order", "orderID", orderID, "courierQuantity", len(couriers))
@@ -197,120 +352,127 @@ func (s *Service) findAndAssignCourier(ctx context.Context, orderID int) (*Assig
 	}, nil
 }
 
-func (s *Service) formatDeliveryMessage(order *models.Order) *strings.Builder {
+func (s *Service) formatDeliveryMessage(tr i18n.Localizer, order *models.Order, title string) *strings.Builder {
 	var builder strings.Builder
 
-	builder.WriteString("*Новый заказ!*\n\n")
-	builder.WriteString(fmt.Sprintf("*Адрес доставки:*\n%s, %s\n", order.Address, order.City))
+	builder.WriteString(fmt.Sprintf("*%s*\n\n", title))
+	builder.WriteString(tr.T("delivery.card.address", order.Address, order.City))
 
 	hasFlat := order.Flat.Valid && order.Flat.String != ""
 	hasEntrance := order.Entrance.Valid && order.Entrance.String != ""
 
 	if hasFlat {
-		builder.WriteString(fmt.Sprintf("*Квартира:*\n%s\n", order.Flat.String))
+		builder.WriteString(tr.T("delivery.card.flat", order.Flat.String))
 	}
 
 	if hasEntrance {
-		builder.WriteString(fmt.Sprintf("*Подъезд:*\n%s\n", order.Entrance.String))
+		builder.WriteString(tr.T("delivery.card.entrance", order.Entrance.String))
 	}
 
-	builder.WriteString(fmt.Sprintf("*Клиент:*\n%s\n", order.Name))
-	builder.WriteString(fmt.Sprintf("*Телефон:*\n%s\n", order.PhoneNumber))
-	builder.WriteString(fmt.Sprintf("*Сумма заказа:*\n%d\n\n", order.FinalPrice))
-	builder.WriteString(fmt.Sprintf("*Стоимость доставки:*\n%d\n\n", order.DeliveryPrice))
+	builder.WriteString(tr.T("delivery.card.date", s.formatDeliveryTime(tr, order.DeliveryDate)))
+	builder.WriteString(tr.T("delivery.card.customer", order.Name))
+	builder.WriteString(tr.T("delivery.card.total", order.FinalPrice))
+	builder.WriteString(tr.T("delivery.card.delivery_price", order.DeliveryPrice))
 
 	if hasFlat && !hasEntrance {
-		builder.WriteString("*Подъезд не указан, для уточнения информации свяжитесь с клиентом\n\n*")
+		builder.WriteString(tr.T("delivery.card.no_entrance"))
 	} else if hasEntrance && !hasFlat {
-		builder.WriteString("*Квартира не указана, для уточнения информации свяжитесь с клиентом\n\n*")
+		builder.WriteString(tr.T("delivery.card.no_flat"))
 	} else {
-		builder.WriteString("*Квартира и подъезд не указаны, для уточнения информации свяжитесь с клиентом\n\n*")
+		builder.WriteString(tr.T("delivery.card.no_flat_entrance"))
 	}
 
 	return &builder
 }
 
-func (s *Service) startAssignmentTimer(ctx context.Context, orderID int, expiry time.Time) {
-	duration := time.Until(expiry)
-	if duration <= 0 {
-		return
-	}
-
-	time.Sleep(duration)
-
-	assignment, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
-	if err != nil {
-		s.log.Error("Failed to get assignment for timer", "error", err)
-		return
-	}
-
-	if assignment.CourierResponseStatus == models.ResponseStatusWaiting {
-		s.log.Info("Assignment timeout for order", "orderID", orderID)
-
-		if err := s.repo.OrderAssignment.UpdateStatus(ctx, orderID, models.ResponsseStatusExpired); err != nil {
-			s.log.Error("failed to update assignment status to expired", "error", err)
-			return
-		}
-
-		go s.findAndAssignCourier(ctx, orderID)
-	}
-}
-
-func (s *Service) sendNotificationWithKeyboard(chatID int64, orderID int, message string) error {
+func (s *Service) sendNotificationWithKeyboard(ctx context.Context, tr i18n.Localizer, chatID int64, orderID int, message string) error {
 	msg := tgbotapi.NewMessage(chatID, message)
 	msg.ParseMode = "Markdown"
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Принять", fmt.Sprintf("accept_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", fmt.Sprintf("reject_%d", orderID)),
+			s.button(tr.T("button.accept"), callback.New(actionAccept, orderID)),
+			s.button(tr.T("button.reject"), callback.New(actionReject, orderID)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
+	msg.DisableNotification = s.silentOffer(ctx, chatID)
 
-	_, err := s.botAPI.Send(msg)
+	sent, err := s.send(ctx, "sendMessage", chatID, msg)
 	if err != nil {
 		s.log.Error("Failed to send message with keyboard", "chatID", chatID, "error", err)
+		metrics.NotificationFailures.WithLabelValues("offer").Inc()
 		return err
 	}
 
+	metrics.OffersSent.Inc()
+
+	s.TrackOrderMessage(ctx, orderID, chatID, sent.MessageID, models.OrderMessageOffer)
+
 	s.log.Info("Message with keyboard sent", "chatID", chatID, "orderID", orderID)
 	return nil
 }
 
-func (s *Service) sendNotificationWithDeliveryKeyboard(chatID int64, message string, orderID int, order *models.Order) error {
+func (s *Service) sendNotificationWithDeliveryKeyboard(ctx context.Context, tr i18n.Localizer, chatID int64, message string, orderID int, order *models.Order) error {
 	msg := tgbotapi.NewMessage(chatID, message)
 	msg.ParseMode = "Markdown"
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🗺️ Маршрут", fmt.Sprintf("nav_%d_%s", orderID, s.escapeAddress(order.Address))),
-			tgbotapi.NewInlineKeyboardButtonData("📞 Позвонить", fmt.Sprintf("call_%d_%s", orderID, order.PhoneNumber)),
+			s.button(tr.T("button.route_short"), callback.New(actionNavigate, orderID)),
+			s.button(tr.T("button.contact_short"), callback.New(actionContact, orderID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Доставлено", fmt.Sprintf("complete_%d", orderID)),
-			tgbotapi.NewInlineKeyboardButtonData("🚨 Проблема", fmt.Sprintf("problem_%d", orderID)),
+			s.button(tr.T("button.picked"), callback.New(actionStatusPicked, orderID)),
+			s.button(tr.T("button.on_the_way"), callback.New(actionStatusDelivering, orderID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			s.button(tr.T("button.delivered"), callback.New(actionComplete, orderID)),
+			s.button(tr.T("button.problem_short"), callback.New(actionProblem, orderID)),
 		),
 	)
 	msg.ReplyMarkup = keyboard
+	msg.DisableNotification = s.notificationSettings(ctx, chatID).Silent(models.NotifyOrder, false)
 
-	_, err := s.botAPI.Send(msg)
+	sent, err := s.send(ctx, "sendMessage", chatID, msg)
 	if err != nil {
 		s.log.Error("Failed to send delivery details", "chatID", chatID, "error", err)
+		metrics.NotificationFailures.WithLabelValues("delivery").Inc()
 		return err
 	}
 
+	s.TrackOrderMessage(ctx, orderID, chatID, sent.MessageID, models.OrderMessageDelivery)
+
 	s.log.Info("Delivery Details sent", "chatID", chatID, "orderID", orderID)
 	return nil
 }
 
-func (s *Service) sendSimpleNotification(chatID int64, message string) error {
+func (s *Service) send(ctx context.Context, method string, chatID int64, chattable tgbotapi.Chattable) (tgbotapi.Message, error) {
+	_, span := tracing.Start(ctx, "telegram."+method, tracing.ChatID(chatID))
+	sent, err := s.botAPI.Send(chattable)
+	tracing.End(span, err)
+
+	return sent, err
+}
+
+// sendSimpleNotification отправляет курьеру сообщение с учётом его
+// настроек: отключённые виды не отправляются, в тихом режиме всё уходит
+// без звука.
+func (s *Service) sendSimpleNotification(ctx context.Context, chatID int64, kind models.NotificationKind, message string) error {
+	settings := s.notificationSettings(ctx, chatID)
+	if !settings.Receives(kind) {
+		s.log.Debug("Notification disabled by courier", "chatID", chatID, "kind", kind)
+		return nil
+	}
+
 	msg := tgbotapi.NewMessage(chatID, message)
 	msg.ParseMode = "Markdown"
+	msg.DisableNotification = settings.Silent(kind, false)
 
-	_, err := s.botAPI.Send(msg)
+	_, err := s.send(ctx, "sendMessage", chatID, msg)
 	if err != nil {
 		s.log.Error("Failed to send message to courier", "chatID", chatID, "error", err)
+		metrics.NotificationFailures.WithLabelValues("message").Inc()
 		return err
 	}
 
@@ -324,10 +486,12 @@ func (s *Service) sendDeliveryDetails(ctx context.Context, chatID int64, orderID
 		return err
 	}
 
-	message := s.formatDeliveryMessage(order)
-	message.WriteString("*Используйте кнопки ниже для управления доставкой*")
+	tr := s.localizer(ctx, chatID)
 
-	return s.sendNotificationWithDeliveryKeyboard(chatID, message.String(), orderID, order)
+	message := s.formatDeliveryMessage(tr, order, tr.T("delivery.title", orderID))
+	message.WriteString(tr.T("delivery.controls"))
+
+	return s.sendNotificationWithDeliveryKeyboard(ctx, tr, chatID, message.String(), orderID, order)
 }
 
 func (s *Service) validateOrderForAssignment(order *models.Order) error {
@@ -339,6 +503,10 @@ func (s *Service) validateOrderForAssignment(order *models.Order) error {
 		return errors.New("order is not assembled")
 	}
 
+	if order.IsCancelled() {
+		return errors.New("order is cancelled")
+	}
+
 	if order.CourierID != nil {
 		return fmt.Errorf("order already assigned to courier %d", *order.CourierID)
 	}
@@ -346,24 +514,44 @@ func (s *Service) validateOrderForAssignment(order *models.Order) error {
 	return nil
 }
 
-func (s *Service) formatDeliveryTime(deliveryTime *time.Time) string {
+func (s *Service) formatDeliveryTime(tr i18n.Localizer, deliveryTime *time.Time) string {
 	if deliveryTime == nil {
-		return "не указано"
+		return tr.T("delivery.date_not_set")
 	}
-	return deliveryTime.Format("02.01.2006 в 15:04")
+	return tr.T("delivery.date", deliveryTime.Format("02.01.2006"), deliveryTime.Format("15:04"))
 }
 
-func (s *Service) escapeAddress(address string) string {
-	if len(address) > 50 {
-		address = address[:50]
+// Действия кнопок совпадают с константами пакета bot. Импортировать их
+// нельзя: bot сам зависит от сервиса.
+const (
+	actionAccept   = "accept"
+	actionReject   = "reject"
+	actionComplete = "complete"
+	actionProblem  = "problem"
+	actionNavigate = "nav"
+	actionContact  = "contact"
+
+	actionStatusPicked     = "status_picked"
+	actionStatusDelivering = "status_delivering"
+)
+
+func (s *Service) button(text string, data callback.Data) tgbotapi.InlineKeyboardButton {
+	encoded, err := s.codec.Encode(data)
+	if err != nil {
+		s.log.Error("Failed to encode callback data", "action", data.Action, "error", err)
 	}
-	return address
+
+	return tgbotapi.NewInlineKeyboardButtonData(text, encoded)
 }
 
 func (s *Service) UpdateAssignmentTimeout(timeout time.Duration) {
 	s.assignmentTimeout = timeout
 }
 
+func (s *Service) UpdateIdleThreshold(threshold time.Duration) {
+	s.idleThreshold = threshold
+}
+
 func (s *Service) GetActiveOrdersByCourier(ctx context.Context, chatID int64) ([]models.Order, error) {
 	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
 	if err != nil {
@@ -378,6 +566,29 @@ func (s *Service) GetActiveOrdersByCourier(ctx context.Context, chatID int64) ([
 	return activeOrders, nil
 }
 
+// ListCourierOrders возвращает страницу заказов курьера на вкладке tab и
+// общее число заказов на ней. Сутки считаются в часовом поясе сервиса.
+func (s *Service) ListCourierOrders(ctx context.Context, chatID int64, tab models.CourierOrderTab, page models.Page) ([]models.Order, int, error) {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	now := s.Now()
+
+	orders, total, err := s.repo.Order.ListByCourier(ctx, models.CourierOrderFilter{
+		CourierID: courier.ID,
+		Tab:       tab,
+		Since:     time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		Page:      page,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list %s orders for courier with id #%d: %v", tab, courier.ID, err)
+	}
+
+	return orders, total, nil
+}
+
 func (s *Service) GetAssignmentByOrderID(ctx context.Context, orderID int) (*models.OrderAssignment, error) {
 	assignment, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
 	if err != nil {
@@ -387,8 +598,38 @@ func (s *Service) GetAssignmentByOrderID(ctx context.Context, orderID int) (*mod
 	return assignment, nil
 }
 
-func (s *Service) UpdateOrderStatusReceived(ctx context.Context, id int, received bool) error {
-	return s.repo.Order.UpdateStatusReceived(ctx, id, received)
+func (s *Service) UpdateOrderStatusReceived(ctx context.Context, id int, received bool) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.UpdateOrderStatusReceived", tracing.OrderID(id))
+	defer func() { tracing.End(span, err) }()
+
+	order, err := s.repo.Order.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Order.UpdateStatusReceived(ctx, id, received); err != nil {
+		return err
+	}
+
+	if received && !order.IsReceived {
+		s.observeTimeToDeliver(ctx, id)
+		s.hideCustomerPhone(ctx, id)
+		s.notifyCustomer(ctx, id, models.CustomerEventDelivered)
+	}
+
+	return nil
+}
+
+func (s *Service) observeTimeToDeliver(ctx context.Context, orderID int) {
+	assignment, err := s.repo.OrderAssignment.GetByOrderID(ctx, orderID)
+	if err != nil {
+		s.log.Warn("Failed to get assignment for delivery metrics", "orderID", orderID, "error", err)
+		return
+	}
+
+	if assignment.CourierResponseStatus == models.ResponseStatusAccepted {
+		metrics.TimeToDeliver.Observe(time.Since(assignment.AssignedAt).Seconds())
+	}
 }
 
 func (s *Service) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
@@ -408,13 +649,33 @@ func (s *Service) CheckCourierByChatID(ctx context.Context, chatID int64) bool {
 }
 
 func (s *Service) CreateCourier(ctx context.Context, courier *models.Courier) error {
+	if courier.Language == "" {
+		courier.Language = string(i18n.Default)
+	}
+
 	return s.repo.Courier.Create(ctx, courier)
 }
 
-func (s *Service) UpdateCourierStatusIsActive(ctx context.Context, chatID int64, currStatus bool) error {
-	err := s.repo.Courier.UpdateCourierStatusIsActive(ctx, chatID, currStatus)
+func (s *Service) SetCourierLanguage(ctx context.Context, chatID int64, lang i18n.Lang) error {
+	return s.repo.Courier.SetLanguage(ctx, chatID, string(lang))
+}
+
+func (s *Service) SetCourierMapApp(ctx context.Context, chatID int64, app navigation.App) error {
+	return s.repo.Courier.SetMapApp(ctx, chatID, string(app))
+}
+
+// localizer возвращает локализатор на языке курьера. Если курьера найти не
+// удалось, сообщение уходит на языке по умолчанию.
+func (s *Service) localizer(ctx context.Context, chatID int64) i18n.Localizer {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
 	if err != nil {
-		s.log.Error("Internal Server Error", "Error", err)
+		s.log.Warn("Failed to get courier language", "chatID", chatID, "error", err)
+		return i18n.For(i18n.Default)
 	}
-	return err
+
+	return courierLocalizer(courier)
+}
+
+func courierLocalizer(courier *models.Courier) i18n.Localizer {
+	return i18n.For(i18n.Parse(courier.Language))
 }
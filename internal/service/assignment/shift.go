@@ -0,0 +1,295 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/i18n"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	ErrShiftAlreadyOpen    = errors.New("shift already open")
+	ErrNoOpenShift         = errors.New("no open shift")
+	ErrShiftHasUndelivered = errors.New("courier holds undelivered orders")
+	ErrAlreadyOnBreak      = errors.New("courier is already on break")
+	ErrNotOnBreak          = errors.New("courier is not on break")
+)
+
+// ShiftState — текущее состояние смены курьера: Shift == nil, если курьер
+// не на смене, Break != nil, если он на перерыве.
+type ShiftState struct {
+	Shift *models.Shift
+	Break *models.ShiftBreak
+}
+
+func (s *Service) GetShiftState(ctx context.Context, chatID int64) (*ShiftState, error) {
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	return s.shiftState(ctx, courier.ID)
+}
+
+func (s *Service) shiftState(ctx context.Context, courierID int) (*ShiftState, error) {
+	shift, err := s.repo.Shift.GetOpen(ctx, courierID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return &ShiftState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shiftBreak, err := s.repo.Shift.GetOpenBreak(ctx, shift.ID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return &ShiftState{Shift: shift}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShiftState{Shift: shift, Break: shiftBreak}, nil
+}
+
+func (s *Service) StartShift(ctx context.Context, chatID int64) (shift *models.Shift, err error) {
+	ctx, span := tracing.Start(ctx, "assignment.StartShift", tracing.ChatID(chatID))
+	defer func() { tracing.End(span, err) }()
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	if _, err := s.repo.Shift.GetOpen(ctx, courier.ID); err == nil {
+		return nil, ErrShiftAlreadyOpen
+	} else if !errors.Is(err, interfaces.ErrNotFound) {
+		return nil, err
+	}
+
+	shift, err = s.repo.Shift.Start(ctx, courier.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Courier.SetActive(ctx, courier.ID, true); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Courier.TouchLastSeen(ctx, chatID); err != nil {
+		s.log.Warn("Failed to update courier last seen", "courierID", courier.ID, "error", err)
+	}
+
+	s.log.Info("Shift started", "courierID", courier.ID, "shiftID", shift.ID)
+
+	return shift, nil
+}
+
+// EndShift закрывает смену по просьбе курьера и отправляет ему итоги.
+// Пока у курьера есть недоставленные заказы или предложения без ответа,
+// смену закрыть нельзя.
+func (s *Service) EndShift(ctx context.Context, chatID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.EndShift", tracing.ChatID(chatID))
+	defer func() { tracing.End(span, err) }()
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	shift, err := s.repo.Shift.GetOpen(ctx, courier.ID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return ErrNoOpenShift
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.endShift(ctx, courier, shift, models.ShiftEndByCourier)
+}
+
+// endShift закрывает смену, если у курьера нет недоставленных заказов и
+// предложений без ответа; проверка и закрытие — одно условное изменение.
+func (s *Service) endShift(ctx context.Context, courier *models.Courier, shift *models.Shift, reason models.ShiftEndReason) error {
+	ended, err := s.repo.Shift.EndWithoutOrders(ctx, shift.ID, reason)
+	switch {
+	case errors.Is(err, interfaces.ErrConflict):
+		return ErrShiftHasUndelivered
+	case errors.Is(err, interfaces.ErrNotFound):
+		return ErrNoOpenShift
+	case err != nil:
+		return err
+	}
+
+	s.log.Info("Shift ended", "courierID", courier.ID, "shiftID", shift.ID, "reason", reason)
+
+	summary, err := s.summarizeShift(ctx, ended)
+	if err != nil {
+		s.log.Error("Failed to build shift summary", "shiftID", shift.ID, "error", err)
+		return nil
+	}
+
+	s.sendSimpleNotification(ctx, courier.ChatID, models.NotifyShiftSummary, s.formatShiftSummary(courierLocalizer(courier), summary, reason))
+
+	return nil
+}
+
+func (s *Service) StartBreak(ctx context.Context, chatID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.StartBreak", tracing.ChatID(chatID))
+	defer func() { tracing.End(span, err) }()
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	state, err := s.shiftState(ctx, courier.ID)
+	if err != nil {
+		return err
+	}
+
+	if state.Shift == nil {
+		return ErrNoOpenShift
+	}
+	if state.Break != nil {
+		return ErrAlreadyOnBreak
+	}
+
+	if _, err := s.repo.Shift.StartBreak(ctx, state.Shift.ID); err != nil {
+		return err
+	}
+
+	s.log.Info("Shift break started", "courierID", courier.ID, "shiftID", state.Shift.ID)
+
+	return nil
+}
+
+func (s *Service) EndBreak(ctx context.Context, chatID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "assignment.EndBreak", tracing.ChatID(chatID))
+	defer func() { tracing.End(span, err) }()
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	shift, err := s.repo.Shift.GetOpen(ctx, courier.ID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return ErrNoOpenShift
+	}
+	if err != nil {
+		return err
+	}
+
+	shiftBreak, err := s.repo.Shift.EndBreak(ctx, shift.ID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return ErrNotOnBreak
+	}
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Shift break ended", "courierID", courier.ID, "shiftID", shift.ID, "duration", shiftBreak.Duration(time.Now()))
+
+	return nil
+}
+
+// RunAutoOffline закрывает смены курьеров, которые не появлялись дольше
+// idleTimeout. Курьеров с недоставленными заказами не трогаем.
+func (s *Service) RunAutoOffline(ctx context.Context, interval, idleTimeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTracked(ctx, "shift-auto-offline", func(ctx context.Context) {
+				s.closeIdleShifts(ctx, idleTimeout)
+			})
+		}
+	}
+}
+
+func (s *Service) closeIdleShifts(ctx context.Context, idleTimeout time.Duration) {
+	shifts, err := s.repo.Shift.ListIdle(ctx, time.Now().Add(-idleTimeout))
+	if err != nil {
+		s.log.Error("Failed to list idle shifts", "error", err)
+		return
+	}
+
+	for _, shift := range shifts {
+		ctx, span := tracing.Start(ctx, "assignment.closeIdleShift", attribute.Int("shift.id", shift.ID))
+
+		courier, err := s.repo.Courier.GetByID(ctx, shift.CourierID)
+		if err == nil {
+			err = s.endShift(ctx, courier, shift, models.ShiftEndByInactivity)
+		}
+
+		// Курьер успел получить заказ или сам закрыл смену после выборки.
+		if errors.Is(err, ErrShiftHasUndelivered) || errors.Is(err, ErrNoOpenShift) {
+			s.log.Debug("Idle shift no longer closable", "shiftID", shift.ID, "courierID", shift.CourierID, "reason", err)
+			err = nil
+		}
+
+		if err != nil {
+			s.log.Error("Failed to close idle shift", "shiftID", shift.ID, "courierID", shift.CourierID, "error", err)
+		}
+
+		tracing.End(span, err)
+	}
+}
+
+func (s *Service) summarizeShift(ctx context.Context, shift *models.Shift) (*models.ShiftSummary, error) {
+	end := time.Now()
+	if shift.EndedAt != nil {
+		end = *shift.EndedAt
+	}
+
+	breaks, err := s.repo.Shift.ListBreaks(ctx, shift.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.ShiftSummary{Shift: shift, Breaks: len(breaks)}
+	for _, shiftBreak := range breaks {
+		summary.OnBreak += shiftBreak.Duration(end)
+	}
+	summary.Worked = end.Sub(shift.StartedAt) - summary.OnBreak
+
+	stats, err := s.repo.CourierStats.GetByCourierID(ctx, shift.CourierID, shift.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Deliveries = stats.Deliveries
+	summary.Earnings = stats.Earnings
+
+	return summary, nil
+}
+
+func (s *Service) formatShiftSummary(tr i18n.Localizer, summary *models.ShiftSummary, reason models.ShiftEndReason) string {
+	var builder strings.Builder
+
+	builder.WriteString(tr.T("shift.summary.title", tr.T("shift.end."+string(reason))))
+	builder.WriteString(tr.T("shift.summary.started", summary.Shift.StartedAt.In(s.location).Format("02.01 15:04")))
+	builder.WriteString(tr.T("shift.summary.worked", tr.Duration(summary.Worked)))
+	if summary.Breaks > 0 {
+		builder.WriteString(tr.N("shift.summary.breaks", summary.Breaks, tr.Duration(summary.OnBreak)))
+	}
+	builder.WriteString(tr.T("shift.summary.deliveries", summary.Deliveries))
+	builder.WriteString(tr.T("shift.summary.earnings", summary.Earnings))
+
+	if reason == models.ShiftEndByInactivity {
+		builder.WriteString(tr.T("shift.summary.restart"))
+	}
+
+	return builder.String()
+}
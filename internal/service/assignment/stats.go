@@ -0,0 +1,29 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+)
+
+func (s *Service) GetCourierStats(ctx context.Context, chatID int64, period models.StatsPeriod) (*models.CourierStats, error) {
+	if !period.IsValid() {
+		return nil, fmt.Errorf("invalid stats period: %s", period)
+	}
+
+	courier, err := s.repo.Courier.GetByChatID(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier: %v", err)
+	}
+
+	stats, err := s.repo.CourierStats.GetByCourierID(ctx, courier.ID, period.Since(s.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier stats: %v", err)
+	}
+
+	stats.Period = period
+	stats.Rating = courier.Rating
+
+	return stats, nil
+}
@@ -0,0 +1,101 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/lifecycle"
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/repository"
+	"github.com/CAATHARSIS/courier-bot/internal/service/assignment"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+)
+
+type Service struct {
+	repo              repository.Repository
+	assignmentService *assignment.Service
+	spawner           lifecycle.Spawner
+	log               *slog.Logger
+	maxAttempts       int
+	batchSize         int
+	lease             time.Duration
+}
+
+func NewService(repo repository.Repository, assignmentService *assignment.Service, spawner lifecycle.Spawner, maxAttempts int, log *slog.Logger) *Service {
+	return &Service{
+		repo:              repo,
+		assignmentService: assignmentService,
+		spawner:           spawner,
+		log:               log,
+		maxAttempts:       maxAttempts,
+		batchSize:         10,
+		lease:             time.Minute,
+	}
+}
+
+func (s *Service) Record(ctx context.Context, key, eventType string, orderID int, payload []byte) (*models.InboxEvent, bool, error) {
+	event := &models.InboxEvent{
+		IdempotencyKey: key,
+		EventType:      eventType,
+		OrderID:        orderID,
+		Payload:        payload,
+		TraceParent:    tracing.Inject(ctx),
+	}
+
+	inserted, err := s.repo.Inbox.Insert(ctx, event)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record inbox event: %v", err)
+	}
+
+	if inserted {
+		s.log.Info("Inbox event recorded", "eventID", event.ID, "key", key, "orderID", orderID)
+		return event, false, nil
+	}
+
+	original, err := s.repo.Inbox.GetByKey(ctx, key)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to get original inbox event: %v", err)
+	}
+
+	s.log.Info("Duplicate inbox event", "eventID", original.ID, "key", key, "orderID", orderID)
+
+	return original, true, nil
+}
+
+type eventPayload struct {
+	Reason   string                  `json:"reason"`
+	Changes  []string                `json:"changes"`
+	Customer *models.CustomerContact `json:"customer"`
+}
+
+func (s *Service) dispatch(ctx context.Context, event *models.InboxEvent) error {
+	var payload eventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode event payload: %v", err)
+	}
+
+	// Адреса клиента сохраняем до обработки события: уведомление о
+	// назначении курьера может уйти сразу.
+	if payload.Customer != nil {
+		payload.Customer.OrderID = event.OrderID
+		if err := s.assignmentService.SaveCustomerContact(ctx, payload.Customer); err != nil {
+			return fmt.Errorf("failed to save customer contact: %v", err)
+		}
+	}
+
+	switch event.EventType {
+	case models.EventOrderCreated:
+		return s.assignmentService.ProcessNewOrder(ctx, event.OrderID)
+	case models.EventOrderCancelled:
+		return s.assignmentService.HandleOrderCancelled(ctx, event.OrderID, payload.Reason)
+	case models.EventOrderUpdated:
+		return s.assignmentService.HandleOrderUpdated(ctx, event.OrderID, payload.Changes)
+	case models.EventOrderAssembled:
+		return s.assignmentService.HandleOrderAssembled(ctx, event.OrderID)
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+}
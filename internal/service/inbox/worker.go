@@ -0,0 +1,89 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/models"
+	"github.com/CAATHARSIS/courier-bot/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (s *Service) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.processTracked(ctx)
+		}
+	}
+}
+
+// processTracked обрабатывает пачку через spawner и ждёт её окончания:
+// при остановке новые события не забираются, а уже взятые дорабатываются.
+func (s *Service) processTracked(ctx context.Context) {
+	done := make(chan struct{})
+
+	s.spawner.Go(ctx, "inbox-batch", func(ctx context.Context) {
+		defer close(done)
+		s.processBatch(ctx)
+	})
+
+	<-done
+}
+
+func (s *Service) processBatch(ctx context.Context) {
+	events, err := s.repo.Inbox.ClaimPending(ctx, s.batchSize, s.lease)
+	if err != nil {
+		s.log.Error("Failed to claim inbox events", "error", err)
+		return
+	}
+
+	for _, event := range events {
+		s.processEvent(ctx, event)
+	}
+}
+
+func (s *Service) processEvent(ctx context.Context, event *models.InboxEvent) {
+	// Обработка идёт в трассе того запроса, который положил событие в inbox.
+	ctx, span := tracing.Start(tracing.Extract(ctx, event.TraceParent), "inbox.process",
+		tracing.OrderID(event.OrderID),
+		attribute.Int("inbox.event_id", event.ID),
+		attribute.String("inbox.event_type", event.EventType),
+		attribute.Int("inbox.attempt", event.Attempts),
+	)
+
+	startTime := time.Now()
+	s.log.Info("Processing inbox event", "eventID", event.ID, "type", event.EventType, "orderID", event.OrderID, "attempt", event.Attempts)
+
+	err := s.dispatch(ctx, event)
+	tracing.End(span, err)
+
+	if err == nil {
+		if err := s.repo.Inbox.MarkDone(ctx, event.ID); err != nil {
+			s.log.Error("Failed to mark inbox event as done", "eventID", event.ID, "error", err)
+			return
+		}
+
+		s.log.Info("Inbox event processed", "eventID", event.ID, "orderID", event.OrderID, "time", time.Since(startTime))
+		return
+	}
+
+	s.log.Error("Failed to process inbox event", "eventID", event.ID, "orderID", event.OrderID, "attempt", event.Attempts, "error", err)
+
+	if event.Attempts >= s.maxAttempts {
+		if err := s.repo.Inbox.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+			s.log.Error("Failed to mark inbox event as failed", "eventID", event.ID, "error", err)
+		}
+		return
+	}
+
+	retryAfter := time.Duration(event.Attempts*event.Attempts) * 10 * time.Second
+	if err := s.repo.Inbox.MarkRetry(ctx, event.ID, err.Error(), retryAfter); err != nil {
+		s.log.Error("Failed to schedule inbox event retry", "eventID", event.ID, "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package presence
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/CAATHARSIS/courier-bot/internal/repository/interfaces"
+)
+
+// Tracker обновляет last_seen курьера не чаще раза в debounce, чтобы
+// каждое нажатие кнопки не превращалось в запись в базу.
+type Tracker struct {
+	couriers interfaces.CourierRepository
+	debounce time.Duration
+	log      *slog.Logger
+
+	mu      sync.Mutex
+	written map[int64]time.Time
+}
+
+func NewTracker(couriers interfaces.CourierRepository, debounce time.Duration, log *slog.Logger) *Tracker {
+	return &Tracker{
+		couriers: couriers,
+		debounce: debounce,
+		log:      log,
+		written:  make(map[int64]time.Time),
+	}
+}
+
+func (t *Tracker) Touch(ctx context.Context, chatID int64) {
+	now := time.Now()
+	if !t.reserve(chatID, now) {
+		return
+	}
+
+	if err := t.couriers.TouchLastSeen(ctx, chatID); err != nil {
+		t.log.Warn("Failed to update courier last seen", "chatID", chatID, "error", err)
+
+		t.mu.Lock()
+		delete(t.written, chatID)
+		t.mu.Unlock()
+	}
+}
+
+func (t *Tracker) reserve(chatID int64, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.written[chatID]; ok && now.Sub(last) < t.debounce {
+		return false
+	}
+
+	t.written[chatID] = now
+
+	if len(t.written) > 1024 {
+		for id, last := range t.written {
+			if now.Sub(last) >= t.debounce {
+				delete(t.written, id)
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,21 @@
+UPDATE order_assignments
+SET
+    courier_response_status = 'expired'
+WHERE
+    courier_response_status = 'cancelled';
+
+ALTER TYPE courier_response_status RENAME TO courier_response_status_old;
+
+CREATE TYPE courier_response_status AS ENUM (
+    'waiting',
+    'accepted',
+    'rejected',
+    'expired'
+);
+
+ALTER TABLE order_assignments
+ALTER COLUMN courier_response_status DROP DEFAULT,
+ALTER COLUMN courier_response_status TYPE courier_response_status USING courier_response_status::TEXT::courier_response_status,
+ALTER COLUMN courier_response_status SET DEFAULT 'waiting';
+
+DROP TYPE courier_response_status_old;
@@ -0,0 +1 @@
+ALTER TYPE courier_response_status ADD VALUE IF NOT EXISTS 'cancelled';
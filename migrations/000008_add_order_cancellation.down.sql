@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_messages;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS cancel_reason,
+DROP COLUMN IF EXISTS cancelled_by,
+DROP COLUMN IF EXISTS cancelled_at;
@@ -0,0 +1,15 @@
+ALTER TABLE orders
+ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS cancelled_by TEXT,
+ADD COLUMN IF NOT EXISTS cancel_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS order_messages (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL,
+    message_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_messages_order_id_idx ON order_messages (order_id);
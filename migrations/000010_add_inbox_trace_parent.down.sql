@@ -0,0 +1,2 @@
+ALTER TABLE webhook_inbox
+DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_inbox
+ADD COLUMN IF NOT EXISTS trace_parent TEXT;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS courier_shift_breaks;
+DROP TABLE IF EXISTS courier_shifts;
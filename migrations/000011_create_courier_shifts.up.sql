@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS courier_shifts (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    courier_id INTEGER NOT NULL REFERENCES couriers (id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE,
+    end_reason TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS courier_shifts_open_idx ON courier_shifts (courier_id) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS courier_shift_breaks (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    shift_id INTEGER NOT NULL REFERENCES courier_shifts (id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS courier_shift_breaks_open_idx ON courier_shift_breaks (shift_id) WHERE ended_at IS NULL;
+
+INSERT INTO courier_shifts (courier_id)
+SELECT id FROM couriers WHERE is_active = true;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS courier_availability_exceptions;
+DROP TABLE IF EXISTS courier_availability_windows;
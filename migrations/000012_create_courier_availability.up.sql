@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS courier_availability_windows (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    courier_id INTEGER NOT NULL REFERENCES couriers (id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute INTEGER NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute INTEGER NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    reminded_on DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (start_minute < end_minute)
+);
+
+CREATE INDEX IF NOT EXISTS courier_availability_windows_weekday_idx ON courier_availability_windows (weekday, start_minute);
+
+CREATE TABLE IF NOT EXISTS courier_availability_exceptions (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    courier_id INTEGER NOT NULL REFERENCES couriers (id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    available BOOLEAN NOT NULL,
+    start_minute INTEGER CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute INTEGER CHECK (end_minute BETWEEN 1 AND 1440),
+    reminded BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (
+        (available AND start_minute IS NOT NULL AND end_minute IS NOT NULL AND start_minute < end_minute)
+        OR (NOT available AND start_minute IS NULL AND end_minute IS NULL)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS courier_availability_exceptions_day_idx ON courier_availability_exceptions (day, courier_id);
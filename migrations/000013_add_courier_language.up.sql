@@ -0,0 +1,2 @@
+ALTER TABLE couriers
+ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT 'ru';
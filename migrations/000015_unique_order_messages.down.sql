@@ -0,0 +1 @@
+DROP INDEX IF EXISTS order_messages_order_chat_kind_idx;
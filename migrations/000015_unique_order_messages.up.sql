@@ -0,0 +1,8 @@
+DELETE FROM order_messages AS older
+USING order_messages AS newer
+WHERE older.order_id = newer.order_id
+    AND older.chat_id = newer.chat_id
+    AND older.kind = newer.kind
+    AND older.id < newer.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS order_messages_order_chat_kind_idx ON order_messages (order_id, chat_id, kind);
@@ -0,0 +1,2 @@
+ALTER TABLE couriers
+ADD COLUMN IF NOT EXISTS map_app TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS order_contact_messages (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    courier_id INTEGER REFERENCES couriers (id) ON DELETE SET NULL,
+    direction TEXT NOT NULL CHECK (direction IN ('to_customer', 'to_courier')),
+    template TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    delivered BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_contact_messages_order_id_idx ON order_contact_messages (order_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_notifications;
+
+DROP TABLE IF EXISTS order_customer_contacts;
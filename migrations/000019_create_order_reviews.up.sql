@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS order_reviews (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_id INTEGER NOT NULL UNIQUE REFERENCES orders (id) ON DELETE CASCADE,
+    courier_id INTEGER REFERENCES couriers (id) ON DELETE SET NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    hidden BOOLEAN NOT NULL DEFAULT false,
+    hidden_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_reviews_courier_id_idx ON order_reviews (courier_id, created_at);
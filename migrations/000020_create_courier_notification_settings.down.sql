@@ -0,0 +1 @@
+DROP TABLE IF EXISTS courier_notification_settings;
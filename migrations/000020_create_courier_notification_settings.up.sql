@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS courier_notification_settings (
+    courier_id INTEGER PRIMARY KEY REFERENCES couriers (id) ON DELETE CASCADE,
+    quiet_mode BOOLEAN NOT NULL DEFAULT false,
+    silent_offers BOOLEAN NOT NULL DEFAULT false,
+    order_ready BOOLEAN NOT NULL DEFAULT true,
+    reminders BOOLEAN NOT NULL DEFAULT true,
+    shift_summary BOOLEAN NOT NULL DEFAULT true,
+    daily_summary BOOLEAN NOT NULL DEFAULT false,
+    daily_summary_sent_on DATE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS courier_notification_settings_daily_summary_idx ON courier_notification_settings (courier_id) WHERE daily_summary;
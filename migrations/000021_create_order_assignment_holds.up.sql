@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS order_assignment_holds (
+    order_id INTEGER PRIMARY KEY REFERENCES orders (id) ON DELETE CASCADE,
+    release_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS order_assignment_holds_release_at_idx ON order_assignment_holds (release_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS callback_payloads (
+    key TEXT PRIMARY KEY,
+    payload TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS callback_payloads_expires_at_idx ON callback_payloads (expires_at);
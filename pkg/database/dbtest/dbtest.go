@@ -0,0 +1,200 @@
+// Package dbtest подключает тесты к настоящему Postgres. Адрес базы берётся
+// из TEST_DATABASE_URL; без него тесты, которым нужна база, пропускаются.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+)
+
+const EnvURL = "TEST_DATABASE_URL"
+
+// setupLockID сериализует подготовку схемы: пакеты тестируются параллельно
+// разными процессами, а CREATE TABLE IF NOT EXISTS от гонки не спасает.
+const setupLockID = 7_246_031
+
+// ordersTable — таблица заказов магазина. Бот её только читает и дополняет
+// своими колонками, миграциями она не создаётся, поэтому в тестовой базе
+// заводится здесь.
+const ordersTable = `
+	CREATE TABLE IF NOT EXISTS orders (
+		id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		name TEXT NOT NULL DEFAULT '',
+		phone_number TEXT NOT NULL DEFAULT '',
+		city TEXT NOT NULL DEFAULT '',
+		address TEXT NOT NULL DEFAULT '',
+		flat TEXT,
+		entrance TEXT,
+		delivery_price INTEGER NOT NULL DEFAULT 0,
+		first_price INTEGER NOT NULL DEFAULT 0,
+		final_price INTEGER NOT NULL DEFAULT 0,
+		paid_price INTEGER NOT NULL DEFAULT 0,
+		bonus_accrual_percentage INTEGER NOT NULL DEFAULT 0,
+		received_bonuses INTEGER NOT NULL DEFAULT 0,
+		lost_bonuses INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		delivery_date TIMESTAMP WITH TIME ZONE,
+		received_at TIMESTAMP WITH TIME ZONE,
+		is_paid BOOLEAN NOT NULL DEFAULT false,
+		is_delivery BOOLEAN NOT NULL DEFAULT true,
+		is_assembled BOOLEAN,
+		is_received BOOLEAN NOT NULL DEFAULT false,
+		payment_url TEXT,
+		courier_id INTEGER
+	)
+`
+
+var (
+	seq       atomic.Int64
+	setupOnce sync.Once
+	setupErr  error
+)
+
+// Open возвращает подключение к тестовой базе с применёнными миграциями.
+// Каждый вызов открывает отдельный пул, так что два Open в одном тесте
+// ведут себя как два инстанса приложения.
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+
+	url := Lookup(t)
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping test database: %v", err)
+	}
+
+	return db
+}
+
+// Lookup возвращает адрес тестовой базы, один раз на процесс готовит в ней
+// схему и пропускает тест, если база не задана.
+func Lookup(t testing.TB) string {
+	t.Helper()
+
+	url := os.Getenv(EnvURL)
+	if url == "" {
+		t.Skipf("%s is not set", EnvURL)
+	}
+
+	setupOnce.Do(func() { setupErr = setup(url) })
+	if setupErr != nil {
+		t.Fatalf("failed to prepare test database: %v", setupErr)
+	}
+
+	return url
+}
+
+// Unique возвращает число, не повторяющееся между тестами и их запусками:
+// им заполняют telegram_id, chat_id и прочие уникальные поля.
+func Unique() int64 {
+	return time.Now().UnixNano()/1000 + seq.Add(1)
+}
+
+func setup(url string) error {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, setupLockID); err != nil {
+		return fmt.Errorf("failed to lock schema setup: %v", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, setupLockID)
+
+	if _, err := conn.ExecContext(ctx, ordersTable); err != nil {
+		return fmt.Errorf("failed to create orders table: %v", err)
+	}
+
+	migrationDB, err := sql.Open("postgres", url)
+	if err != nil {
+		return fmt.Errorf("failed to open migration database: %v", err)
+	}
+
+	driver, err := postgres.WithInstance(migrationDB, &postgres.Config{})
+	if err != nil {
+		migrationDB.Close()
+		return fmt.Errorf("failed to create migration runner: %v", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsDir(), "postgres", driver)
+	if err != nil {
+		migrationDB.Close()
+		return fmt.Errorf("failed to create migrate instance: %v", err)
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run migrations: %v", err)
+	}
+
+	return nil
+}
+
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "migrations")
+}
+
+// InsertCourier заводит курьера с уникальными telegram_id и chat_id.
+func InsertCourier(t testing.TB, db *sql.DB) (id int, chatID int64) {
+	t.Helper()
+
+	chatID = Unique()
+
+	err := db.QueryRow(`
+		INSERT INTO couriers (telegram_id, chat_id, name, phone, is_active, last_seen)
+		VALUES ($1, $1, 'Test Courier', '9990000000', true, NOW())
+		RETURNING id
+	`, chatID).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to insert courier: %v", err)
+	}
+
+	return id, chatID
+}
+
+// InsertOrder заводит оплаченный собранный заказ, ещё не назначенный курьеру.
+func InsertOrder(t testing.TB, db *sql.DB) int {
+	t.Helper()
+
+	var id int
+
+	err := db.QueryRow(`
+		INSERT INTO orders (name, phone_number, city, address, is_paid, is_assembled, delivery_date)
+		VALUES ('Test Customer', '9991112233', 'Test City', 'Test Street, 1', true, true, NOW() + INTERVAL '1 hour')
+		RETURNING id
+	`).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to insert order: %v", err)
+	}
+
+	return id
+}
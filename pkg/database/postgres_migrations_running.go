@@ -4,12 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+const migrationsDir = "../../migrations"
+
 func RunMigrations(db *sql.DB, log *slog.Logger) error {
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
@@ -17,7 +22,7 @@ func RunMigrations(db *sql.DB, log *slog.Logger) error {
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://../../migrations",
+		"file://"+migrationsDir,
 		"postgres",
 		driver,
 	)
@@ -40,3 +45,29 @@ func RunMigrations(db *sql.DB, log *slog.Logger) error {
 
 	return nil
 }
+
+// LatestMigrationVersion возвращает номер самой свежей миграции в каталоге,
+// то есть версию схемы, которую ожидает текущий бинарник.
+func LatestMigrationVersion() (uint, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations dir: %v", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		if !found || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		latest = max(latest, uint(version))
+	}
+
+	return latest, nil
+}